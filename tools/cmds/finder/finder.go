@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
@@ -11,6 +13,7 @@ import (
 	"github.com/66james99/gig-calendar/internal/dbcollection"
 	"github.com/66james99/gig-calendar/internal/metadata"
 	"github.com/66james99/gig-calendar/internal/metadata/images"
+	"github.com/66james99/gig-calendar/internal/metadata/performers"
 	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
 )
@@ -38,6 +41,14 @@ func parseFlags(source string, args []string) (interface{}, error) {
 	pattern := fs.String("pattern", "", "Pattern to extract performer, promoter and venue from directory path (images only)")
 	incParent := fs.Bool("include_parent", false, "Include the last directory in the root directory in the path use of metadata (images only)")
 	ignoreDirs := fs.String("ignore_dirs", "", "Comma separated list of strings to ignore in paths (images only)")
+	city := fs.String("city", "", "City of the venues in directories whose pattern has no %C token (images only)")
+	headlinerLast := fs.Bool("headliner_last", false, "Performers are listed with the headliner last rather than first (images only)")
+	history := fs.Bool("history", true, "Boost candidates that past events connect with the rest of the directory (images only)")
+	historyVenue := fs.Int("history_venue", metadata.DefaultHistoryBoost.Venue, "Boost for a performer who has played the matched venue before (images only)")
+	historyBill := fs.Int("history_bill", metadata.DefaultHistoryBoost.Bill, "Boost for a performer who has shared a bill with another matched performer (images only)")
+	historyPromoter := fs.Int("history_promoter", metadata.DefaultHistoryBoost.Promoter, "Boost for a venue that has hosted events by a matched promoter (images only)")
+	historyMax := fs.Int("history_max", metadata.DefaultHistoryBoost.Max, "Limit on the total history boost given to a single candidate (images only)")
+	eventType := fs.Int("event_type", 0, "Write an event of this event type ID for each consistent directory, unless --dryrun is set (images only)")
 
 	// Custom usage message
 	fs.Usage = func() {
@@ -73,6 +84,16 @@ func parseFlags(source string, args []string) (interface{}, error) {
 			ignoreList = strings.Split(*ignoreDirs, ",")
 		}
 
+		var historyBoost metadata.HistoryBoost
+		if *history {
+			historyBoost = metadata.HistoryBoost{Venue: *historyVenue, Bill: *historyBill, Promoter: *historyPromoter, Max: *historyMax}
+			var settingErr *metadata.SettingError
+			// The scan checks the boosts again against the settings stored for each index
+			if err := historyBoost.Validate(metadata.DefaultMatchSettings); errors.As(err, &settingErr) {
+				return nil, fmt.Errorf("invalid --%s value: %s", settingErr.Field, settingErr.Message)
+			}
+		}
+
 		return metadata.ImagesConfig{
 			BaseConfig:    base,
 			DateFromExif:  *dateFromExif,
@@ -80,6 +101,10 @@ func parseFlags(source string, args []string) (interface{}, error) {
 			Pattern:       *pattern,
 			IncludeParent: *incParent,
 			IgnoreDirs:    ignoreList,
+			DefaultCity:   *city,
+			HeadlinerLast: *headlinerLast,
+			History:       historyBoost,
+			EventType:     int32(*eventType),
 		}, nil
 	case "tickets":
 		return metadata.TicketsConfig{BaseConfig: base}, nil
@@ -92,7 +117,7 @@ func parseFlags(source string, args []string) (interface{}, error) {
 
 func validateFlags(source string, fs *flag.FlagSet) error {
 	validFlagsBySource := map[string][]string{
-		"images":  {"dryrun", "verbose", "debug", "date_from_exif", "rootdir", "pattern", "include_parent", "ignore_dirs"},
+		"images":  {"dryrun", "verbose", "debug", "date_from_exif", "rootdir", "pattern", "include_parent", "ignore_dirs", "city", "headliner_last", "history", "history_venue", "history_bill", "history_promoter", "history_max", "event_type"},
 		"tickets": {"dryrun", "verbose", "debug"},
 		"info":    {"dryrun", "verbose", "debug"},
 	}
@@ -137,20 +162,39 @@ func main() {
 		if err == nil {
 			defer db.Close()
 			cfg.Queries = database.New(db)
+		} else if cfg.Debug {
+			fmt.Printf("Notice: No database connection, matching disabled: %v\n", err)
 		}
-		patternsArray, err := dbcollection.NewDBArray(context.Background(), cfg.Queries.LastModifiedPatternConsts, cfg.Queries.GetPatternConsts)
-		if err == nil {
-			cfg.Patterns = patternsArray
-		} else {
-			fmt.Printf("Error: %v\n", err)
-			return
-		}
-		patterns := cfg.Patterns.Get()
-		if cfg.Debug {
-			fmt.Printf("Patterns being used:\n")
-			for _, p := range patterns {
-				fmt.Printf("Pattern: %s\n", p)
+
+		if cfg.Queries != nil {
+			patternsArray, err := dbcollection.NewDBArray(context.Background(), cfg.Queries.LastModifiedPatternConsts, cfg.Queries.GetPatternConsts)
+			if err == nil {
+				cfg.Patterns = patternsArray
+			} else {
+				fmt.Printf("Error: %v\n", err)
+				return
 			}
+			patterns := cfg.Patterns.Get()
+			if cfg.Debug {
+				fmt.Printf("Patterns being used:\n")
+				for _, p := range patterns {
+					fmt.Printf("Pattern: %s\n", p)
+				}
+			}
+
+			stageRoles, err := performers.NewStageRoleMap(context.Background(), cfg.Queries)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+			cfg.StageRoles = stageRoles
+
+			indexes, err := images.NewMatchIndexes(context.Background(), cfg.Queries)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+			cfg.Indexes = indexes
 		}
 
 		if cfg.Debug {
@@ -166,11 +210,30 @@ func main() {
 
 		if cfg.Verbose {
 			for _, s := range result.Successes {
-				fmt.Printf("Parsed Location: \"%s\" ->\n Date: %04d-%02d-%02d\n Venue: %s (Match: %s, Conf: %d%%)\n Performers: %v\n Promoters: %v\n\n",
-					s.Directory, s.Year, s.Month, s.Day, s.Venue.Name, s.Venue.Match, s.Venue.Confidence, s.Performers, s.Promoters)
+				fmt.Printf("Parsed Location: \"%s\" ->\n Date: %04d-%02d-%02d\n", s.Directory, s.Year, s.Month, s.Day)
+				fmt.Printf(" Venue: %s (Match: %s, Conf: %d%%)\n  Why: %s\n", s.Venue.Name, s.Venue.Match, s.Venue.Confidence, s.Venue.Explanation)
+				if s.Venue.Boost != nil {
+					fmt.Printf("  Boost: %s\n", s.Venue.Boost)
+				}
+				for _, group := range s.Performers {
+					for _, p := range group {
+						fmt.Printf(" Performer: %s (Match: %s, Conf: %d%%)\n  Why: %s\n", p.Name, p.Match, p.Confidence, p.Explanation)
+						if p.Boost != nil {
+							fmt.Printf("  Boost: %s\n", p.Boost)
+						}
+					}
+				}
+				for _, p := range s.Promoters {
+					fmt.Printf(" Promoter: %s (Match: %s, Conf: %d%%)\n  Why: %s\n", p.Name, p.Match, p.Confidence, p.Explanation)
+				}
+				fmt.Println()
 			}
 		}
 
+		if cfg.EventType != 0 && cfg.Queries != nil {
+			writeEvents(db, cfg, result)
+		}
+
 		fmt.Printf("\n--- Parsing Summary ---\n")
 		fmt.Printf("Successfully parsed: %d\n", result.SuccessCount)
 		fmt.Printf("Inconsistent data:   %d\n", result.InconsistentCount)
@@ -184,3 +247,47 @@ func main() {
 		fmt.Printf("Source: %s\nDryrun: %v\nVerbose: %v\nDebug: %v\n", cfg.Source, cfg.DryRun, cfg.Verbose, cfg.Debug)
 	}
 }
+
+// writeEvents creates an event, with its lineup and promoters, for each consistent
+// directory of a scan, or with --dryrun reports the directories it would create them for.
+func writeEvents(db *sql.DB, cfg metadata.ImagesConfig, result images.ScanResult) {
+	ctx := context.Background()
+	written, skipped := 0, 0
+	for _, s := range result.Successes {
+		if cfg.DryRun {
+			if s.Consistent {
+				fmt.Printf("Would write event for %s\n", s.Directory)
+				written++
+			}
+			continue
+		}
+
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		event, err := images.WriteEvent(ctx, cfg.Queries.WithTx(tx), s, cfg.EventType)
+		if err == nil {
+			err = tx.Commit()
+		}
+		if err != nil {
+			tx.Rollback()
+			skipped++
+			if cfg.Verbose || cfg.Debug {
+				fmt.Printf("Skipped %s: %v\n", s.Directory, err)
+			}
+			continue
+		}
+		written++
+		if cfg.Verbose {
+			fmt.Printf("Wrote event %d for %s\n", event.ID, s.Directory)
+		}
+	}
+	if cfg.DryRun {
+		fmt.Printf("Events to write:     %d\n", written)
+		return
+	}
+	fmt.Printf("Events written:      %d\n", written)
+	fmt.Printf("Events skipped:      %d\n", skipped)
+}
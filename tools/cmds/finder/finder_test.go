@@ -181,6 +181,18 @@ func TestParseFlags_Errors(t *testing.T) {
 			args:    []string{"--pattern=%y%m"},
 			wantErr: "invalid --pattern value: invalid pattern: placeholders must be separated",
 		},
+		{
+			name:    "Negative history boost",
+			source:  "images",
+			args:    []string{"--history_bill=-5"},
+			wantErr: "invalid --history_bill value: must not be negative",
+		},
+		{
+			name:    "History limit lifting phonetic matches over fuzzy aliases",
+			source:  "images",
+			args:    []string{"--history_max=5"},
+			wantErr: "invalid --history_max value: must be below the smallest gap of 5 between match stages",
+		},
 	}
 
 	for _, tt := range tests {
@@ -9,18 +9,29 @@ import {
     getFilteredRowModel,
     createColumnHelper,
 } from '@tanstack/react-table';
-import { TableName, ScanResult, MatchedResult } from './types';
+import { TableName, ScanResult, MatchedResult, MatchExplanation, MatchBoost } from './types';
 import { SortableHeader } from './TableElements';
 
 const getConfidenceStyle = (conf: number) => {
     let color = 'red';
     if (conf === 100) color = 'green';
-    else if (conf === 75) color = 'blue';
-    else if (conf === 50) color = 'orange';
-    else if (conf === 25) color = 'gray';
+    else if (conf >= 75) color = 'blue';
+    else if (conf >= 50) color = 'orange';
+    else if (conf === 40) color = 'teal';
+    else if (conf >= 25) color = 'gray';
+    else if (conf >= 20) color = 'purple';
     return { color, fontWeight: conf > 0 ? 'bold' : 'normal' as any, cursor: 'pointer', textDecoration: 'underline dotted' };
 };
 
+const describeMatch = (name: string, confidence: number, explanation?: MatchExplanation, boost?: MatchBoost) => {
+    if (!explanation) return confidence !== 100 ? `Original: ${name}` : '';
+    if (explanation.stage === 'inferred') return `Inferred: ${explanation.compared} from ${explanation.input}`;
+    let text = `Original: ${name}\nStage: ${explanation.stage}\nCompared: "${explanation.input}" vs "${explanation.compared}" (distance ${explanation.distance})`;
+    if (explanation.alias_id) text += `\nAlias #${explanation.alias_id}: ${explanation.alias}`;
+    if (boost) text += `\nBoost +${boost.amount}: ${boost.reasons.join('; ')}`;
+    return text;
+};
+
 export const PreviewScan: React.FC<{ 
     result: ScanResult; 
     isDebug: boolean;
@@ -52,7 +63,7 @@ export const PreviewScan: React.FC<{
                                     {p.pattern && <span> {p.pattern} </span>}
                                     <span 
                                         style={getConfidenceStyle(conf)} 
-                                        title={conf !== 100 ? `Original: ${p.name}` : ''}
+                                        title={describeMatch(p.name, conf, p.explanation, p.boost)}
                                         onClick={() => onNavigate('performers', p.name)}
                                     >
                                         {display}
@@ -74,7 +85,7 @@ export const PreviewScan: React.FC<{
                 return (
                     <span 
                         style={getConfidenceStyle(venue.confidence)}
-                        title={venue.confidence !== 100 ? `Original: ${venue.name}` : ''}
+                        title={describeMatch(venue.name, venue.confidence, venue.explanation, venue.boost)}
                         onClick={() => onNavigate('venues', venue.name)}
                     >
                         {venue.confidence > 0 ? venue.match : venue.name}
@@ -94,7 +105,7 @@ export const PreviewScan: React.FC<{
                                 ...getConfidenceStyle(p.confidence),
                                 textDecoration: p.festival ? 'underline' : 'underline dotted'
                             }}
-                            title={p.confidence !== 100 ? `Original: ${p.name}` : ''}
+                            title={describeMatch(p.name, p.confidence, p.explanation)}
                             onClick={() => onNavigate('promoters', p.name)}
                         >
                             {p.confidence > 0 ? p.match : p.name}
@@ -109,7 +120,10 @@ export const PreviewScan: React.FC<{
             header: 'OK',
             filterFn: 'equals',
             cell: info => (
-                <div style={{ textAlign: 'center', color: info.getValue() ? 'green' : 'red', fontWeight: 'bold' }}>
+                <div
+                    style={{ textAlign: 'center', color: info.getValue() ? 'green' : 'red', fontWeight: 'bold' }}
+                    title={info.row.original.ambiguities?.map(a => `Ambiguous ${a.type} "${a.name}": ${a.contenders.join(', ')}`).join('\n') || ''}
+                >
                     {info.getValue() ? '✓' : '✗'}
                 </div>
             )
@@ -62,17 +62,35 @@ export interface ColumnConfig {
     type: 'text' | 'number' | 'date' | 'boolean';
 }
 
+export interface MatchExplanation {
+    stage: 'exact' | 'alias' | 'fuzzy-name' | 'fuzzy-alias' | 'phonetic' | 'inferred';
+    alias_id?: number;
+    alias?: string;
+    distance: number;
+    input: string;
+    compared: string;
+}
+
+export interface MatchBoost {
+    amount: number;
+    reasons: string[];
+}
+
 export interface PerformerMatchResult {
     name: string;
     match?: string;
     confidence: number;
     pattern?: string;
+    explanation?: MatchExplanation;
+    boost?: MatchBoost;
 }
 
 export interface MatchedVenue {
     name: string;
     match: string;
     confidence: number;
+    explanation?: MatchExplanation;
+    boost?: MatchBoost;
 }
 
 export interface PromoterMatchResult {
@@ -80,6 +98,14 @@ export interface PromoterMatchResult {
     match: string;
     confidence: number;
     festival?: boolean;
+    inferred?: boolean;
+    explanation?: MatchExplanation;
+}
+
+export interface Ambiguity {
+    type: 'venue' | 'performer' | 'promoter';
+    name: string;
+    contenders: string[];
 }
 
 export interface MatchedResult {
@@ -90,6 +116,7 @@ export interface MatchedResult {
     performers?: PerformerMatchResult[][];
     venue?: MatchedVenue;
     promoters?: PromoterMatchResult[];
+    ambiguities?: Ambiguity[];
     consistent: boolean;
 }
 
@@ -1,4 +1,6 @@
 import type { EventType } from './types.js';
+import { errorMessage } from '../shared/errors.js';
+import { ifMatch } from '../shared/version.js';
 
 const API_BASE = '/api/v1';
 
@@ -19,28 +21,28 @@ export async function createEventType(payload: { name: string }): Promise<EventT
     });
     if (!response.ok) {
         const errorData = await response.json().catch(() => ({}));
-        throw new Error(errorData.error || `Failed to create event type: ${response.statusText}`);
+        throw new Error(errorMessage(errorData) || `Failed to create event type: ${response.statusText}`);
     }
     return response.json();
 }
 
-export async function updateEventType(id: number, payload: { name: string }): Promise<EventType> {
+export async function updateEventType(id: number, payload: { name: string }, updated: string): Promise<EventType> {
     const response = await fetch(`${API_BASE}/event_types/${id}`, {
         method: 'PUT',
-        headers: { 'Content-Type': 'application/json' },
+        headers: { 'Content-Type': 'application/json', ...ifMatch(updated) },
         body: JSON.stringify(payload)
     });
     if (!response.ok) {
         const errorData = await response.json().catch(() => ({}));
-        throw new Error(errorData.error || `Failed to update event type: ${response.statusText}`);
+        throw new Error(errorMessage(errorData) || `Failed to update event type: ${response.statusText}`);
     }
     return response.json();
 }
 
-export async function deleteEventType(id: number): Promise<void> {
-    const response = await fetch(`${API_BASE}/event_types/${id}`, { method: 'DELETE' });
+export async function deleteEventType(id: number, updated: string): Promise<void> {
+    const response = await fetch(`${API_BASE}/event_types/${id}`, { method: 'DELETE', headers: ifMatch(updated) });
     if (!response.ok) {
         const errorData = await response.json().catch(() => ({}));
-        throw new Error(errorData.error || `Failed to delete event type: ${response.statusText}`);
+        throw new Error(errorMessage(errorData) || `Failed to delete event type: ${response.statusText}`);
     }
 }
\ No newline at end of file
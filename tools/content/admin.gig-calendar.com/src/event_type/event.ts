@@ -30,6 +30,8 @@ export async function handleTableClick(event: MouseEvent) {
 
     const idStr = row.dataset.id;
     const id = idStr ? parseInt(idStr, 10) : 0;
+    // Updates and deletes send the version of the row shown, so they fail if it has changed since
+    const updated = eventTypesCache.find(i => i.ID === id)?.Updated ?? '';
 
     if (btn.classList.contains('edit-btn')) {
         const item = eventTypesCache.find(i => i.ID === id);
@@ -38,7 +40,7 @@ export async function handleTableClick(event: MouseEvent) {
     } else if (btn.classList.contains('delete-btn')) {
         if (confirm('Are you sure you want to delete this event type?')) {
             try {
-                await deleteEventType(id);
+                await deleteEventType(id, updated);
                 await refreshEventTypes();
             } catch (e) {
                 alert((e as Error).message);
@@ -73,7 +75,7 @@ export async function handleTableClick(event: MouseEvent) {
             if (btn.classList.contains('add-btn')) {
                 await createEventType(payload);
             } else {
-                await updateEventType(id, payload);
+                await updateEventType(id, payload, updated);
             }
             await refreshEventTypes();
         } catch (e) {
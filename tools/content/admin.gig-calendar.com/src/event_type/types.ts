@@ -2,6 +2,8 @@ export interface EventType {
     ID: number;
     Uuid: string;
     Name: string;
+    Created: string;
+    Updated: string;
 }
 
 export type EventTypeSortableColumn = 'ID' | 'Uuid' | 'Name';
@@ -1,4 +1,6 @@
 import type { Festival, FestivalPayload, Promoter } from './types.js';
+import { errorMessage } from '../shared/errors.js';
+import { ifMatch } from '../shared/version.js';
 
 const API_BASE = '/api/v1';
 
@@ -30,32 +32,34 @@ export async function createFestival(payload: FestivalPayload): Promise<Festival
     });
     if (!response.ok) {
         const errorData = await response.json().catch(() => ({}));
-        throw new Error(errorData.error || `Failed to create festival: ${response.statusText}`);
+        throw new Error(errorMessage(errorData) || `Failed to create festival: ${response.statusText}`);
     }
     return response.json();
 }
 
-export async function updateFestival(id: number, payload: FestivalPayload): Promise<Festival> {
+export async function updateFestival(id: number, payload: FestivalPayload, updated: string): Promise<Festival> {
     const response = await fetch(`${API_BASE}/festivals/${id}`, {
         method: 'PUT',
         headers: {
             'Content-Type': 'application/json',
+            ...ifMatch(updated),
         },
         body: JSON.stringify(payload),
     });
     if (!response.ok) {
         const errorData = await response.json().catch(() => ({}));
-        throw new Error(errorData.error || `Failed to update festival: ${response.statusText}`);
+        throw new Error(errorMessage(errorData) || `Failed to update festival: ${response.statusText}`);
     }
     return response.json();
 }
 
-export async function deleteFestival(id: number): Promise<void> {
+export async function deleteFestival(id: number, updated: string): Promise<void> {
     const response = await fetch(`${API_BASE}/festivals/${id}`, {
         method: 'DELETE',
+        headers: ifMatch(updated),
     });
     if (!response.ok) {
         const errorData = await response.json().catch(() => ({}));
-        throw new Error(errorData.error || `Failed to delete festival: ${response.statusText}`);
+        throw new Error(errorMessage(errorData) || `Failed to delete festival: ${response.statusText}`);
     }
 }
\ No newline at end of file
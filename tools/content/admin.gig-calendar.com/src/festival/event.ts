@@ -33,6 +33,8 @@ export async function handleTableClick(event: MouseEvent) {
 
     const idStr = row.dataset.id;
     const id = idStr ? parseInt(idStr, 10) : 0;
+    // Updates and deletes send the version of the row shown, so they fail if it has changed since
+    const updated = aliasesCache.find(i => i.ID === id)?.Updated ?? '';
 
     if (btn.classList.contains('edit-btn')) {
         const item = aliasesCache.find(i => i.ID === id);
@@ -41,7 +43,7 @@ export async function handleTableClick(event: MouseEvent) {
     } else if (btn.classList.contains('delete-btn')) {
         if (confirm('Are you sure you want to delete this festival?')) {
             try {
-                await deleteFestival(id);
+                await deleteFestival(id, updated);
                 await refreshFestivals();
             } catch (e) {
                 alert((e as Error).message);
@@ -94,7 +96,7 @@ export async function handleTableClick(event: MouseEvent) {
             if (btn.classList.contains('add-btn')) {
                 await createFestival(payload);
             } else {
-                await updateFestival(id, payload);
+                await updateFestival(id, payload, updated);
             }
             await refreshFestivals();
         } catch (e) {
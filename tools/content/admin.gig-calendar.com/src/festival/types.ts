@@ -6,6 +6,8 @@ export interface Festival {
     EndDate: string;
     Description: string;
     Uuid: string;
+    Created: string;
+    Updated: string;
 }
 
 export interface Promoter {
@@ -1,4 +1,6 @@
 import type { FestivalAlias, FestivalAliasPayload, Festival, Promoter } from './types.js';
+import { errorMessage } from '../shared/errors.js';
+import { ifMatch } from '../shared/version.js';
 
 const API_BASE = '/api/v1';
 
@@ -39,32 +41,34 @@ export async function createFestivalAlias(payload: FestivalAliasPayload): Promis
     });
     if (!response.ok) {
         const errorData = await response.json().catch(() => ({}));
-        throw new Error(errorData.error || `Failed to create festival alias: ${response.statusText}`);
+        throw new Error(errorMessage(errorData) || `Failed to create festival alias: ${response.statusText}`);
     }
     return response.json();
 }
 
-export async function updateFestivalAlias(id: number, payload: FestivalAliasPayload): Promise<FestivalAlias> {
+export async function updateFestivalAlias(id: number, payload: FestivalAliasPayload, updated: string): Promise<FestivalAlias> {
     const response = await fetch(`${API_BASE}/festival_aliases/${id}`, {
         method: 'PUT',
         headers: {
             'Content-Type': 'application/json',
+            ...ifMatch(updated),
         },
         body: JSON.stringify(payload),
     });
     if (!response.ok) {
         const errorData = await response.json().catch(() => ({}));
-        throw new Error(errorData.error || `Failed to update festival alias: ${response.statusText}`);
+        throw new Error(errorMessage(errorData) || `Failed to update festival alias: ${response.statusText}`);
     }
     return response.json();
 }
 
-export async function deleteFestivalAlias(id: number): Promise<void> {
+export async function deleteFestivalAlias(id: number, updated: string): Promise<void> {
     const response = await fetch(`${API_BASE}/festival_aliases/${id}`, {
         method: 'DELETE',
+        headers: ifMatch(updated),
     });
     if (!response.ok) {
         const errorData = await response.json().catch(() => ({}));
-        throw new Error(errorData.error || `Failed to delete festival alias: ${response.statusText}`);
+        throw new Error(errorMessage(errorData) || `Failed to delete festival alias: ${response.statusText}`);
     }
 }
\ No newline at end of file
@@ -32,6 +32,8 @@ export async function handleTableClick(event: MouseEvent) {
 
     const idStr = row.dataset.id;
     const id = idStr ? parseInt(idStr, 10) : 0;
+    // Updates and deletes send the version of the row shown, so they fail if it has changed since
+    const updated = aliasesCache.find(i => i.ID === id)?.Updated ?? '';
 
     if (btn.classList.contains('edit-btn')) {
         const item = aliasesCache.find(i => i.ID === id);
@@ -40,7 +42,7 @@ export async function handleTableClick(event: MouseEvent) {
     } else if (btn.classList.contains('delete-btn')) {
         if (confirm('Are you sure you want to delete this alias?')) {
             try {
-                await deleteFestivalAlias(id);
+                await deleteFestivalAlias(id, updated);
                 await refreshAliases();
             } catch (e) {
                 alert((e as Error).message);
@@ -83,7 +85,7 @@ export async function handleTableClick(event: MouseEvent) {
             if (btn.classList.contains('add-btn')) {
                 await createFestivalAlias(payload);
             } else {
-                await updateFestivalAlias(id, payload);
+                await updateFestivalAlias(id, payload, updated);
             }
             await refreshAliases();
         } catch (e) {
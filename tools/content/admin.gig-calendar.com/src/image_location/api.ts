@@ -1,4 +1,6 @@
 import type { ImageLocation, ImageLocationPayload, ScanResult } from './types.js';
+import { errorMessage } from '../shared/errors.js';
+import { ifMatch } from '../shared/version.js';
 
 const API_BASE_URL = 'http://localhost:8080/api/v1';
 
@@ -19,32 +21,33 @@ export async function createImageLocation(payload: ImageLocationPayload): Promis
     });
     if (!response.ok) {
         const err = await response.json();
-        throw new Error(err.error || 'Failed to create image location');
+        throw new Error(errorMessage(err) || 'Failed to create image location');
     }
     return response.json();
 }
 
-export async function updateImageLocation(id: number, payload: ImageLocationPayload): Promise<ImageLocation> {
+export async function updateImageLocation(id: number, payload: ImageLocationPayload, updated: string): Promise<ImageLocation> {
     const response = await fetch(`${API_BASE_URL}/image_locations/${id}`, {
         method: 'PUT',
-        headers: { 'Content-Type': 'application/json' },
+        headers: { 'Content-Type': 'application/json', ...ifMatch(updated) },
         body: JSON.stringify(payload),
     });
     if (!response.ok) {
         const err = await response.json();
-        throw new Error(err.error || 'Failed to update image location');
+        throw new Error(errorMessage(err) || 'Failed to update image location');
     }
     return response.json();
 }
 
-export async function deleteImageLocation(id: number): Promise<void> {
+export async function deleteImageLocation(id: number, updated: string): Promise<void> {
     const response = await fetch(`${API_BASE_URL}/image_locations/${id}`, {
         method: 'DELETE',
+        headers: ifMatch(updated),
     });
     if (!response.ok) {
         try {
             const err = await response.json();
-            throw new Error(err.error || 'Failed to delete image location');
+            throw new Error(errorMessage(err) || 'Failed to delete image location');
         } catch (e) {
              throw new Error('Failed to delete image location');
         }
@@ -55,7 +58,7 @@ export async function previewImageLocationScan(id: number, debug: boolean): Prom
     const response = await fetch(`${API_BASE_URL}/image_locations/${id}/preview_scan?debug=${debug}`);
     if (!response.ok) {
         const err = await response.json();
-        throw new Error(err.error || 'Failed to run preview scan');
+        throw new Error(errorMessage(err) || 'Failed to run preview scan');
     }
     return response.json();
 }
\ No newline at end of file
@@ -83,7 +83,7 @@ export async function refreshLocations() {
         });
     } catch (error) {
         alert(`Error fetching data: ${error instanceof Error ? error.message : 'Unknown error'}`);
-        tableBody.innerHTML = '<tr><td colspan="10">Failed to load data. Is the backend server running?</td></tr>';
+        tableBody.innerHTML = '<tr><td colspan="12">Failed to load data. Is the backend server running?</td></tr>';
     }
 }
 
@@ -86,10 +86,12 @@ export async function handleTableClick(event: Event) {
             date_from_exif: (row.querySelector('.edit-date_from_exif') as HTMLInputElement).checked,
             include_parent: (row.querySelector('.edit-include_parent') as HTMLInputElement).checked,
             ignore_dirs: (row.querySelector('.edit-ignore_dirs') as HTMLInputElement).value.split(',').map(s => s.trim()).filter(s => s),
+            default_city: (row.querySelector('.edit-default_city') as HTMLInputElement).value.trim(),
+            headliner_last: (row.querySelector('.edit-headliner_last') as HTMLInputElement).checked,
             active: (row.querySelector('.edit-active') as HTMLInputElement).checked,
         };
         try {
-            await updateImageLocation(id, payload);
+            await updateImageLocation(id, payload, location?.Updated ?? '');
             await refreshLocations(); // Refresh all to see changes
         } catch (error) {
             alert(`Error: ${error instanceof Error ? error.message : 'Unknown error'}`);
@@ -119,6 +121,8 @@ export async function handleTableClick(event: Event) {
             date_from_exif: (row.querySelector('.edit-date_from_exif') as HTMLInputElement).checked,
             include_parent: (row.querySelector('.edit-include_parent') as HTMLInputElement).checked,
             ignore_dirs: (row.querySelector('.edit-ignore_dirs') as HTMLInputElement).value.split(',').map(s => s.trim()).filter(s => s),
+            default_city: (row.querySelector('.edit-default_city') as HTMLInputElement).value.trim(),
+            headliner_last: (row.querySelector('.edit-headliner_last') as HTMLInputElement).checked,
             active: (row.querySelector('.edit-active') as HTMLInputElement).checked,
         };
         try {
@@ -133,7 +137,7 @@ export async function handleTableClick(event: Event) {
     else if (target.classList.contains('delete-btn') && id) {
         if (confirm(`Are you sure you want to delete location ${id}?`)) {
             try {
-                await deleteImageLocation(id);
+                await deleteImageLocation(id, location?.Updated ?? '');
                 await refreshLocations();
             } catch (error) {
                 alert(`Error: ${error instanceof Error ? error.message : 'Unknown error'}`);
@@ -195,7 +199,7 @@ export function handleEditItem(item: ImageLocation) {
 
 export function handleNotFound(name: string) {
     const row = tableBody.insertRow(0);
-    row.innerHTML = `<td colspan="10" style="color: red; font-weight: bold; text-align: center; padding: 10px; background-color: #fff0f0;">Not Found : ${name}</td>`;
+    row.innerHTML = `<td colspan="12" style="color: red; font-weight: bold; text-align: center; padding: 10px; background-color: #fff0f0;">Not Found : ${name}</td>`;
 }
 
 export function handleSort(event: Event) {
@@ -436,18 +440,21 @@ function createPreviewContent(result: ScanResult, isDebug: boolean): HTMLElement
             const dateStr = (item.year && item.month && item.day) 
                 ? `${item.year}-${String(item.month).padStart(2, '0')}-${String(item.day).padStart(2, '0')}`                
                 : 'N/A';            
-            const perfStr = (item.performers || []).map(group => {
+            // List the lineup in running order, opener first
+            const lineup = [...(item.performers || [])].sort((a, b) => (a[0]?.slot || 0) - (b[0]?.slot || 0));
+            const perfStr = lineup.map(group => {
                 return group.map(p => {
                     const conf = p.confidence || 0;
                     const display = (conf > 0 ? p.match : p.name) || '';
                     let color = 'red';
                     if (conf === 100) color = 'green';
-                    else if (conf === 75) color = 'blue';
-                    else if (conf === 50) color = 'orange';
-                    else if (conf === 25) color = 'gray';
+                    else if (conf >= 75) color = 'blue';
+                    else if (conf >= 50) color = 'orange';
+                    else if (conf === 40) color = 'teal';
+                    else if (conf >= 25) color = 'gray';
 
-                    const tooltip = (conf !== 100) ? `title="Original: ${p.name}"` : '';
-                    const patternHtml = p.pattern ? `<span> ${p.pattern} </span>` : '';
+                    const tooltip = (conf !== 100) ? `title="Original: ${p.name}${p.headliner ? ' (headliner)' : ''}"` : (p.headliner ? 'title="Headliner"' : '');
+                    const patternHtml = p.pattern ? `<span${p.role ? ` title="${p.role}"` : ''}> ${p.pattern} </span>` : '';
                     // Wrap name in clickable span
                     return `${patternHtml}<span class="entity-item" data-type="performer" data-name="${encodeURIComponent(p.name)}" data-confidence="${conf}" style="cursor: pointer; text-decoration: underline dotted; color: ${color}; font-weight: ${conf > 0 ? 'bold' : 'normal'};" ${tooltip}>${display}</span>`;
                 }).join('');
@@ -457,9 +464,10 @@ function createPreviewContent(result: ScanResult, isDebug: boolean): HTMLElement
                 const display = (conf > 0 ? p.match : p.name) || '';
                 let color = 'red';
                 if (conf === 100) color = 'green';
-                else if (conf === 75) color = 'blue';
-                else if (conf === 50) color = 'orange';
-                else if (conf === 25) color = 'gray';
+                else if (conf >= 75) color = 'blue';
+                else if (conf >= 50) color = 'orange';
+                else if (conf === 40) color = 'teal';
+                else if (conf >= 25) color = 'gray';
 
                 const tooltip = (conf !== 100) ? `title="Original: ${p.name}"` : '';
                 const style = `color: ${color}; font-weight: ${conf > 0 ? 'bold' : 'normal'};${p.festival ? ' text-decoration: underline;' : ''}`;
@@ -470,9 +478,10 @@ function createPreviewContent(result: ScanResult, isDebug: boolean): HTMLElement
             const displayVenue = (conf > 0 ? item.venue?.match : item.venue?.name) || '';
             let color = 'red';            
             if (conf === 100) color = 'green';
-            else if (conf === 75) color = 'blue';
-            else if (conf === 50) color = 'orange';
-            else if (conf === 25) color = 'gray';
+            else if (conf >= 75) color = 'blue';
+            else if (conf >= 50) color = 'orange';
+            else if (conf === 40) color = 'teal';
+            else if (conf >= 25) color = 'gray';
 
             const consistentIcon = item.consistent ? '✓' : '✗';
             const consistentColor = item.consistent ? 'green' : 'red';
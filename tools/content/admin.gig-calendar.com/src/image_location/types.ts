@@ -5,6 +5,8 @@ export interface ImageLocation {
     DateFromExif: boolean;
     IncludeParent: boolean;
     IgnoreDirs: string[] | null;
+    DefaultCity: string;
+    HeadlinerLast: boolean;
     Active: boolean;
     Created: string;
     Updated: string;
@@ -16,6 +18,8 @@ export interface ImageLocationPayload {
     date_from_exif: boolean;
     include_parent: boolean;
     ignore_dirs: string[];
+    default_city: string;
+    headliner_last: boolean;
     active: boolean;
 }
 
@@ -30,6 +34,9 @@ export interface PerformerMatchResult {
     match?: string;
     confidence: number;
     pattern?: string;
+    role?: string;
+    slot?: number;
+    headliner?: boolean;
 }
 
 export interface PromoterMatchResult {
@@ -37,6 +44,7 @@ export interface PromoterMatchResult {
     match: string;
     confidence: number;
     festival?: boolean;
+    inferred?: boolean;
 }
 
 export interface MatchedResult {
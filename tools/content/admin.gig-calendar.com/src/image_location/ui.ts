@@ -8,6 +8,8 @@ export function renderDisplayRow(row: HTMLTableRowElement, location: ImageLocati
         <td>${location.DateFromExif}</td>
         <td>${location.IncludeParent}</td>
         <td>${(location.IgnoreDirs || []).join(', ')}</td>
+        <td>${location.DefaultCity || ''}</td>
+        <td>${location.HeadlinerLast}</td>
         <td>${location.Active}</td>
         <td>${new Date(location.Created).toLocaleString()}</td>
         <td>${new Date(location.Updated).toLocaleString()}</td>
@@ -29,6 +31,8 @@ export function renderEditRow(row: HTMLTableRowElement, location: Partial<ImageL
         <td><input type="checkbox" class="edit-date_from_exif" ${location.DateFromExif ? 'checked' : ''}></td>
         <td><input type="checkbox" class="edit-include_parent" ${location.IncludeParent ? 'checked' : ''}></td>
         <td><input type="text" class="edit-ignore_dirs" value="${ignoreDirs}"></td>
+        <td><input type="text" class="edit-default_city" value="${location.DefaultCity || ''}"></td>
+        <td><input type="checkbox" class="edit-headliner_last" ${location.HeadlinerLast ? 'checked' : ''}></td>
         <td><input type="checkbox" class="edit-active" ${location.Active || isNew ? 'checked' : ''}></td>
         <td>${location.Created ? new Date(location.Created).toLocaleString() : '...'}</td>
         <td>${location.Updated ? new Date(location.Updated).toLocaleString() : '...'}</td>
@@ -47,7 +51,7 @@ export function renderEditRow(row: HTMLTableRowElement, location: Partial<ImageL
 export function renderTable(tableBody: HTMLTableSectionElement, locations: ImageLocation[]) {
     tableBody.innerHTML = '';
     if (!locations || locations.length === 0) {
-        tableBody.innerHTML = '<tr><td colspan="10">No image locations found.</td></tr>';
+        tableBody.innerHTML = '<tr><td colspan="12">No image locations found.</td></tr>';
         return;
     }
     locations.forEach(location => {
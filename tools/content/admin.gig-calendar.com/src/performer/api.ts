@@ -1,4 +1,6 @@
 import type { Performer, PerformerPayload } from './types.js';
+import { errorMessage } from '../shared/errors.js';
+import { ifMatch } from '../shared/version.js';
 
 const API_PREFIX = '/api/v1';
 
@@ -21,7 +23,7 @@ export async function createPerformer(payload: PerformerPayload): Promise<Perfor
     });
     if (!response.ok) {
         const errorData = await response.json();
-        throw new Error(`Failed to create performer: ${errorData.error || response.statusText}`);
+        throw new Error(`Failed to create performer: ${errorMessage(errorData) || response.statusText}`);
     }
     return await response.json();
 }
@@ -34,27 +36,29 @@ export async function getPerformer(id: number): Promise<Performer> {
     return await response.json();
 }
 
-export async function updatePerformer(id: number, payload: PerformerPayload): Promise<Performer> {
+export async function updatePerformer(id: number, payload: PerformerPayload, updated: string): Promise<Performer> {
     const response = await fetch(`${API_PREFIX}/performers/${id}`, {
         method: 'PUT',
         headers: {
             'Content-Type': 'application/json',
+            ...ifMatch(updated),
         },
         body: JSON.stringify(payload),
     });
     if (!response.ok) {
         const errorData = await response.json();
-        throw new Error(`Failed to update performer: ${errorData.error || response.statusText}`);
+        throw new Error(`Failed to update performer: ${errorMessage(errorData) || response.statusText}`);
     }
     return await response.json();
 }
 
-export async function deletePerformer(id: number): Promise<void> {
+export async function deletePerformer(id: number, updated: string): Promise<void> {
     const response = await fetch(`${API_PREFIX}/performers/${id}`, {
         method: 'DELETE',
+        headers: ifMatch(updated),
     });
     if (!response.ok) {
         const errorData = await response.json();
-        throw new Error(`Failed to delete performer: ${errorData.error || response.statusText}`);
+        throw new Error(`Failed to delete performer: ${errorMessage(errorData) || response.statusText}`);
     }
 }
\ No newline at end of file
@@ -40,7 +40,7 @@ export async function handleTableClick(event: Event) {
             name: (row.querySelector('.edit-name') as HTMLInputElement).value,
         };
         try {
-            await updatePerformer(id, payload);
+            await updatePerformer(id, payload, performer?.Updated ?? '');
             await refreshPerformers(); // Refresh all to see changes
         } catch (error) {
             alert(`Error: ${error instanceof Error ? error.message : 'Unknown error'}`);
@@ -78,7 +78,7 @@ export async function handleTableClick(event: Event) {
     else if (target.classList.contains('delete-btn') && id) {
         if (confirm(`Are you sure you want to delete performer ${id}?`)) {
             try {
-                await deletePerformer(id);
+                await deletePerformer(id, performer?.Updated ?? '');
                 await refreshPerformers();
             } catch (error) {
                 alert(`Error: ${error instanceof Error ? error.message : 'Unknown error'}`);
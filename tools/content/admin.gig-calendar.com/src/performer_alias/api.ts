@@ -1,4 +1,6 @@
 import type { PerformerAlias, PerformerAliasPayload, Performer } from './types.ts';
+import { errorMessage } from '../shared/errors.js';
+import { ifMatch } from '../shared/version.js';
 
 const API_PREFIX = '/api/v1';
 
@@ -30,32 +32,34 @@ export async function createPerformerAlias(payload: PerformerAliasPayload): Prom
     });
     if (!response.ok) {
         const errorData = await response.json();
-        throw new Error(`Failed to create performer alias: ${errorData.error || response.statusText}`);
+        throw new Error(`Failed to create performer alias: ${errorMessage(errorData) || response.statusText}`);
     }
     return await response.json();
 }
 
-export async function updatePerformerAlias(id: number, payload: PerformerAliasPayload): Promise<PerformerAlias> {
+export async function updatePerformerAlias(id: number, payload: PerformerAliasPayload, updated: string): Promise<PerformerAlias> {
     const response = await fetch(`${API_PREFIX}/performer_aliases/${id}`, {
         method: 'PUT',
         headers: {
             'Content-Type': 'application/json',
+            ...ifMatch(updated),
         },
         body: JSON.stringify(payload),
     });
     if (!response.ok) {
         const errorData = await response.json();
-        throw new Error(`Failed to update performer alias: ${errorData.error || response.statusText}`);
+        throw new Error(`Failed to update performer alias: ${errorMessage(errorData) || response.statusText}`);
     }
     return await response.json();
 }
 
-export async function deletePerformerAlias(id: number): Promise<void> {
+export async function deletePerformerAlias(id: number, updated: string): Promise<void> {
     const response = await fetch(`${API_PREFIX}/performer_aliases/${id}`, {
         method: 'DELETE',
+        headers: ifMatch(updated),
     });
     if (!response.ok) {
         const errorData = await response.json();
-        throw new Error(`Failed to delete performer alias: ${errorData.error || response.statusText}`);
+        throw new Error(`Failed to delete performer alias: ${errorMessage(errorData) || response.statusText}`);
     }
 }
\ No newline at end of file
@@ -1,4 +1,6 @@
 import type { Promoter, PromoterPayload } from './types.js';
+import { errorMessage } from '../shared/errors.js';
+import { ifMatch } from '../shared/version.js';
 
 const API_PREFIX = '/api/v1';
 
@@ -21,7 +23,7 @@ export async function createPromoter(payload: PromoterPayload): Promise<Promoter
     });
     if (!response.ok) {
         const errorData = await response.json();
-        throw new Error(`Failed to create promoter: ${errorData.error || response.statusText}`);
+        throw new Error(`Failed to create promoter: ${errorMessage(errorData) || response.statusText}`);
     }
     return await response.json();
 }
@@ -34,27 +36,29 @@ export async function getPromoter(id: number): Promise<Promoter> {
     return await response.json();
 }
 
-export async function updatePromoter(id: number, payload: PromoterPayload): Promise<Promoter> {
+export async function updatePromoter(id: number, payload: PromoterPayload, updated: string): Promise<Promoter> {
     const response = await fetch(`${API_PREFIX}/promoters/${id}`, {
         method: 'PUT',
         headers: {
             'Content-Type': 'application/json',
+            ...ifMatch(updated),
         },
         body: JSON.stringify(payload),
     });
     if (!response.ok) {
         const errorData = await response.json();
-        throw new Error(`Failed to update promoter: ${errorData.error || response.statusText}`);
+        throw new Error(`Failed to update promoter: ${errorMessage(errorData) || response.statusText}`);
     }
     return await response.json();
 }
 
-export async function deletePromoter(id: number): Promise<void> {
+export async function deletePromoter(id: number, updated: string): Promise<void> {
     const response = await fetch(`${API_PREFIX}/promoters/${id}`, {
         method: 'DELETE',
+        headers: ifMatch(updated),
     });
     if (!response.ok) {
         const errorData = await response.json();
-        throw new Error(`Failed to delete promoter: ${errorData.error || response.statusText}`);
+        throw new Error(`Failed to delete promoter: ${errorMessage(errorData) || response.statusText}`);
     }
 }
\ No newline at end of file
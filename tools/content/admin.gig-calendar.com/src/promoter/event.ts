@@ -40,7 +40,7 @@ export async function handleTableClick(event: Event) {
             name: (row.querySelector('.edit-name') as HTMLInputElement).value,
         };
         try {
-            await updatePromoter(id, payload);
+            await updatePromoter(id, payload, promoter?.Updated ?? '');
             await refreshPromoters(); // Refresh all to see changes
         } catch (error) {
             alert(`Error: ${error instanceof Error ? error.message : 'Unknown error'}`);
@@ -78,7 +78,7 @@ export async function handleTableClick(event: Event) {
     else if (target.classList.contains('delete-btn') && id) {
         if (confirm(`Are you sure you want to delete promoter ${id}?`)) {
             try {
-                await deletePromoter(id);
+                await deletePromoter(id, promoter?.Updated ?? '');
                 await refreshPromoters();
             } catch (error) {
                 alert(`Error: ${error instanceof Error ? error.message : 'Unknown error'}`);
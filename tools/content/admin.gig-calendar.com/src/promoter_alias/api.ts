@@ -1,4 +1,6 @@
 import type { PromoterAlias, Promoter, PromoterAliasPayload } from './types.js';
+import { errorMessage } from '../shared/errors.js';
+import { ifMatch } from '../shared/version.js';
 
 const API_BASE = '/api/v1';
 
@@ -30,32 +32,34 @@ export async function createPromoterAlias(payload: PromoterAliasPayload): Promis
     });
     if (!response.ok) {
         const errorData = await response.json().catch(() => ({}));
-        throw new Error(errorData.error || `Failed to create promoter alias: ${response.statusText}`);
+        throw new Error(errorMessage(errorData) || `Failed to create promoter alias: ${response.statusText}`);
     }
     return response.json();
 }
 
-export async function updatePromoterAlias(id: number, payload: PromoterAliasPayload): Promise<PromoterAlias> {
+export async function updatePromoterAlias(id: number, payload: PromoterAliasPayload, updated: string): Promise<PromoterAlias> {
     const response = await fetch(`${API_BASE}/promoter_aliases/${id}`, {
         method: 'PUT',
         headers: {
             'Content-Type': 'application/json',
+            ...ifMatch(updated),
         },
         body: JSON.stringify(payload),
     });
     if (!response.ok) {
         const errorData = await response.json().catch(() => ({}));
-        throw new Error(errorData.error || `Failed to update promoter alias: ${response.statusText}`);
+        throw new Error(errorMessage(errorData) || `Failed to update promoter alias: ${response.statusText}`);
     }
     return response.json();
 }
 
-export async function deletePromoterAlias(id: number): Promise<void> {
+export async function deletePromoterAlias(id: number, updated: string): Promise<void> {
     const response = await fetch(`${API_BASE}/promoter_aliases/${id}`, {
         method: 'DELETE',
+        headers: ifMatch(updated),
     });
     if (!response.ok) {
         const errorData = await response.json().catch(() => ({}));
-        throw new Error(errorData.error || `Failed to delete promoter alias: ${response.statusText}`);
+        throw new Error(errorMessage(errorData) || `Failed to delete promoter alias: ${response.statusText}`);
     }
 }
\ No newline at end of file
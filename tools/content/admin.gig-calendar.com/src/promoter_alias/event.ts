@@ -42,7 +42,7 @@ export async function handleTableClick(event: Event) {
             alias: (row.querySelector('.edit-alias') as HTMLInputElement).value,
         };
         try {
-            await updatePromoterAlias(id, payload);
+            await updatePromoterAlias(id, payload, alias?.Updated ?? '');
             await refreshAliases(); // Refresh all to see changes
         } catch (error) {
             alert(`Error: ${error instanceof Error ? error.message : 'Unknown error'}`);
@@ -82,7 +82,7 @@ export async function handleTableClick(event: Event) {
     else if (target.classList.contains('delete-btn') && id) {
         if (confirm(`Are you sure you want to delete promoter alias ${id}?`)) {
             try {
-                await deletePromoterAlias(id);
+                await deletePromoterAlias(id, alias?.Updated ?? '');
                 await refreshAliases();
             } catch (error) {
                 alert(`Error: ${error instanceof Error ? error.message : 'Unknown error'}`);
@@ -0,0 +1,14 @@
+// The body of a failed API response: a single error, or the field errors of a payload
+// that failed validation.
+export interface ErrorResponse {
+    error?: string;
+    errors?: { field: string; message: string }[];
+}
+
+// errorMessage returns the message to show for a failed API response, if it has one.
+export function errorMessage(data: ErrorResponse): string | undefined {
+    if (data.errors && data.errors.length > 0) {
+        return data.errors.map(e => `${e.field} ${e.message}`).join('; ');
+    }
+    return data.error;
+}
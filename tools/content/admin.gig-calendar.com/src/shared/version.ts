@@ -0,0 +1,5 @@
+// ifMatch returns the headers that make an update or delete apply only to the version of
+// a row that was read. The API uses a row's Updated time, quoted, as its ETag.
+export function ifMatch(updated: string): Record<string, string> {
+    return { 'If-Match': `"${updated}"` };
+}
@@ -1,4 +1,6 @@
-import { StageRole } from "./types.js";
+import { StageRole, StageRoleKind } from "./types.js";
+import { errorMessage } from '../shared/errors.js';
+import { ifMatch } from '../shared/version.js';
 
 const API_BASE = "/api/v1/stage_roles";
 
@@ -11,42 +13,44 @@ export async function fetchStageRoles(): Promise<StageRole[]> {
     return data || [];
 }
 
-export async function createStageRole(pattern: string): Promise<StageRole> {
+export async function createStageRole(pattern: string, kind: StageRoleKind): Promise<StageRole> {
     const response = await fetch(API_BASE, {
         method: "POST",
         headers: {
             "Content-Type": "application/json",
         },
-        body: JSON.stringify({ pattern }),
+        body: JSON.stringify({ pattern, kind }),
     });
     if (!response.ok) {
         const errorData = await response.json().catch(() => ({}));
-        throw new Error(errorData.error || `Failed to create stage role: ${response.statusText}`);
+        throw new Error(errorMessage(errorData) || `Failed to create stage role: ${response.statusText}`);
     }
     return await response.json();
 }
 
-export async function updateStageRole(id: number, pattern: string): Promise<StageRole> {
+export async function updateStageRole(id: number, pattern: string, kind: StageRoleKind, updated: string): Promise<StageRole> {
     const response = await fetch(`${API_BASE}/${id}`, {
         method: "PUT",
         headers: {
             "Content-Type": "application/json",
+            ...ifMatch(updated),
         },
-        body: JSON.stringify({ pattern }),
+        body: JSON.stringify({ pattern, kind }),
     });
     if (!response.ok) {
         const errorData = await response.json().catch(() => ({}));
-        throw new Error(errorData.error || `Failed to update stage role: ${response.statusText}`);
+        throw new Error(errorMessage(errorData) || `Failed to update stage role: ${response.statusText}`);
     }
     return await response.json();
 }
 
-export async function deleteStageRole(id: number): Promise<void> {
+export async function deleteStageRole(id: number, updated: string): Promise<void> {
     const response = await fetch(`${API_BASE}/${id}`, {
         method: "DELETE",
+        headers: ifMatch(updated),
     });
     if (!response.ok) {
         const errorData = await response.json().catch(() => ({}));
-        throw new Error(errorData.error || `Failed to delete stage role: ${response.statusText}`);
+        throw new Error(errorMessage(errorData) || `Failed to delete stage role: ${response.statusText}`);
     }
 }
\ No newline at end of file
@@ -4,7 +4,7 @@ import {
     stageRolesCache, currentSort, setCurrentSort, setCurrentFilters, refreshStageRoles, applyFilters, sortStageRoles 
 } from './app.js';
 import { updateSortIndicators } from '../shared/ui.js';
-import type { StageRole, StageRoleSortableColumn } from './types.js';
+import type { StageRole, StageRoleKind, StageRoleSortableColumn } from './types.js';
 
 export function handleNewClick() {
     const tbody = document.getElementById('table-body') as HTMLTableSectionElement;
@@ -30,6 +30,8 @@ export async function handleTableClick(event: MouseEvent) {
 
     const idStr = row.dataset.id;
     const id = idStr ? parseInt(idStr, 10) : 0;
+    // Updates and deletes send the version of the row shown, so they fail if it has changed since
+    const updated = stageRolesCache.find(i => i.ID === id)?.Updated ?? '';
 
     if (btn.classList.contains('edit-btn')) {
         const item = stageRolesCache.find(i => i.ID === id);
@@ -38,7 +40,7 @@ export async function handleTableClick(event: MouseEvent) {
     } else if (btn.classList.contains('delete-btn')) {
         if (confirm('Are you sure you want to delete this stage role?')) {
             try {
-                await deleteStageRole(id);
+                await deleteStageRole(id, updated);
                 await refreshStageRoles();
             } catch (e) {
                 alert((e as Error).message);
@@ -61,6 +63,7 @@ export async function handleTableClick(event: MouseEvent) {
         
     } else if (btn.classList.contains('save-btn') || btn.classList.contains('add-btn')) {
         const pattern = (row.querySelector('.edit-pattern') as HTMLInputElement).value;
+        const kind = (row.querySelector('.edit-kind') as HTMLSelectElement).value as StageRoleKind;
 
         if (!pattern) {
             alert('Pattern is required.');
@@ -69,9 +72,9 @@ export async function handleTableClick(event: MouseEvent) {
 
         try {
             if (btn.classList.contains('add-btn')) {
-                await createStageRole(pattern);
+                await createStageRole(pattern, kind);
             } else {
-                await updateStageRole(id, pattern);
+                await updateStageRole(id, pattern, kind, updated);
             }
             await refreshStageRoles();
         } catch (e) {
@@ -2,9 +2,14 @@ export interface StageRole {
     ID: number;
     Uuid: string;
     Pattern: string;
+    Kind: StageRoleKind;
     Created: string;
     Updated: string;
 }
 
-export type StageRoleSortableColumn = 'ID' | 'Uuid' | 'Pattern' | 'Created' | 'Updated';
+export type StageRoleKind = 'support' | 'guest' | 'b2b' | 'in-conversation' | 'featuring';
+
+export const STAGE_ROLE_KINDS: StageRoleKind[] = ['support', 'guest', 'b2b', 'in-conversation', 'featuring'];
+
+export type StageRoleSortableColumn = 'ID' | 'Uuid' | 'Pattern' | 'Kind' | 'Created' | 'Updated';
 export type SortDirection = 'asc' | 'desc';
\ No newline at end of file
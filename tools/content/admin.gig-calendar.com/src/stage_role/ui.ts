@@ -1,11 +1,11 @@
-import { StageRole } from "./types.js";
+import { StageRole, STAGE_ROLE_KINDS } from "./types.js";
 import { getActionButtonsHtml, getEditButtonsHtml, getNoDataRowHtml, formatDateTime } from '../shared/ui.js';
 
 export function renderTable(tbody: HTMLTableSectionElement, items: StageRole[]) {
     tbody.innerHTML = "";
 
     if (items.length === 0) {
-        tbody.innerHTML = getNoDataRowHtml(6, 'No stage roles found.');
+        tbody.innerHTML = getNoDataRowHtml(7, 'No stage roles found.');
         return;
     }
 
@@ -28,6 +28,7 @@ export function renderDisplayRow(tbody: HTMLTableSectionElement, item: StageRole
         <td>${item.ID}</td>
         <td>${item.Uuid || ''}</td>
         <td>${escapeHtml(item.Pattern)}</td>
+        <td>${item.Kind || ''}</td>
         <td>${formatDateTime(item.Created)}</td>
         <td>${formatDateTime(item.Updated)}</td>
         <td class="actions">${getActionButtonsHtml()}</td>
@@ -49,10 +50,14 @@ export function renderEditRow(tbody: HTMLTableSectionElement, item: Partial<Stag
 
     if (!row) return;
 
+    const kind = item.Kind || 'support';
+    const kindOptions = STAGE_ROLE_KINDS.map(k => `<option value="${k}" ${k === kind ? 'selected' : ''}>${k}</option>`).join('');
+
     row.innerHTML = `
         <td>${item.ID || 'New'}</td>
         <td>${item.Uuid || ''}</td>
         <td><input type="text" class="edit-pattern" value="${item.Pattern || ''}" placeholder="Pattern"></td>
+        <td><select class="edit-kind">${kindOptions}</select></td>
         <td>${item.Created ? formatDateTime(item.Created) : ''}</td>
         <td>${item.Updated ? formatDateTime(item.Updated) : ''}</td>
         <td class="actions">${getEditButtonsHtml(isNew)}</td>
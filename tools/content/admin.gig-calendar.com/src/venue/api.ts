@@ -1,4 +1,6 @@
 import type { Venue, VenuePayload } from './types.js';
+import { errorMessage } from '../shared/errors.js';
+import { ifMatch } from '../shared/version.js';
 
 const API_BASE_URL = 'http://localhost:8080/api/v1';
 
@@ -19,32 +21,33 @@ export async function createVenue(payload: VenuePayload): Promise<Venue> {
     });
     if (!response.ok) {
         const err = await response.json();
-        throw new Error(err.error || 'Failed to create venue');
+        throw new Error(errorMessage(err) || 'Failed to create venue');
     }
     return response.json();
 }
 
-export async function updateVenue(id: number, payload: VenuePayload): Promise<Venue> {
+export async function updateVenue(id: number, payload: VenuePayload, updated: string): Promise<Venue> {
     const response = await fetch(`${API_BASE_URL}/venues/${id}`, {
         method: 'PUT',
-        headers: { 'Content-Type': 'application/json' },
+        headers: { 'Content-Type': 'application/json', ...ifMatch(updated) },
         body: JSON.stringify(payload),
     });
     if (!response.ok) {
         const err = await response.json();
-        throw new Error(err.error || 'Failed to update venue');
+        throw new Error(errorMessage(err) || 'Failed to update venue');
     }
     return response.json();
 }
 
-export async function deleteVenue(id: number): Promise<void> {
+export async function deleteVenue(id: number, updated: string): Promise<void> {
     const response = await fetch(`${API_BASE_URL}/venues/${id}`, {
         method: 'DELETE',
+        headers: ifMatch(updated),
     });
     if (!response.ok) {
         try {
             const err = await response.json();
-            throw new Error(err.error || 'Failed to delete venue');
+            throw new Error(errorMessage(err) || 'Failed to delete venue');
         } catch (e) {
              throw new Error('Failed to delete venue');
         }
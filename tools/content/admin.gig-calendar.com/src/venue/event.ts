@@ -39,7 +39,7 @@ export async function handleTableClick(event: Event) {
             name: (row.querySelector('.edit-name') as HTMLInputElement).value,
         };
         try {
-            await updateVenue(id, payload);
+            await updateVenue(id, payload, venue?.Updated ?? '');
             await refreshVenues();
         } catch (error) {
             alert(`Error: ${error instanceof Error ? error.message : 'Unknown error'}`);
@@ -77,7 +77,7 @@ export async function handleTableClick(event: Event) {
     else if (target.classList.contains('delete-btn') && id) {
         if (confirm(`Are you sure you want to delete venue ${id}?`)) {
             try {
-                await deleteVenue(id);
+                await deleteVenue(id, venue?.Updated ?? '');
                 await refreshVenues();
             } catch (error) {
                 alert(`Error: ${error instanceof Error ? error.message : 'Unknown error'}`);
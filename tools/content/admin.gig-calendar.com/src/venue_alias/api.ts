@@ -1,4 +1,6 @@
 import type { VenueAlias, VenueAliasPayload, Venue } from './types.js';
+import { errorMessage } from '../shared/errors.js';
+import { ifMatch } from '../shared/version.js';
 
 const API_PREFIX = '/api/v1';
 
@@ -30,32 +32,34 @@ export async function createVenueAlias(payload: VenueAliasPayload): Promise<Venu
     });
     if (!response.ok) {
         const errorData = await response.json();
-        throw new Error(`Failed to create venue alias: ${errorData.error || response.statusText}`);
+        throw new Error(`Failed to create venue alias: ${errorMessage(errorData) || response.statusText}`);
     }
     return await response.json();
 }
 
-export async function updateVenueAlias(id: number, payload: VenueAliasPayload): Promise<VenueAlias> {
+export async function updateVenueAlias(id: number, payload: VenueAliasPayload, updated: string): Promise<VenueAlias> {
     const response = await fetch(`${API_PREFIX}/venue_aliases/${id}`, {
         method: 'PUT',
         headers: {
             'Content-Type': 'application/json',
+            ...ifMatch(updated),
         },
         body: JSON.stringify(payload),
     });
     if (!response.ok) {
         const errorData = await response.json();
-        throw new Error(`Failed to update venue alias: ${errorData.error || response.statusText}`);
+        throw new Error(`Failed to update venue alias: ${errorMessage(errorData) || response.statusText}`);
     }
     return await response.json();
 }
 
-export async function deleteVenueAlias(id: number): Promise<void> {
+export async function deleteVenueAlias(id: number, updated: string): Promise<void> {
     const response = await fetch(`${API_PREFIX}/venue_aliases/${id}`, {
         method: 'DELETE',
+        headers: ifMatch(updated),
     });
     if (!response.ok) {
         const errorData = await response.json();
-        throw new Error(`Failed to delete venue alias: ${errorData.error || response.statusText}`);
+        throw new Error(`Failed to delete venue alias: ${errorMessage(errorData) || response.statusText}`);
     }
 }
\ No newline at end of file
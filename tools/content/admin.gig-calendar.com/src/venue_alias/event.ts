@@ -45,7 +45,7 @@ export async function handleTableClick(event: Event) {
             alias: (row.querySelector('.edit-alias') as HTMLInputElement).value,
         };
         try {
-            await updateVenueAlias(id, payload);
+            await updateVenueAlias(id, payload, alias?.Updated ?? '');
             await refreshAliases(); // Refresh all to see changes
         } catch (error) {
             alert(`Error: ${error instanceof Error ? error.message : 'Unknown error'}`);
@@ -85,7 +85,7 @@ export async function handleTableClick(event: Event) {
     else if (target.classList.contains('delete-btn') && id) {
         if (confirm(`Are you sure you want to delete venue alias ${id}?`)) {
             try {
-                await deleteVenueAlias(id);
+                await deleteVenueAlias(id, alias?.Updated ?? '');
                 await refreshAliases();
             } catch (error) {
                 alert(`Error: ${error instanceof Error ? error.message : 'Unknown error'}`);
@@ -11,6 +11,7 @@ require (
 	github.com/lib/pq v1.12.0
 	github.com/pressly/goose/v3 v3.27.0
 	golang.org/x/oauth2 v0.36.0
+	golang.org/x/text v0.35.0
 	google.golang.org/api v0.272.0
 	google.golang.org/grpc v1.79.3
 )
@@ -40,7 +41,6 @@ require (
 	golang.org/x/net v0.52.0 // indirect
 	golang.org/x/sync v0.20.0 // indirect
 	golang.org/x/sys v0.42.0 // indirect
-	golang.org/x/text v0.35.0 // indirect
 	golang.org/x/time v0.15.0 // indirect
 	google.golang.org/genproto v0.0.0-20260319201613-d00831a3d3e7 // indirect
 	google.golang.org/genproto/googleapis/api v0.0.0-20260319201613-d00831a3d3e7 // indirect
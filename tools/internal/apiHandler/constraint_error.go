@@ -0,0 +1,86 @@
+package apiHandler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// Postgres error codes for the constraint violations reported to clients. Any other
+// database error is the server's fault and answered with 500.
+const (
+	notNullViolation    = "23502"
+	foreignKeyViolation = "23503"
+	uniqueViolation     = "23505"
+	checkViolation      = "23514"
+)
+
+// keyDetail matches the detail Postgres gives for unique and foreign key violations, such
+// as `Key (alias)=(The Who) already exists.`
+var keyDetail = regexp.MustCompile(`^Key \((.+?)\)=\((.*)\) (.+?)\.?$`)
+
+// constraintViolation reports whether err is a violated database constraint and, if so,
+// the status and body to respond with. A row that clashes with another, or is still
+// referred to by others, is a 409 Conflict naming the constraint and the value. A payload
+// referring to a row that does not exist, or breaking a check, is a 422 in the same shape
+// as a failed validation.
+func constraintViolation(err error) (int, interface{}, bool) {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return 0, nil, false
+	}
+
+	column, value := pqErr.Column, ""
+	if m := keyDetail.FindStringSubmatch(pqErr.Detail); m != nil {
+		column, value = m[1], m[2]
+	}
+
+	switch pqErr.Code {
+	case uniqueViolation:
+		return http.StatusConflict, map[string]string{
+			"error":      fmt.Sprintf("%s %q is already in use", column, value),
+			"constraint": pqErr.Constraint,
+			"field":      column,
+			"value":      value,
+		}, true
+	case foreignKeyViolation:
+		if strings.Contains(pqErr.Detail, "still referenced") {
+			return http.StatusConflict, map[string]string{
+				"error":      fmt.Sprintf("%s %s is still referred to by %s", column, value, pqErr.Table),
+				"constraint": pqErr.Constraint,
+				"field":      column,
+				"value":      value,
+			}, true
+		}
+		// Payloads name the columns referring to other rows after the row's type, with _id
+		field := column
+		if !strings.HasSuffix(field, "_id") {
+			field += "_id"
+		}
+		return http.StatusUnprocessableEntity, map[string]validationErrors{"errors": {{
+			Field:      field,
+			Message:    fmt.Sprintf("refers to %s, which does not exist", value),
+			Constraint: pqErr.Constraint,
+		}}}, true
+	case notNullViolation:
+		return http.StatusUnprocessableEntity, map[string]validationErrors{"errors": {{
+			Field:      column,
+			Message:    "is required",
+			Constraint: pqErr.Constraint,
+		}}}, true
+	case checkViolation:
+		if column == "" {
+			column = pqErr.Table
+		}
+		return http.StatusUnprocessableEntity, map[string]validationErrors{"errors": {{
+			Field:      column,
+			Message:    "breaks the check " + pqErr.Constraint,
+			Constraint: pqErr.Constraint,
+		}}}, true
+	}
+	return 0, nil, false
+}
@@ -0,0 +1,79 @@
+package apiHandler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+func TestConstraintViolation(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantBody   interface{}
+	}{
+		{
+			name: "Unique",
+			err: &pq.Error{Code: uniqueViolation, Constraint: "performer_alias_alias_key", Table: "performer_alias",
+				Detail: "Key (alias)=(The Who) already exists."},
+			wantStatus: http.StatusConflict,
+			wantBody: map[string]string{
+				"error": `alias "The Who" is already in use`, "constraint": "performer_alias_alias_key", "field": "alias", "value": "The Who",
+			},
+		},
+		{
+			name: "Still referenced",
+			err: fmt.Errorf("deleting venue: %w", &pq.Error{Code: foreignKeyViolation, Constraint: "event_venue_fkey", Table: "event",
+				Detail: `Key (id)=(3) is still referenced from table "event".`}),
+			wantStatus: http.StatusConflict,
+			wantBody: map[string]string{
+				"error": "id 3 is still referred to by event", "constraint": "event_venue_fkey", "field": "id", "value": "3",
+			},
+		},
+		{
+			name: "Missing reference",
+			err: &pq.Error{Code: foreignKeyViolation, Constraint: "event_venue_fkey", Table: "event",
+				Detail: `Key (venue)=(99) is not present in table "venue".`},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantBody: map[string]validationErrors{"errors": {{
+				Field: "venue_id", Message: "refers to 99, which does not exist", Constraint: "event_venue_fkey",
+			}}},
+		},
+		{
+			name:       "Not null",
+			err:        &pq.Error{Code: notNullViolation, Column: "name", Table: "performer"},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantBody:   map[string]validationErrors{"errors": {{Field: "name", Message: "is required"}}},
+		},
+		{
+			name:       "Check on a table",
+			err:        &pq.Error{Code: checkViolation, Constraint: "event_performer_slot_check", Table: "event_performer"},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantBody: map[string]validationErrors{"errors": {{
+				Field: "event_performer", Message: "breaks the check event_performer_slot_check", Constraint: "event_performer_slot_check",
+			}}},
+		},
+		{name: "Other database error", err: &pq.Error{Code: "42P01"}},
+		{name: "Not a database error", err: errors.New("connection refused")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body, ok := constraintViolation(tt.err)
+			if ok != (tt.wantStatus != 0) {
+				t.Fatalf("constraintViolation() ok = %v, want %v", ok, tt.wantStatus != 0)
+			}
+			if status != tt.wantStatus {
+				t.Errorf("constraintViolation() status = %d, want %d", status, tt.wantStatus)
+			}
+			if !reflect.DeepEqual(body, tt.wantBody) {
+				t.Errorf("constraintViolation() body = %#v, want %#v", body, tt.wantBody)
+			}
+		})
+	}
+}
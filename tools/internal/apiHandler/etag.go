@@ -0,0 +1,92 @@
+package apiHandler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/66james99/gig-calendar/internal/database"
+	"github.com/labstack/echo/v5"
+)
+
+// Updates and deletes are checked against the version of the row the client last read, so
+// two people editing the same row cannot silently overwrite each other. GET and PUT
+// responses carry the version as an ETag, which is the row's Updated time as it appears in
+// JSON, so a client holding a row from a list can send it back too. A PUT or DELETE
+// sending it in If-Match is refused with 412 Precondition Failed if the row has changed
+// since. Without If-Match the change goes ahead, unless the API has been told to require
+// it, when it is refused with 428 Precondition Required.
+
+var (
+	errPreconditionFailed   = errors.New("row changed since it was read")
+	errPreconditionRequired = errors.New("If-Match header missing")
+)
+
+// versionQuery reads the updated time of the row with an id and locks the row until the
+// transaction ends, as the LockX queries do.
+type versionQuery func(q *database.Queries, ctx context.Context, id int32) (time.Time, error)
+
+// etag returns the ETag of the version of a row last updated at updated, formatted as
+// encoding/json formats times.
+func etag(updated time.Time) string {
+	return `"` + updated.Format(time.RFC3339Nano) + `"`
+}
+
+// setETag reports the version of a row in the response headers.
+func setETag(c *echo.Context, updated time.Time) {
+	c.Response().Header().Set("ETag", etag(updated))
+}
+
+// ifMatch checks the If-Match header of a request against the current version of a row.
+func (a *API) ifMatch(c *echo.Context, updated time.Time) error {
+	header := c.Request().Header.Get("If-Match")
+	if header == "" {
+		if a.requireIfMatch {
+			return errPreconditionRequired
+		}
+		return nil
+	}
+	current := etag(updated)
+	for _, tag := range strings.Split(header, ",") {
+		if tag = strings.TrimSpace(tag); tag == "*" || tag == current {
+			return nil
+		}
+	}
+	return errPreconditionFailed
+}
+
+// lockIfMatch locks the row with id until the transaction of q ends and checks the If-Match
+// header of the request against it. A missing row is reported as sql.ErrNoRows.
+func (a *API) lockIfMatch(c *echo.Context, q *database.Queries, id int32, version versionQuery) error {
+	updated, err := version(q, c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	return a.ifMatch(c, updated)
+}
+
+// writeIfMatch runs write in a transaction once the If-Match header of the request has
+// been checked against the row with id, which stays locked until write is done. A missing
+// row is reported as sql.ErrNoRows.
+func (a *API) writeIfMatch(c *echo.Context, id int32, version versionQuery, write func(q *database.Queries) error) error {
+	return a.inTx(c.Request().Context(), func(q *database.Queries) error {
+		if err := a.lockIfMatch(c, q, id, version); err != nil {
+			return err
+		}
+		return write(q)
+	})
+}
+
+// preconditionFailed reports whether err is a failed If-Match check and, if so, the status
+// and body to respond with.
+func preconditionFailed(err error) (int, map[string]string, bool) {
+	switch {
+	case errors.Is(err, errPreconditionFailed):
+		return http.StatusPreconditionFailed, map[string]string{"error": "This has been changed since you read it. Reload it and try again"}, true
+	case errors.Is(err, errPreconditionRequired):
+		return http.StatusPreconditionRequired, map[string]string{"error": "If-Match is required. Send the ETag of the version you are changing"}, true
+	}
+	return 0, nil, false
+}
@@ -0,0 +1,71 @@
+package apiHandler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/66james99/gig-calendar/internal/database"
+)
+
+func TestETag(t *testing.T) {
+	updated := time.Date(2025, 6, 14, 20, 30, 0, 123456000, time.UTC)
+	c, rec := newTestContext(http.MethodGet, "/performers/7")
+	setETag(c, updated)
+
+	want := `"2025-06-14T20:30:00.123456Z"`
+	if got := rec.Header().Get("ETag"); got != want {
+		t.Errorf("ETag = %s, want %s", got, want)
+	}
+}
+
+func TestLockIfMatch(t *testing.T) {
+	updated := time.Date(2025, 6, 14, 20, 30, 0, 123456000, time.UTC)
+	current := etag(updated)
+	stale := etag(updated.Add(-time.Second))
+	version := func(q *database.Queries, ctx context.Context, id int32) (time.Time, error) {
+		if id != 7 {
+			return time.Time{}, sql.ErrNoRows
+		}
+		return updated, nil
+	}
+
+	tests := []struct {
+		name       string
+		id         int32
+		header     string
+		require    bool
+		wantErr    error
+		wantStatus int
+	}{
+		{name: "Current version", id: 7, header: current},
+		{name: "Any version", id: 7, header: "*"},
+		{name: "Current version in a list", id: 7, header: stale + ", " + current},
+		{name: "Changed since read", id: 7, header: stale, wantErr: errPreconditionFailed, wantStatus: http.StatusPreconditionFailed},
+		{name: "Missing header", id: 7},
+		{name: "Missing header when required", id: 7, require: true, wantErr: errPreconditionRequired, wantStatus: http.StatusPreconditionRequired},
+		{name: "Missing row", id: 8, header: current, wantErr: sql.ErrNoRows},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newTestContext(http.MethodPut, "/performers/7")
+			if tt.header != "" {
+				c.Request().Header.Set("If-Match", tt.header)
+			}
+			a := &API{requireIfMatch: tt.require}
+
+			err := a.lockIfMatch(c, nil, tt.id, version)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("lockIfMatch() error = %v, want %v", err, tt.wantErr)
+			}
+			status, _, ok := preconditionFailed(err)
+			if ok != (tt.wantStatus != 0) || status != tt.wantStatus {
+				t.Errorf("preconditionFailed() = %d, %v, want %d", status, ok, tt.wantStatus)
+			}
+		})
+	}
+}
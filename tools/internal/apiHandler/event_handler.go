@@ -0,0 +1,383 @@
+package apiHandler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/66james99/gig-calendar/internal/database"
+	"github.com/66james99/gig-calendar/internal/metadata"
+	"github.com/labstack/echo/v5"
+)
+
+// eventPerformerPayload is one performer in the lineup of an event. Slots count up from 1
+// in running order; a zero slot places the performer by their position in the lineup.
+type eventPerformerPayload struct {
+	PerformerID int32  `json:"performer_id"`
+	Headliner   bool   `json:"headliner"`
+	Slot        int32  `json:"slot"`
+	Role        string `json:"role"`
+}
+
+// eventPromoterPayload is one promoter of an event.
+type eventPromoterPayload struct {
+	PromoterID int32 `json:"promoter_id"`
+	Primary    bool  `json:"primary"`
+}
+
+// eventPayload defines the JSON body for creating/updating an event with its lineup and
+// promoters. On update, leaving out performers or promoters keeps the event's current
+// ones, while an empty list removes them.
+type eventPayload struct {
+	Name        *string                 `json:"name"`
+	VenueID     int32                   `json:"venue_id"`
+	EventTypeID int32                   `json:"event_type_id"`
+	Date        time.Time               `json:"date"`
+	Performers  []eventPerformerPayload `json:"performers"`
+	Promoters   []eventPromoterPayload  `json:"promoters"`
+}
+
+// validate checks one performer of a lineup, naming its fields with prefix.
+func (p eventPerformerPayload) validate(prefix string) validationErrors {
+	var errs validationErrors
+	errs.requiredID(prefix+"performer_id", p.PerformerID)
+	if p.Slot < 0 {
+		errs.add(prefix+"slot", "must not be negative")
+	}
+	if p.Role != "" && !metadata.ValidStageRoleKind(p.Role) {
+		errs.add(prefix+"role", "must be a known stage role kind, not %q", p.Role)
+	}
+	return errs
+}
+
+// validate checks the payload for mistakes the database would only report as a failed
+// constraint, such as a performer or slot appearing twice in the lineup.
+func (p eventPayload) validate() validationErrors {
+	var errs validationErrors
+	errs.requiredID("venue_id", p.VenueID)
+	errs.requiredID("event_type_id", p.EventTypeID)
+	errs.requiredDate("date", p.Date)
+
+	performers := make(map[int32]bool)
+	slots := make(map[int32]bool)
+	for i, ep := range p.lineup(0) {
+		prefix := fmt.Sprintf("performers[%d].", i)
+		errs = append(errs, p.Performers[i].validate(prefix)...)
+		if ep.Performer != 0 && performers[ep.Performer] {
+			errs.add(prefix+"performer_id", "performer %d appears more than once in the lineup", ep.Performer)
+		}
+		if slots[ep.Slot] {
+			errs.add(prefix+"slot", "slot %d is used more than once in the lineup", ep.Slot)
+		}
+		performers[ep.Performer] = true
+		slots[ep.Slot] = true
+	}
+
+	promoters := make(map[int32]bool)
+	for i, ep := range p.Promoters {
+		field := fmt.Sprintf("promoters[%d].promoter_id", i)
+		errs.requiredID(field, ep.PromoterID)
+		if ep.PromoterID != 0 && promoters[ep.PromoterID] {
+			errs.add(field, "promoter %d appears more than once", ep.PromoterID)
+		}
+		promoters[ep.PromoterID] = true
+	}
+	return errs
+}
+
+// lineup returns the event_performer rows recording the payload's lineup for event.
+func (p eventPayload) lineup(event int32) []database.CreateEventPerformerParams {
+	lineup := make([]database.CreateEventPerformerParams, len(p.Performers))
+	for i, ep := range p.Performers {
+		slot := ep.Slot
+		if slot == 0 {
+			slot = int32(i + 1)
+		}
+		lineup[i] = database.CreateEventPerformerParams{
+			Event:     event,
+			Performer: ep.PerformerID,
+			Headliner: ep.Headliner,
+			Slot:      slot,
+			Role:      sql.NullString{String: ep.Role, Valid: ep.Role != ""},
+		}
+	}
+	return lineup
+}
+
+// writeLineup replaces the lineup and promoters of event with those in the payload,
+// leaving either alone when the payload does not give it.
+func (p eventPayload) writeLineup(ctx context.Context, q *database.Queries, event int32) error {
+	if p.Performers != nil {
+		if err := q.DeleteEventPerformers(ctx, event); err != nil {
+			return err
+		}
+		for _, ep := range p.lineup(event) {
+			if _, err := q.CreateEventPerformer(ctx, ep); err != nil {
+				return err
+			}
+		}
+	}
+	if p.Promoters != nil {
+		if err := q.DeleteEventPromoters(ctx, event); err != nil {
+			return err
+		}
+		for _, ep := range p.Promoters {
+			params := database.CreateEventPromoterParams{Event: event, Promoter: ep.PromoterID, Primary: ep.Primary}
+			if _, err := q.CreateEventPromoter(ctx, params); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func mapEvent(e database.GetEventRow) map[string]interface{} {
+	return map[string]interface{}{
+		"ID":   e.ID,
+		"Uuid": e.Uuid,
+		"Name": e.Name.String,
+		"Date": e.Date,
+		"Venue": map[string]interface{}{
+			"ID":   e.Venue,
+			"Uuid": e.VenueUuid,
+			"Name": e.VenueName,
+			"City": e.VenueCity,
+		},
+		"EventType": map[string]interface{}{
+			"ID":   e.EventType,
+			"Uuid": e.EventTypeUuid,
+			"Name": e.EventTypeName,
+		},
+		"Created": e.Created,
+		"Updated": e.Updated,
+	}
+}
+
+func mapLineup(performers []database.ListEventPerformersRow) []map[string]interface{} {
+	lineup := make([]map[string]interface{}, len(performers))
+	for i, p := range performers {
+		lineup[i] = map[string]interface{}{
+			"ID":        p.Performer,
+			"Uuid":      p.Uuid,
+			"Name":      p.Name,
+			"Headliner": p.Headliner,
+			"Slot":      p.Slot,
+			"Role":      p.Role.String,
+		}
+	}
+	return lineup
+}
+
+// loadEvent reads an event with its venue, event type, lineup and promoters resolved.
+func loadEvent(ctx context.Context, q *database.Queries, id int32) (map[string]interface{}, error) {
+	event, err := q.GetEvent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	performers, err := q.ListEventPerformers(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	promoters, err := q.ListEventPromoters(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := mapEvent(event)
+	response["Performers"] = mapLineup(performers)
+	eventPromoters := make([]map[string]interface{}, len(promoters))
+	for i, p := range promoters {
+		eventPromoters[i] = map[string]interface{}{
+			"ID":      p.Promoter,
+			"Uuid":    p.Uuid,
+			"Name":    p.Name,
+			"Primary": p.Primary,
+		}
+	}
+	response["Promoters"] = eventPromoters
+	return response, nil
+}
+
+// --- Event Handlers ---
+
+func (a *API) CreateEvent(c *echo.Context) error {
+	var payload eventPayload
+	if err := c.Bind(&payload); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
+	}
+	if errs := payload.validate(); len(errs) > 0 {
+		return validationFailed(c, errs)
+	}
+
+	ctx := c.Request().Context()
+	var response map[string]interface{}
+	err := a.inTx(ctx, func(q *database.Queries) error {
+		params := database.CreateEventParams{
+			Name:      sql.NullString{String: "", Valid: false},
+			Venue:     payload.VenueID,
+			EventType: payload.EventTypeID,
+			Date:      payload.Date,
+		}
+		if payload.Name != nil {
+			params.Name = sql.NullString{String: *payload.Name, Valid: true}
+		}
+		event, err := q.CreateEvent(ctx, params)
+		if err != nil {
+			return err
+		}
+		if err := payload.writeLineup(ctx, q, event.ID); err != nil {
+			return err
+		}
+		response, err = loadEvent(ctx, q, event.ID)
+		return err
+	})
+	if err != nil {
+		if status, body, ok := constraintViolation(err); ok {
+			return c.JSON(status, body)
+		}
+		log.Printf("Error creating event: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to create event"})
+	}
+
+	return c.JSON(http.StatusCreated, response)
+}
+
+// eventSorts are the columns events can be sorted by with ?sort=.
+var eventSorts = []string{"date", "name", "venue", "created", "updated"}
+
+func (a *API) ListEvents(c *echo.Context) error {
+	list, err := parseListQuery(c, eventSorts, "-date")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	ctx := c.Request().Context()
+	total, err := a.queries.CountEvents(ctx, list.Query)
+	if err != nil {
+		log.Printf("Error counting events: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve events"})
+	}
+	params := database.ListEventsPageParams{Query: list.Query, Sort: list.Sort, MaxRows: list.Limit, Skip: list.Offset}
+	events, err := a.queries.ListEventsPage(ctx, params)
+	if err != nil {
+		log.Printf("Error listing events: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve events"})
+	}
+	setTotalCount(c, total)
+	response := make([]map[string]interface{}, len(events))
+	for i, e := range events {
+		response[i] = mapEvent(database.GetEventRow(e))
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+func (a *API) GetEvent(c *echo.Context) error {
+	id, err := rowID(c, a.queries.GetEventIDByUUID)
+	if err != nil {
+		return idError(c, err, "Event")
+	}
+
+	event, err := loadEvent(c.Request().Context(), a.queries, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Event not found"})
+		}
+		log.Printf("Error getting event: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve event"})
+	}
+
+	setETag(c, event["Updated"].(time.Time))
+	return c.JSON(http.StatusOK, event)
+}
+
+func (a *API) UpdateEvent(c *echo.Context) error {
+	id, err := rowID(c, a.queries.GetEventIDByUUID)
+	if err != nil {
+		return idError(c, err, "Event")
+	}
+
+	var payload eventPayload
+	if err := c.Bind(&payload); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
+	}
+	if errs := payload.validate(); len(errs) > 0 {
+		return validationFailed(c, errs)
+	}
+
+	ctx := c.Request().Context()
+	var response map[string]interface{}
+	err = a.writeIfMatch(c, id, (*database.Queries).LockEvent, func(q *database.Queries) error {
+		params := database.UpdateEventParams{
+			ID:        id,
+			Name:      sql.NullString{String: "", Valid: false},
+			Venue:     payload.VenueID,
+			EventType: payload.EventTypeID,
+			Date:      payload.Date,
+		}
+		if payload.Name != nil {
+			params.Name = sql.NullString{String: *payload.Name, Valid: true}
+		}
+		if _, err := q.UpdateEvent(ctx, params); err != nil {
+			return err
+		}
+		if err := payload.writeLineup(ctx, q, id); err != nil {
+			return err
+		}
+		response, err = loadEvent(ctx, q, id)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Event not found"})
+		}
+		if status, body, ok := preconditionFailed(err); ok {
+			return c.JSON(status, body)
+		}
+		if status, body, ok := constraintViolation(err); ok {
+			return c.JSON(status, body)
+		}
+		log.Printf("Error updating event: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to update event"})
+	}
+
+	setETag(c, response["Updated"].(time.Time))
+	return c.JSON(http.StatusOK, response)
+}
+
+func (a *API) DeleteEvent(c *echo.Context) error {
+	id, err := rowID(c, a.queries.GetEventIDByUUID)
+	if err != nil {
+		return idError(c, err, "Event")
+	}
+
+	// The lineup and promoters belong to the event, so they go with it
+	ctx := c.Request().Context()
+	err = a.writeIfMatch(c, id, (*database.Queries).LockEvent, func(q *database.Queries) error {
+		if err := q.DeleteEventPerformers(ctx, id); err != nil {
+			return err
+		}
+		if err := q.DeleteEventPromoters(ctx, id); err != nil {
+			return err
+		}
+		return q.DeleteEvent(ctx, id)
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Event not found"})
+		}
+		if status, body, ok := preconditionFailed(err); ok {
+			return c.JSON(status, body)
+		}
+		if status, body, ok := constraintViolation(err); ok {
+			return c.JSON(status, body)
+		}
+		log.Printf("Error deleting event: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to delete event"})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,91 @@
+package apiHandler
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestEventPayload_Validate(t *testing.T) {
+	valid := func() eventPayload {
+		return eventPayload{
+			VenueID:     3,
+			EventTypeID: 1,
+			Date:        time.Date(2025, 6, 14, 0, 0, 0, 0, time.UTC),
+			Performers: []eventPerformerPayload{
+				{PerformerID: 1, Slot: 2, Headliner: true},
+				{PerformerID: 2, Slot: 1, Role: "guest"},
+			},
+			Promoters: []eventPromoterPayload{{PromoterID: 20, Primary: true}},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		modify     func(p *eventPayload)
+		wantFields []string
+	}{
+		{name: "Valid", modify: func(p *eventPayload) {}},
+		{name: "No lineup or promoters", modify: func(p *eventPayload) { p.Performers, p.Promoters = nil, nil }},
+		{
+			name:       "Missing event fields",
+			modify:     func(p *eventPayload) { p.VenueID, p.EventTypeID, p.Date = 0, 0, time.Time{} },
+			wantFields: []string{"venue_id", "event_type_id", "date"},
+		},
+		{
+			name:       "Missing performer",
+			modify:     func(p *eventPayload) { p.Performers[1].PerformerID = 0 },
+			wantFields: []string{"performers[1].performer_id"},
+		},
+		{
+			name:       "Negative slot",
+			modify:     func(p *eventPayload) { p.Performers[0].Slot = -1 },
+			wantFields: []string{"performers[0].slot"},
+		},
+		{
+			name:       "Unknown role",
+			modify:     func(p *eventPayload) { p.Performers[1].Role = "warm-up" },
+			wantFields: []string{"performers[1].role"},
+		},
+		{
+			name:       "Performer twice",
+			modify:     func(p *eventPayload) { p.Performers[1].PerformerID = 1 },
+			wantFields: []string{"performers[1].performer_id"},
+		},
+		{
+			name:       "Slot twice",
+			modify:     func(p *eventPayload) { p.Performers[1].Slot = 2 },
+			wantFields: []string{"performers[1].slot"},
+		},
+		{
+			// The first performer takes slot 1 from its position, which the second names
+			name:       "Slot taken by position",
+			modify:     func(p *eventPayload) { p.Performers[0].Slot = 0 },
+			wantFields: []string{"performers[1].slot"},
+		},
+		{
+			name:   "Slots all from position",
+			modify: func(p *eventPayload) { p.Performers[0].Slot, p.Performers[1].Slot = 0, 0 },
+		},
+		{
+			name:       "Missing promoter",
+			modify:     func(p *eventPayload) { p.Promoters[0].PromoterID = 0 },
+			wantFields: []string{"promoters[0].promoter_id"},
+		},
+		{
+			name:       "Promoter twice",
+			modify:     func(p *eventPayload) { p.Promoters = append(p.Promoters, eventPromoterPayload{PromoterID: 20}) },
+			wantFields: []string{"promoters[1].promoter_id"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := valid()
+			tt.modify(&p)
+			if got := errorFields(p.validate()); !slices.Equal(got, tt.wantFields) {
+				t.Errorf("validate() fields = %v, want %v", got, tt.wantFields)
+			}
+		})
+	}
+}
@@ -0,0 +1,358 @@
+package apiHandler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/66james99/gig-calendar/internal/database"
+	"github.com/labstack/echo/v5"
+)
+
+// The lineup of an event can be edited a performer at a time under
+// /events/:id/performers, without resending the whole event. Each change runs in a
+// transaction and responds with the event's lineup as it then stands. The lineup belongs
+// to the event and shares its version: a change is checked against If-Match like an update
+// of the event, bumps the event's updated time, and responds with the event's new ETag.
+
+var errEventNotFound = errors.New("event not found")
+
+// reorderPayload defines the JSON body for setting the running order of an event. It lists
+// every performer in the lineup, first on stage first.
+type reorderPayload struct {
+	Performers []int32 `json:"performers"`
+}
+
+func (p reorderPayload) validate() validationErrors {
+	var errs validationErrors
+	seen := make(map[int32]bool, len(p.Performers))
+	for i, performer := range p.Performers {
+		if seen[performer] {
+			errs.add(fmt.Sprintf("performers[%d]", i), "performer %d appears more than once in the running order", performer)
+		}
+		seen[performer] = true
+	}
+	return errs
+}
+
+// lineupParams reads the event and performer of a lineup request, each given by ID or UUID.
+func (a *API) lineupParams(c *echo.Context) (event, performer int32, err error) {
+	ctx := c.Request().Context()
+	event, err = parseRowID(ctx, c.Param("id"), a.queries.GetEventIDByUUID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, 0, errEventNotFound
+	}
+	if err != nil || c.Param("performer") == "" {
+		return event, 0, err
+	}
+	performer, err = parseRowID(ctx, c.Param("performer"), a.queries.GetPerformerIDByUUID)
+	return event, performer, err
+}
+
+// lineupError responds to an error from a lineup transaction.
+func lineupError(c *echo.Context, err error, action string) error {
+	var conflict conflictError
+	switch {
+	case errors.Is(err, errInvalidID):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+	case errors.As(err, &conflict):
+		return c.JSON(http.StatusConflict, map[string]string{"error": conflict.Error()})
+	case errors.Is(err, errEventNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Event not found"})
+	case errors.Is(err, sql.ErrNoRows):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Performer is not in the lineup of this event"})
+	}
+	if status, body, ok := preconditionFailed(err); ok {
+		return c.JSON(status, body)
+	}
+	if status, body, ok := constraintViolation(err); ok {
+		return c.JSON(status, body)
+	}
+	log.Printf("Error trying to %s: %v", action, err)
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to " + action})
+}
+
+// eventLineup reads the lineup of event and when the event was last updated, reporting
+// errEventNotFound if there is no such event.
+func eventLineup(ctx context.Context, q *database.Queries, event int32) ([]database.ListEventPerformersRow, time.Time, error) {
+	e, err := q.GetEvent(ctx, event)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, time.Time{}, errEventNotFound
+		}
+		return nil, time.Time{}, err
+	}
+	lineup, err := q.ListEventPerformers(ctx, event)
+	return lineup, e.Updated, err
+}
+
+// lockEvent locks event for a change to its lineup until the transaction of q ends, and
+// checks the If-Match header of the request against the event.
+func (a *API) lockEvent(c *echo.Context, q *database.Queries, event int32) error {
+	err := a.lockIfMatch(c, q, event, (*database.Queries).LockEvent)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errEventNotFound
+	}
+	return err
+}
+
+// touchedLineup reads the lineup of event once it has been changed, marking the event
+// updated so its ETag changes with its lineup.
+func touchedLineup(ctx context.Context, q *database.Queries, event int32) ([]database.ListEventPerformersRow, time.Time, error) {
+	updated, err := q.TouchEvent(ctx, event)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	lineup, err := q.ListEventPerformers(ctx, event)
+	return lineup, updated, err
+}
+
+// setSlots moves the performers of event to the given slots. Every slot of the event is
+// parked before any is set, so slots can be swapped or rotated without two performers
+// ever holding the same slot, which UNIQUE (event, slot) would reject.
+func setSlots(ctx context.Context, q *database.Queries, event int32, slots map[int32]int32) error {
+	if err := q.ParkEventPerformerSlots(ctx, event); err != nil {
+		return err
+	}
+	for performer, slot := range slots {
+		params := database.SetEventPerformerSlotParams{Event: event, Performer: performer, Slot: slot}
+		if _, err := q.SetEventPerformerSlot(ctx, params); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// --- Event Performer Handlers ---
+
+func (a *API) ListEventPerformers(c *echo.Context) error {
+	event, _, err := a.lineupParams(c)
+	if err != nil {
+		return lineupError(c, err, "retrieve lineup")
+	}
+
+	lineup, updated, err := eventLineup(c.Request().Context(), a.queries, event)
+	if err != nil {
+		return lineupError(c, err, "retrieve lineup")
+	}
+	setETag(c, updated)
+	return c.JSON(http.StatusOK, mapLineup(lineup))
+}
+
+// AddEventPerformer adds a performer to the lineup of an event, after the current lineup
+// unless the payload gives a free slot.
+func (a *API) AddEventPerformer(c *echo.Context) error {
+	event, _, err := a.lineupParams(c)
+	if err != nil {
+		return lineupError(c, err, "add performer to lineup")
+	}
+
+	var payload eventPerformerPayload
+	if err := c.Bind(&payload); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
+	}
+	if errs := payload.validate(""); len(errs) > 0 {
+		return validationFailed(c, errs)
+	}
+
+	ctx := c.Request().Context()
+	var lineup []database.ListEventPerformersRow
+	var updated time.Time
+	err = a.inTx(ctx, func(q *database.Queries) error {
+		if err := a.lockEvent(c, q, event); err != nil {
+			return err
+		}
+		current, _, err := eventLineup(ctx, q, event)
+		if err != nil {
+			return err
+		}
+		for _, ep := range current {
+			if ep.Performer == payload.PerformerID {
+				return conflictError(fmt.Sprintf("%s is already in the lineup of this event", ep.Name))
+			}
+			// Slot 0 asks for the slot after the lineup, so cannot clash with one taken
+			if payload.Slot != 0 && ep.Slot == payload.Slot {
+				return conflictError(fmt.Sprintf("Slot %d is already taken by %s", ep.Slot, ep.Name))
+			}
+		}
+
+		params := database.CreateEventPerformerParams{
+			Event:     event,
+			Performer: payload.PerformerID,
+			Headliner: payload.Headliner,
+			Slot:      payload.Slot,
+			Role:      sql.NullString{String: payload.Role, Valid: payload.Role != ""},
+		}
+		if params.Slot == 0 {
+			if params.Slot, err = q.NextEventPerformerSlot(ctx, event); err != nil {
+				return err
+			}
+		}
+		if _, err := q.CreateEventPerformer(ctx, params); err != nil {
+			return err
+		}
+		lineup, updated, err = touchedLineup(ctx, q, event)
+		return err
+	})
+	if err != nil {
+		return lineupError(c, err, "add performer to lineup")
+	}
+
+	setETag(c, updated)
+	return c.JSON(http.StatusCreated, mapLineup(lineup))
+}
+
+// UpdateEventPerformer sets the headliner flag and role of a performer in the lineup. A
+// slot in the payload moves the performer there, swapping places with whoever holds it.
+func (a *API) UpdateEventPerformer(c *echo.Context) error {
+	event, performer, err := a.lineupParams(c)
+	if err != nil {
+		return lineupError(c, err, "update performer in lineup")
+	}
+
+	var payload eventPerformerPayload
+	if err := c.Bind(&payload); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
+	}
+	payload.PerformerID = performer // given by the URL rather than the body
+	if errs := payload.validate(""); len(errs) > 0 {
+		return validationFailed(c, errs)
+	}
+
+	ctx := c.Request().Context()
+	var lineup []database.ListEventPerformersRow
+	var updated time.Time
+	err = a.inTx(ctx, func(q *database.Queries) error {
+		if err := a.lockEvent(c, q, event); err != nil {
+			return err
+		}
+		current, _, err := eventLineup(ctx, q, event)
+		if err != nil {
+			return err
+		}
+		params := database.UpdateEventPerformerParams{
+			Event:     event,
+			Performer: performer,
+			Headliner: payload.Headliner,
+			Role:      sql.NullString{String: payload.Role, Valid: payload.Role != ""},
+		}
+		ep, err := q.UpdateEventPerformer(ctx, params)
+		if err != nil {
+			return err
+		}
+
+		if payload.Slot != 0 && payload.Slot != ep.Slot {
+			slots := make(map[int32]int32, len(current))
+			for _, other := range current {
+				slots[other.Performer] = other.Slot
+				if other.Slot == payload.Slot {
+					slots[other.Performer] = ep.Slot
+				}
+			}
+			slots[performer] = payload.Slot
+			if err := setSlots(ctx, q, event, slots); err != nil {
+				return err
+			}
+		}
+
+		lineup, updated, err = touchedLineup(ctx, q, event)
+		return err
+	})
+	if err != nil {
+		return lineupError(c, err, "update performer in lineup")
+	}
+
+	setETag(c, updated)
+	return c.JSON(http.StatusOK, mapLineup(lineup))
+}
+
+// ReorderEventPerformers sets the running order of an event, numbering the slots from 1.
+// The payload must list the lineup as stored, so an order based on a stale copy of the
+// lineup is refused rather than dropping or reviving a performer.
+func (a *API) ReorderEventPerformers(c *echo.Context) error {
+	event, _, err := a.lineupParams(c)
+	if err != nil {
+		return lineupError(c, err, "reorder lineup")
+	}
+
+	var payload reorderPayload
+	if err := c.Bind(&payload); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
+	}
+	if errs := payload.validate(); len(errs) > 0 {
+		return validationFailed(c, errs)
+	}
+	slots := make(map[int32]int32, len(payload.Performers))
+	for i, performer := range payload.Performers {
+		slots[performer] = int32(i + 1)
+	}
+
+	ctx := c.Request().Context()
+	var lineup []database.ListEventPerformersRow
+	var updated time.Time
+	err = a.inTx(ctx, func(q *database.Queries) error {
+		if err := a.lockEvent(c, q, event); err != nil {
+			return err
+		}
+		current, _, err := eventLineup(ctx, q, event)
+		if err != nil {
+			return err
+		}
+		if len(current) != len(slots) {
+			return conflictError(fmt.Sprintf("The running order lists %d performers but the lineup has %d", len(slots), len(current)))
+		}
+		for _, ep := range current {
+			if _, ok := slots[ep.Performer]; !ok {
+				return conflictError(fmt.Sprintf("The running order leaves out %s, who is in the lineup", ep.Name))
+			}
+		}
+
+		if err := setSlots(ctx, q, event, slots); err != nil {
+			return err
+		}
+		lineup, updated, err = touchedLineup(ctx, q, event)
+		return err
+	})
+	if err != nil {
+		return lineupError(c, err, "reorder lineup")
+	}
+
+	setETag(c, updated)
+	return c.JSON(http.StatusOK, mapLineup(lineup))
+}
+
+func (a *API) RemoveEventPerformer(c *echo.Context) error {
+	event, performer, err := a.lineupParams(c)
+	if err != nil {
+		return lineupError(c, err, "remove performer from lineup")
+	}
+
+	ctx := c.Request().Context()
+	var lineup []database.ListEventPerformersRow
+	var updated time.Time
+	err = a.inTx(ctx, func(q *database.Queries) error {
+		if err := a.lockEvent(c, q, event); err != nil {
+			return err
+		}
+		params := database.DeleteEventPerformerParams{Event: event, Performer: performer}
+		n, err := q.DeleteEventPerformer(ctx, params)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return sql.ErrNoRows
+		}
+		lineup, updated, err = touchedLineup(ctx, q, event)
+		return err
+	})
+	if err != nil {
+		return lineupError(c, err, "remove performer from lineup")
+	}
+
+	setETag(c, updated)
+	return c.JSON(http.StatusOK, mapLineup(lineup))
+}
@@ -0,0 +1,28 @@
+package apiHandler
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestReorderPayload_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		performers []int32
+		wantFields []string
+	}{
+		{name: "Running order", performers: []int32{3, 1, 2}},
+		{name: "Empty", performers: nil},
+		{name: "Performer twice", performers: []int32{3, 1, 3}, wantFields: []string{"performers[2]"}},
+		{name: "Performer three times", performers: []int32{1, 1, 1}, wantFields: []string{"performers[1]", "performers[2]"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := reorderPayload{Performers: tt.performers}
+			if got := errorFields(p.validate()); !slices.Equal(got, tt.wantFields) {
+				t.Errorf("validate() fields = %v, want %v", got, tt.wantFields)
+			}
+		})
+	}
+}
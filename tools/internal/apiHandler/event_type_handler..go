@@ -4,7 +4,6 @@ import (
 	"database/sql"
 	"log"
 	"net/http"
-	"strconv"
 
 	"github.com/66james99/gig-calendar/internal/database"
 	"github.com/labstack/echo/v5"
@@ -14,18 +13,26 @@ type eventTypePayload struct {
 	Name string `json:"name"`
 }
 
+func (p eventTypePayload) validate() validationErrors {
+	var errs validationErrors
+	errs.required("name", p.Name)
+	return errs
+}
+
 func (a *API) CreateEventType(c *echo.Context) error {
 	var payload eventTypePayload
 	if err := c.Bind(&payload); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
 	}
-
-	if payload.Name == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Name is required"})
+	if errs := payload.validate(); len(errs) > 0 {
+		return validationFailed(c, errs)
 	}
 
 	eventType, err := a.queries.CreateEventType(c.Request().Context(), payload.Name)
 	if err != nil {
+		if status, body, ok := constraintViolation(err); ok {
+			return c.JSON(status, body)
+		}
 		log.Printf("Error creating event type: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to create event type"})
 	}
@@ -33,22 +40,38 @@ func (a *API) CreateEventType(c *echo.Context) error {
 	return c.JSON(http.StatusCreated, eventType)
 }
 
+// eventTypeSorts are the columns event types can be sorted by with ?sort=.
+var eventTypeSorts = []string{"name"}
+
 func (a *API) ListEventTypes(c *echo.Context) error {
-	eventTypes, err := a.queries.ListEventTypes(c.Request().Context())
+	list, err := parseListQuery(c, eventTypeSorts, "name")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	ctx := c.Request().Context()
+	total, err := a.queries.CountEventTypes(ctx, list.Query)
+	if err != nil {
+		log.Printf("Error counting event types: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve event types"})
+	}
+	params := database.ListEventTypesPageParams{Query: list.Query, Sort: list.Sort, MaxRows: list.Limit, Skip: list.Offset}
+	eventTypes, err := a.queries.ListEventTypesPage(ctx, params)
 	if err != nil {
 		log.Printf("Error listing event types: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve event types"})
 	}
+	setTotalCount(c, total)
 	return c.JSON(http.StatusOK, eventTypes)
 }
 
 func (a *API) GetEventType(c *echo.Context) error {
-	id, err := strconv.Atoi(c.Param("id"))
+	id, err := rowID(c, a.queries.GetEventTypeIDByUUID)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+		return idError(c, err, "Event type")
 	}
 
-	eventType, err := a.queries.GetEventType(c.Request().Context(), int32(id))
+	eventType, err := a.queries.GetEventType(c.Request().Context(), id)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return c.JSON(http.StatusNotFound, map[string]string{"error": "Event type not found"})
@@ -57,48 +80,71 @@ func (a *API) GetEventType(c *echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve event type"})
 	}
 
+	setETag(c, eventType.Updated)
 	return c.JSON(http.StatusOK, eventType)
 }
 
 func (a *API) UpdateEventType(c *echo.Context) error {
-	id, err := strconv.Atoi(c.Param("id"))
+	id, err := rowID(c, a.queries.GetEventTypeIDByUUID)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+		return idError(c, err, "Event type")
 	}
 
 	var payload eventTypePayload
 	if err := c.Bind(&payload); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
 	}
-
-	if payload.Name == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Name is required"})
+	if errs := payload.validate(); len(errs) > 0 {
+		return validationFailed(c, errs)
 	}
 
 	params := database.UpdateEventTypeParams{
-		ID:   int32(id),
+		ID:   id,
 		Name: payload.Name,
 	}
 
-	updatedEventType, err := a.queries.UpdateEventType(c.Request().Context(), params)
+	var updatedEventType database.EventType
+	err = a.writeIfMatch(c, id, (*database.Queries).LockEventType, func(q *database.Queries) (err error) {
+		updatedEventType, err = q.UpdateEventType(c.Request().Context(), params)
+		return err
+	})
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return c.JSON(http.StatusNotFound, map[string]string{"error": "Event type not found"})
 		}
+		if status, body, ok := preconditionFailed(err); ok {
+			return c.JSON(status, body)
+		}
+		if status, body, ok := constraintViolation(err); ok {
+			return c.JSON(status, body)
+		}
 		log.Printf("Error updating event type: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to update event type"})
 	}
 
+	setETag(c, updatedEventType.Updated)
 	return c.JSON(http.StatusOK, updatedEventType)
 }
 
 func (a *API) DeleteEventType(c *echo.Context) error {
-	id, err := strconv.Atoi(c.Param("id"))
+	id, err := rowID(c, a.queries.GetEventTypeIDByUUID)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+		return idError(c, err, "Event type")
 	}
 
-	if err := a.queries.DeleteEventType(c.Request().Context(), int32(id)); err != nil {
+	err = a.writeIfMatch(c, id, (*database.Queries).LockEventType, func(q *database.Queries) error {
+		return q.DeleteEventType(c.Request().Context(), id)
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Event type not found"})
+		}
+		if status, body, ok := preconditionFailed(err); ok {
+			return c.JSON(status, body)
+		}
+		if status, body, ok := constraintViolation(err); ok {
+			return c.JSON(status, body)
+		}
 		log.Printf("Error deleting event type: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to delete event type"})
 	}
@@ -4,7 +4,6 @@ import (
 	"database/sql"
 	"log"
 	"net/http"
-	"strconv"
 	
 	"github.com/66james99/gig-calendar/internal/database"
 	"github.com/labstack/echo/v5"
@@ -15,6 +14,13 @@ type festivalAliasPayload struct {
 	Alias      string `json:"alias"`
 }
 
+func (p festivalAliasPayload) validate() validationErrors {
+	var errs validationErrors
+	errs.requiredID("festival_id", p.FestivalID)
+	errs.required("alias", p.Alias)
+	return errs
+}
+
 // --- Festival Alias Handlers ---
 
 func (a *API) CreateFestivalAlias(c *echo.Context) error {
@@ -22,6 +28,9 @@ func (a *API) CreateFestivalAlias(c *echo.Context) error {
 	if err := c.Bind(&payload); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
 	}
+	if errs := payload.validate(); len(errs) > 0 {
+		return validationFailed(c, errs)
+	}
 
 	params := database.CreateFestivalAliasParams{
 		Festival:   payload.FestivalID,
@@ -30,6 +39,9 @@ func (a *API) CreateFestivalAlias(c *echo.Context) error {
 
 	alias, err := a.queries.CreateFestivalAlias(c.Request().Context(), params)
 	if err != nil {
+		if status, body, ok := constraintViolation(err); ok {
+			return c.JSON(status, body)
+		}
 		log.Printf("Error creating festival alias: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to create festival alias"})
 	}
@@ -37,22 +49,38 @@ func (a *API) CreateFestivalAlias(c *echo.Context) error {
 	return c.JSON(http.StatusCreated, alias)
 }
 
+// festivalAliasSorts are the columns festival aliases can be sorted by with ?sort=.
+var festivalAliasSorts = []string{"alias", "created", "updated"}
+
 func (a *API) ListFestivalAliases(c *echo.Context) error {
-	aliases, err := a.queries.ListFestivalAliases(c.Request().Context())
+	list, err := parseListQuery(c, festivalAliasSorts, "alias")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	ctx := c.Request().Context()
+	total, err := a.queries.CountFestivalAliases(ctx, list.Query)
+	if err != nil {
+		log.Printf("Error counting festival aliases: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve festival aliases"})
+	}
+	params := database.ListFestivalAliasesPageParams{Query: list.Query, Sort: list.Sort, MaxRows: list.Limit, Skip: list.Offset}
+	aliases, err := a.queries.ListFestivalAliasesPage(ctx, params)
 	if err != nil {
 		log.Printf("Error listing festival aliases: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve festival aliases"})
 	}
+	setTotalCount(c, total)
 	return c.JSON(http.StatusOK, aliases)
 }
 
 func (a *API) GetFestivalAlias(c *echo.Context) error {
-	id, err := strconv.Atoi(c.Param("id"))
+	id, err := rowID(c, a.queries.GetFestivalAliasIDByUUID)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+		return idError(c, err, "Festival alias")
 	}
 
-	alias, err := a.queries.GetFestivalAlias(c.Request().Context(), int32(id))
+	alias, err := a.queries.GetFestivalAlias(c.Request().Context(), id)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return c.JSON(http.StatusNotFound, map[string]string{"error": "Festival alias not found"})
@@ -61,46 +89,72 @@ func (a *API) GetFestivalAlias(c *echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve festival alias"})
 	}
 
+	setETag(c, alias.Updated)
 	return c.JSON(http.StatusOK, alias)
 }
 
 func (a *API) UpdateFestivalAlias(c *echo.Context) error {
-	id, err := strconv.Atoi(c.Param("id"))
+	id, err := rowID(c, a.queries.GetFestivalAliasIDByUUID)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+		return idError(c, err, "Festival alias")
 	}
 
 	var payload festivalAliasPayload
 	if err := c.Bind(&payload); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
 	}
+	if errs := payload.validate(); len(errs) > 0 {
+		return validationFailed(c, errs)
+	}
 
 	params := database.UpdateFestivalAliasParams{
-		ID:         int32(id),
+		ID:         id,
 		Festival:   payload.FestivalID,
 		Alias:      payload.Alias,
 	}
 
-	updatedAlias, err := a.queries.UpdateFestivalAlias(c.Request().Context(), params)
+	var updatedAlias database.FestivalAlias
+	err = a.writeIfMatch(c, id, (*database.Queries).LockFestivalAlias, func(q *database.Queries) (err error) {
+		updatedAlias, err = q.UpdateFestivalAlias(c.Request().Context(), params)
+		return err
+	})
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return c.JSON(http.StatusNotFound, map[string]string{"error": "Festival alias not found"})
 		}
+		if status, body, ok := preconditionFailed(err); ok {
+			return c.JSON(status, body)
+		}
+		if status, body, ok := constraintViolation(err); ok {
+			return c.JSON(status, body)
+		}
 		log.Printf("Error updating festival alias: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to update festival alias"})
 	}
 
+	setETag(c, updatedAlias.Updated)
 	return c.JSON(http.StatusOK, updatedAlias)
 }
 
 func (a *API) DeleteFestivalAlias(c *echo.Context) error {
-	id, err := strconv.Atoi(c.Param("id"))
+	id, err := rowID(c, a.queries.GetFestivalAliasIDByUUID)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+		return idError(c, err, "Festival alias")
 	}
 
-	err = a.queries.DeleteFestivalAlias(c.Request().Context(), int32(id))
+	err = a.writeIfMatch(c, id, (*database.Queries).LockFestivalAlias, func(q *database.Queries) error {
+		return q.DeleteFestivalAlias(c.Request().Context(), id)
+	})
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Festival alias not found"})
+		}
+		if status, body, ok := preconditionFailed(err); ok {
+			return c.JSON(status, body)
+		}
+		if status, body, ok := constraintViolation(err); ok {
+			return c.JSON(status, body)
+		}
 		log.Printf("Error deleting festival alias: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to delete festival alias"})
 	}
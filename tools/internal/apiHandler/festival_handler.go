@@ -4,7 +4,7 @@ import (
 	"database/sql"
 	"log"
 	"net/http"
-	"strconv"
+	"strings"
 	"time"
 
 	"github.com/66james99/gig-calendar/internal/database"
@@ -20,6 +20,18 @@ type festivalPayload struct {
 	Description *string   `json:"description"`
 }
 
+func (p festivalPayload) validate() validationErrors {
+	var errs validationErrors
+	errs.required("name", p.Name)
+	errs.requiredID("promoter_id", p.PromoterID)
+	errs.requiredDate("start_date", p.StartDate)
+	errs.requiredDate("end_date", p.EndDate)
+	if !p.StartDate.IsZero() && p.EndDate.Before(p.StartDate) {
+		errs.add("end_date", "must not be before start_date")
+	}
+	return errs
+}
+
 // festivalAliasPayload defines the JSON body for creating/updating a festival alias.
 
 
@@ -32,6 +44,8 @@ func mapFestival(f database.Festival) map[string]interface{} {
 		"EndDate":     f.EndDate,
 		"Description": f.Description.String,
 		"Uuid":        f.Uuid,
+		"Created":     f.Created,
+		"Updated":     f.Updated,
 	}
 }
 
@@ -42,6 +56,9 @@ func (a *API) CreateFestival(c *echo.Context) error {
 	if err := c.Bind(&payload); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
 	}
+	if errs := payload.validate(); len(errs) > 0 {
+		return validationFailed(c, errs)
+	}
 
 	params := database.CreateFestivalParams{
 		Name:        payload.Name,
@@ -56,6 +73,9 @@ func (a *API) CreateFestival(c *echo.Context) error {
 
 	festival, err := a.queries.CreateFestival(c.Request().Context(), params)
 	if err != nil {
+		if status, body, ok := constraintViolation(err); ok {
+			return c.JSON(status, body)
+		}
 		log.Printf("Error creating festival: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to create festival"})
 	}
@@ -63,12 +83,28 @@ func (a *API) CreateFestival(c *echo.Context) error {
 	return c.JSON(http.StatusCreated, mapFestival(festival))
 }
 
+// festivalSorts are the columns festivals can be sorted by with ?sort=.
+var festivalSorts = []string{"start_date", "end_date", "name", "created", "updated"}
+
 func (a *API) ListFestivals(c *echo.Context) error {
-	festivals, err := a.queries.ListFestivals(c.Request().Context())
+	list, err := parseListQuery(c, festivalSorts, "-start_date")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	ctx := c.Request().Context()
+	total, err := a.queries.CountFestivals(ctx, list.Query)
+	if err != nil {
+		log.Printf("Error counting festivals: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve festivals"})
+	}
+	params := database.ListFestivalsPageParams{Query: list.Query, Sort: list.Sort, MaxRows: list.Limit, Skip: list.Offset}
+	festivals, err := a.queries.ListFestivalsPage(ctx, params)
 	if err != nil {
 		log.Printf("Error listing festivals: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve festivals"})
 	}
+	setTotalCount(c, total)
 	response := make([]map[string]interface{}, len(festivals))
 	for i, f := range festivals {
 		response[i] = mapFestival(f)
@@ -77,12 +113,28 @@ func (a *API) ListFestivals(c *echo.Context) error {
 }
 
 func (a *API) GetFestival(c *echo.Context) error {
-	id, err := strconv.Atoi(c.Param("id"))
+	id, err := rowID(c, a.queries.GetFestivalIDByUUID)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+		return idError(c, err, "Festival")
+	}
+
+	// ?embed=venues,promoters adds the festival's venues and promoters to the response
+	var embedVenues, embedPromoters bool
+	if embed := c.QueryParam("embed"); embed != "" {
+		for _, e := range strings.Split(embed, ",") {
+			switch strings.TrimSpace(e) {
+			case "venues":
+				embedVenues = true
+			case "promoters":
+				embedPromoters = true
+			default:
+				return c.JSON(http.StatusBadRequest, map[string]string{"error": "embed must list venues and/or promoters"})
+			}
+		}
 	}
 
-	festival, err := a.queries.GetFestival(c.Request().Context(), int32(id))
+	ctx := c.Request().Context()
+	festival, err := a.queries.GetFestival(ctx, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return c.JSON(http.StatusNotFound, map[string]string{"error": "Festival not found"})
@@ -91,22 +143,44 @@ func (a *API) GetFestival(c *echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve festival"})
 	}
 
-	return c.JSON(http.StatusOK, mapFestival(festival))
+	setETag(c, festival.Updated)
+	response := mapFestival(festival)
+	if embedVenues {
+		venues, err := a.queries.ListFestivalVenues(ctx, festival.ID)
+		if err != nil {
+			log.Printf("Error listing festival venues: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve festival"})
+		}
+		response["Venues"] = mapFestivalVenues(venues)
+	}
+	if embedPromoters {
+		promoters, err := a.queries.ListFestivalPromoters(ctx, festival.ID)
+		if err != nil {
+			log.Printf("Error listing festival promoters: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve festival"})
+		}
+		response["Promoters"] = mapFestivalPromoters(promoters)
+	}
+
+	return c.JSON(http.StatusOK, response)
 }
 
 func (a *API) UpdateFestival(c *echo.Context) error {
-	id, err := strconv.Atoi(c.Param("id"))
+	id, err := rowID(c, a.queries.GetFestivalIDByUUID)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+		return idError(c, err, "Festival")
 	}
 
 	var payload festivalPayload
 	if err := c.Bind(&payload); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
 	}
+	if errs := payload.validate(); len(errs) > 0 {
+		return validationFailed(c, errs)
+	}
 
 	params := database.UpdateFestivalParams{
-		ID:          int32(id),
+		ID:          id,
 		Name:        payload.Name,
 		Promoter:    payload.PromoterID,
 		StartDate:   payload.StartDate,
@@ -117,26 +191,48 @@ func (a *API) UpdateFestival(c *echo.Context) error {
 		params.Description = sql.NullString{String: *payload.Description, Valid: true}
 	}
 
-	updatedFestival, err := a.queries.UpdateFestival(c.Request().Context(), params)
+	var updatedFestival database.Festival
+	err = a.writeIfMatch(c, id, (*database.Queries).LockFestival, func(q *database.Queries) (err error) {
+		updatedFestival, err = q.UpdateFestival(c.Request().Context(), params)
+		return err
+	})
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return c.JSON(http.StatusNotFound, map[string]string{"error": "Festival not found"})
 		}
+		if status, body, ok := preconditionFailed(err); ok {
+			return c.JSON(status, body)
+		}
+		if status, body, ok := constraintViolation(err); ok {
+			return c.JSON(status, body)
+		}
 		log.Printf("Error updating festival: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to update festival"})
 	}
 
+	setETag(c, updatedFestival.Updated)
 	return c.JSON(http.StatusOK, mapFestival(updatedFestival))
 }
 
 func (a *API) DeleteFestival(c *echo.Context) error {
-	id, err := strconv.Atoi(c.Param("id"))
+	id, err := rowID(c, a.queries.GetFestivalIDByUUID)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+		return idError(c, err, "Festival")
 	}
 
-	err = a.queries.DeleteFestival(c.Request().Context(), int32(id))
+	err = a.writeIfMatch(c, id, (*database.Queries).LockFestival, func(q *database.Queries) error {
+		return q.DeleteFestival(c.Request().Context(), id)
+	})
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Festival not found"})
+		}
+		if status, body, ok := preconditionFailed(err); ok {
+			return c.JSON(status, body)
+		}
+		if status, body, ok := constraintViolation(err); ok {
+			return c.JSON(status, body)
+		}
 		log.Printf("Error deleting festival: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to delete festival"})
 	}
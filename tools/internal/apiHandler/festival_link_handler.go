@@ -0,0 +1,465 @@
+package apiHandler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/66james99/gig-calendar/internal/database"
+	"github.com/labstack/echo/v5"
+)
+
+// The venues (stages) and co-promoters of a festival are managed under
+// /festivals/:id/venues and /festivals/:id/promoters. Each change responds with the
+// festival's venues or promoters as they then stand. Like the lineup of an event, they
+// share the festival's version: a change is checked against If-Match like an update of
+// the festival, bumps its updated time, and responds with its new ETag.
+
+var errFestivalNotFound = errors.New("festival not found")
+
+// festivalVenuePayload defines the JSON body for linking a venue to a festival. A festival
+// has at most one primary venue, so making one primary clears the flag on the others.
+type festivalVenuePayload struct {
+	VenueID    int32  `json:"venue_id"`
+	StageOrder *int32 `json:"stage_order"`
+	Primary    bool   `json:"primary"`
+}
+
+func (p festivalVenuePayload) validate() validationErrors {
+	var errs validationErrors
+	errs.requiredID("venue_id", p.VenueID)
+	if p.StageOrder != nil && *p.StageOrder < 1 {
+		errs.add("stage_order", "must be at least 1")
+	}
+	return errs
+}
+
+// festivalPromoterPayload defines the JSON body for linking a promoter to a festival.
+type festivalPromoterPayload struct {
+	PromoterID int32   `json:"promoter_id"`
+	Role       *string `json:"role"`
+}
+
+func (p festivalPromoterPayload) validate() validationErrors {
+	var errs validationErrors
+	errs.requiredID("promoter_id", p.PromoterID)
+	return errs
+}
+
+// festivalLinkParams reads the festival of a request and the venue or promoter named by
+// param, if the route has one, each given by ID or UUID. lookup finds the linked row by UUID.
+func (a *API) festivalLinkParams(c *echo.Context, param string, lookup idLookup) (festival, linked int32, err error) {
+	ctx := c.Request().Context()
+	festival, err = parseRowID(ctx, c.Param("id"), a.queries.GetFestivalIDByUUID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, 0, errFestivalNotFound
+	}
+	if err != nil || c.Param(param) == "" {
+		return festival, 0, err
+	}
+	linked, err = parseRowID(ctx, c.Param(param), lookup)
+	return festival, linked, err
+}
+
+// festivalLinkError responds to an error from a festival venue or promoter transaction.
+func festivalLinkError(c *echo.Context, err error, linked, action string) error {
+	var conflict conflictError
+	switch {
+	case errors.Is(err, errInvalidID):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+	case errors.As(err, &conflict):
+		return c.JSON(http.StatusConflict, map[string]string{"error": conflict.Error()})
+	case errors.Is(err, errFestivalNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Festival not found"})
+	case errors.Is(err, sql.ErrNoRows):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": linked + " is not linked to this festival"})
+	}
+	if status, body, ok := preconditionFailed(err); ok {
+		return c.JSON(status, body)
+	}
+	if status, body, ok := constraintViolation(err); ok {
+		return c.JSON(status, body)
+	}
+	log.Printf("Error trying to %s: %v", action, err)
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to " + action})
+}
+
+// checkFestival reports when the festival with the given ID was last updated, or
+// errFestivalNotFound if there is no such festival.
+func checkFestival(ctx context.Context, q *database.Queries, festival int32) (time.Time, error) {
+	f, err := q.GetFestival(ctx, festival)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, errFestivalNotFound
+	}
+	return f.Updated, err
+}
+
+// lockFestival locks festival for a change to its venues or promoters until the
+// transaction of q ends, and checks the If-Match header of the request against it.
+func (a *API) lockFestival(c *echo.Context, q *database.Queries, festival int32) error {
+	err := a.lockIfMatch(c, q, festival, (*database.Queries).LockFestival)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errFestivalNotFound
+	}
+	return err
+}
+
+func mapFestivalVenues(venues []database.ListFestivalVenuesRow) []map[string]interface{} {
+	response := make([]map[string]interface{}, len(venues))
+	for i, v := range venues {
+		var stageOrder interface{}
+		if v.StageOrder.Valid {
+			stageOrder = v.StageOrder.Int32
+		}
+		response[i] = map[string]interface{}{
+			"ID":         v.Venue,
+			"Uuid":       v.Uuid,
+			"Name":       v.Name,
+			"City":       v.City,
+			"StageOrder": stageOrder,
+			"Primary":    v.IsPrimary.Bool,
+		}
+	}
+	return response
+}
+
+func mapFestivalPromoters(promoters []database.ListFestivalPromotersRow) []map[string]interface{} {
+	response := make([]map[string]interface{}, len(promoters))
+	for i, p := range promoters {
+		response[i] = map[string]interface{}{
+			"ID":   p.Promoter,
+			"Uuid": p.Uuid,
+			"Name": p.Name,
+			"Role": p.Role.String,
+		}
+	}
+	return response
+}
+
+// write stores the stage order and primary flag of a venue already linked to festival.
+func (p festivalVenuePayload) write(ctx context.Context, q *database.Queries, festival, venue int32) error {
+	params := database.UpdateFestivalVenueParams{
+		Festival:   festival,
+		Venue:      venue,
+		StageOrder: sql.NullInt32{Int32: 0, Valid: false},
+		IsPrimary:  sql.NullBool{Bool: p.Primary, Valid: true},
+	}
+	if p.StageOrder != nil {
+		params.StageOrder = sql.NullInt32{Int32: *p.StageOrder, Valid: true}
+	}
+	if _, err := q.UpdateFestivalVenue(ctx, params); err != nil {
+		return err
+	}
+	if p.Primary {
+		return q.ClearFestivalVenuePrimary(ctx, database.ClearFestivalVenuePrimaryParams{Festival: festival, Venue: venue})
+	}
+	return nil
+}
+
+// --- Festival Venue Handlers ---
+
+func (a *API) ListFestivalVenues(c *echo.Context) error {
+	festival, _, err := a.festivalLinkParams(c, "venue", a.queries.GetVenueIDByUUID)
+	if err != nil {
+		return festivalLinkError(c, err, "Venue", "retrieve festival venues")
+	}
+
+	ctx := c.Request().Context()
+	updated, err := checkFestival(ctx, a.queries, festival)
+	if err != nil {
+		return festivalLinkError(c, err, "Venue", "retrieve festival venues")
+	}
+	venues, err := a.queries.ListFestivalVenues(ctx, festival)
+	if err != nil {
+		return festivalLinkError(c, err, "Venue", "retrieve festival venues")
+	}
+	setETag(c, updated)
+	return c.JSON(http.StatusOK, mapFestivalVenues(venues))
+}
+
+func (a *API) AddFestivalVenue(c *echo.Context) error {
+	festival, _, err := a.festivalLinkParams(c, "venue", a.queries.GetVenueIDByUUID)
+	if err != nil {
+		return festivalLinkError(c, err, "Venue", "add festival venue")
+	}
+
+	var payload festivalVenuePayload
+	if err := c.Bind(&payload); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
+	}
+	if errs := payload.validate(); len(errs) > 0 {
+		return validationFailed(c, errs)
+	}
+
+	ctx := c.Request().Context()
+	var venues []database.ListFestivalVenuesRow
+	var updated time.Time
+	err = a.inTx(ctx, func(q *database.Queries) error {
+		if err := a.lockFestival(c, q, festival); err != nil {
+			return err
+		}
+		current, err := q.ListFestivalVenues(ctx, festival)
+		if err != nil {
+			return err
+		}
+		for _, v := range current {
+			if v.Venue == payload.VenueID {
+				return conflictError(fmt.Sprintf("%s is already a venue of this festival", v.Name))
+			}
+		}
+
+		params := database.CreateFestivalVenueParams{Festival: festival, Venue: payload.VenueID}
+		if _, err := q.CreateFestivalVenue(ctx, params); err != nil {
+			return err
+		}
+		if err := payload.write(ctx, q, festival, payload.VenueID); err != nil {
+			return err
+		}
+		if updated, err = q.TouchFestival(ctx, festival); err != nil {
+			return err
+		}
+		venues, err = q.ListFestivalVenues(ctx, festival)
+		return err
+	})
+	if err != nil {
+		return festivalLinkError(c, err, "Venue", "add festival venue")
+	}
+
+	setETag(c, updated)
+	return c.JSON(http.StatusCreated, mapFestivalVenues(venues))
+}
+
+func (a *API) UpdateFestivalVenue(c *echo.Context) error {
+	festival, venue, err := a.festivalLinkParams(c, "venue", a.queries.GetVenueIDByUUID)
+	if err != nil {
+		return festivalLinkError(c, err, "Venue", "update festival venue")
+	}
+
+	var payload festivalVenuePayload
+	if err := c.Bind(&payload); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
+	}
+	payload.VenueID = venue // given by the URL rather than the body
+	if errs := payload.validate(); len(errs) > 0 {
+		return validationFailed(c, errs)
+	}
+
+	ctx := c.Request().Context()
+	var venues []database.ListFestivalVenuesRow
+	var updated time.Time
+	err = a.inTx(ctx, func(q *database.Queries) error {
+		if err := a.lockFestival(c, q, festival); err != nil {
+			return err
+		}
+		if err := payload.write(ctx, q, festival, venue); err != nil {
+			return err
+		}
+		if updated, err = q.TouchFestival(ctx, festival); err != nil {
+			return err
+		}
+		venues, err = q.ListFestivalVenues(ctx, festival)
+		return err
+	})
+	if err != nil {
+		return festivalLinkError(c, err, "Venue", "update festival venue")
+	}
+
+	setETag(c, updated)
+	return c.JSON(http.StatusOK, mapFestivalVenues(venues))
+}
+
+func (a *API) RemoveFestivalVenue(c *echo.Context) error {
+	festival, venue, err := a.festivalLinkParams(c, "venue", a.queries.GetVenueIDByUUID)
+	if err != nil {
+		return festivalLinkError(c, err, "Venue", "remove festival venue")
+	}
+
+	ctx := c.Request().Context()
+	var venues []database.ListFestivalVenuesRow
+	var updated time.Time
+	err = a.inTx(ctx, func(q *database.Queries) error {
+		if err := a.lockFestival(c, q, festival); err != nil {
+			return err
+		}
+		n, err := q.DeleteFestivalVenue(ctx, database.DeleteFestivalVenueParams{Festival: festival, Venue: venue})
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return sql.ErrNoRows
+		}
+		if updated, err = q.TouchFestival(ctx, festival); err != nil {
+			return err
+		}
+		venues, err = q.ListFestivalVenues(ctx, festival)
+		return err
+	})
+	if err != nil {
+		return festivalLinkError(c, err, "Venue", "remove festival venue")
+	}
+
+	setETag(c, updated)
+	return c.JSON(http.StatusOK, mapFestivalVenues(venues))
+}
+
+// --- Festival Promoter Handlers ---
+
+func (a *API) ListFestivalPromoters(c *echo.Context) error {
+	festival, _, err := a.festivalLinkParams(c, "promoter", a.queries.GetPromoterIDByUUID)
+	if err != nil {
+		return festivalLinkError(c, err, "Promoter", "retrieve festival promoters")
+	}
+
+	ctx := c.Request().Context()
+	updated, err := checkFestival(ctx, a.queries, festival)
+	if err != nil {
+		return festivalLinkError(c, err, "Promoter", "retrieve festival promoters")
+	}
+	promoters, err := a.queries.ListFestivalPromoters(ctx, festival)
+	if err != nil {
+		return festivalLinkError(c, err, "Promoter", "retrieve festival promoters")
+	}
+	setETag(c, updated)
+	return c.JSON(http.StatusOK, mapFestivalPromoters(promoters))
+}
+
+func (a *API) AddFestivalPromoter(c *echo.Context) error {
+	festival, _, err := a.festivalLinkParams(c, "promoter", a.queries.GetPromoterIDByUUID)
+	if err != nil {
+		return festivalLinkError(c, err, "Promoter", "add festival promoter")
+	}
+
+	var payload festivalPromoterPayload
+	if err := c.Bind(&payload); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
+	}
+	if errs := payload.validate(); len(errs) > 0 {
+		return validationFailed(c, errs)
+	}
+
+	ctx := c.Request().Context()
+	var promoters []database.ListFestivalPromotersRow
+	var updated time.Time
+	err = a.inTx(ctx, func(q *database.Queries) error {
+		if err := a.lockFestival(c, q, festival); err != nil {
+			return err
+		}
+		current, err := q.ListFestivalPromoters(ctx, festival)
+		if err != nil {
+			return err
+		}
+		for _, p := range current {
+			if p.Promoter == payload.PromoterID {
+				return conflictError(fmt.Sprintf("%s is already a promoter of this festival", p.Name))
+			}
+		}
+
+		params := database.CreateFestivalPromoterParams{
+			Festival: festival,
+			Promoter: payload.PromoterID,
+			Role:     sql.NullString{String: "", Valid: false},
+		}
+		if payload.Role != nil {
+			params.Role = sql.NullString{String: *payload.Role, Valid: true}
+		}
+		if _, err := q.CreateFestivalPromoter(ctx, params); err != nil {
+			return err
+		}
+		if updated, err = q.TouchFestival(ctx, festival); err != nil {
+			return err
+		}
+		promoters, err = q.ListFestivalPromoters(ctx, festival)
+		return err
+	})
+	if err != nil {
+		return festivalLinkError(c, err, "Promoter", "add festival promoter")
+	}
+
+	setETag(c, updated)
+	return c.JSON(http.StatusCreated, mapFestivalPromoters(promoters))
+}
+
+func (a *API) UpdateFestivalPromoter(c *echo.Context) error {
+	festival, promoter, err := a.festivalLinkParams(c, "promoter", a.queries.GetPromoterIDByUUID)
+	if err != nil {
+		return festivalLinkError(c, err, "Promoter", "update festival promoter")
+	}
+
+	var payload festivalPromoterPayload
+	if err := c.Bind(&payload); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
+	}
+	payload.PromoterID = promoter // given by the URL rather than the body
+	if errs := payload.validate(); len(errs) > 0 {
+		return validationFailed(c, errs)
+	}
+
+	ctx := c.Request().Context()
+	var promoters []database.ListFestivalPromotersRow
+	var updated time.Time
+	err = a.inTx(ctx, func(q *database.Queries) error {
+		if err := a.lockFestival(c, q, festival); err != nil {
+			return err
+		}
+		params := database.UpdateFestivalPromoterParams{
+			Festival: festival,
+			Promoter: promoter,
+			Role:     sql.NullString{String: "", Valid: false},
+		}
+		if payload.Role != nil {
+			params.Role = sql.NullString{String: *payload.Role, Valid: true}
+		}
+		if _, err := q.UpdateFestivalPromoter(ctx, params); err != nil {
+			return err
+		}
+		if updated, err = q.TouchFestival(ctx, festival); err != nil {
+			return err
+		}
+		promoters, err = q.ListFestivalPromoters(ctx, festival)
+		return err
+	})
+	if err != nil {
+		return festivalLinkError(c, err, "Promoter", "update festival promoter")
+	}
+
+	setETag(c, updated)
+	return c.JSON(http.StatusOK, mapFestivalPromoters(promoters))
+}
+
+func (a *API) RemoveFestivalPromoter(c *echo.Context) error {
+	festival, promoter, err := a.festivalLinkParams(c, "promoter", a.queries.GetPromoterIDByUUID)
+	if err != nil {
+		return festivalLinkError(c, err, "Promoter", "remove festival promoter")
+	}
+
+	ctx := c.Request().Context()
+	var promoters []database.ListFestivalPromotersRow
+	var updated time.Time
+	err = a.inTx(ctx, func(q *database.Queries) error {
+		if err := a.lockFestival(c, q, festival); err != nil {
+			return err
+		}
+		n, err := q.DeleteFestivalPromoter(ctx, database.DeleteFestivalPromoterParams{Festival: festival, Promoter: promoter})
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return sql.ErrNoRows
+		}
+		if updated, err = q.TouchFestival(ctx, festival); err != nil {
+			return err
+		}
+		promoters, err = q.ListFestivalPromoters(ctx, festival)
+		return err
+	})
+	if err != nil {
+		return festivalLinkError(c, err, "Promoter", "remove festival promoter")
+	}
+
+	setETag(c, updated)
+	return c.JSON(http.StatusOK, mapFestivalPromoters(promoters))
+}
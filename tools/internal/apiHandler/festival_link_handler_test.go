@@ -0,0 +1,52 @@
+package apiHandler
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFestivalVenuePayload_Validate(t *testing.T) {
+	order := func(n int32) *int32 { return &n }
+
+	tests := []struct {
+		name       string
+		payload    festivalVenuePayload
+		wantFields []string
+	}{
+		{name: "Venue", payload: festivalVenuePayload{VenueID: 3}},
+		{name: "Primary venue with a stage order", payload: festivalVenuePayload{VenueID: 3, StageOrder: order(1), Primary: true}},
+		{name: "Missing venue", payload: festivalVenuePayload{StageOrder: order(2)}, wantFields: []string{"venue_id"}},
+		{name: "Stage order zero", payload: festivalVenuePayload{VenueID: 3, StageOrder: order(0)}, wantFields: []string{"stage_order"}},
+		{name: "Negative stage order", payload: festivalVenuePayload{VenueID: 3, StageOrder: order(-1)}, wantFields: []string{"stage_order"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errorFields(tt.payload.validate()); !slices.Equal(got, tt.wantFields) {
+				t.Errorf("validate() fields = %v, want %v", got, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestFestivalPromoterPayload_Validate(t *testing.T) {
+	role := "co-promoter"
+
+	tests := []struct {
+		name       string
+		payload    festivalPromoterPayload
+		wantFields []string
+	}{
+		{name: "Promoter", payload: festivalPromoterPayload{PromoterID: 20}},
+		{name: "Promoter with a role", payload: festivalPromoterPayload{PromoterID: 20, Role: &role}},
+		{name: "Missing promoter", payload: festivalPromoterPayload{Role: &role}, wantFields: []string{"promoter_id"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errorFields(tt.payload.validate()); !slices.Equal(got, tt.wantFields) {
+				t.Errorf("validate() fields = %v, want %v", got, tt.wantFields)
+			}
+		})
+	}
+}
@@ -1,21 +1,73 @@
 package apiHandler
 
 import (
+	"context"
+	"database/sql"
 
 	"github.com/66james99/gig-calendar/internal/database"
 	"github.com/66james99/gig-calendar/internal/dbcollection"
+	"github.com/66james99/gig-calendar/internal/metadata"
 )
 
-// API holds the database queries, making them available to handlers.
+// API holds the database connection and queries, making them available to handlers.
 type API struct {
+	db *sql.DB
 	queries *database.Queries
 	patternsArray *dbcollection.DBArray[string]
+	stageRoles *dbcollection.DBMap[string, metadata.StageRoleKind]
+	indexes *metadata.MatchIndexes
+	requireIfMatch bool
+	history metadata.HistoryBoost
 }
 
 // New creates a new API handler instance.
-func New(queries *database.Queries, patternsArray *dbcollection.DBArray[string]) *API {
+func New(db *sql.DB, queries *database.Queries, patternsArray *dbcollection.DBArray[string], stageRoles *dbcollection.DBMap[string, metadata.StageRoleKind], indexes *metadata.MatchIndexes) *API {
 	return &API{
+		db: db,
 		queries: queries,
 		patternsArray: patternsArray,
+		stageRoles: stageRoles,
+		indexes: indexes,
+		history: metadata.DefaultHistoryBoost,
 	}
-}
\ No newline at end of file
+}
+
+// RequireIfMatch makes updates and deletes without an If-Match header fail with 428
+// Precondition Required rather than overwrite whatever is stored.
+func (a *API) RequireIfMatch() {
+	a.requireIfMatch = true
+}
+
+// SetHistoryBoost sets how far scan previews raise candidates that past events connect with
+// the rest of their directory, in place of metadata.DefaultHistoryBoost. The boosts must fit
+// the venue and performer match settings.
+func (a *API) SetHistoryBoost(history metadata.HistoryBoost) error {
+	for _, idx := range []*metadata.MatchIndex{a.indexes.Venues, a.indexes.Performers} {
+		if err := history.Validate(idx.Settings()); err != nil {
+			return err
+		}
+	}
+	a.history = history
+	return nil
+}
+
+// inTx runs fn with queries bound to a new transaction, committing it if fn succeeds and
+// rolling it back otherwise, so handlers writing several tables never leave them half done.
+func (a *API) inTx(ctx context.Context, fn func(q *database.Queries) error) error {
+	tx, err := a.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(a.queries.WithTx(tx)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// conflictError is returned from a transaction when a change clashes with what is stored,
+// and is reported as 409 Conflict with its message.
+type conflictError string
+
+func (e conflictError) Error() string { return string(e) }
@@ -6,7 +6,6 @@ import (
 	"log"
 	"net/http"
 	"os"
-	"strconv"
 
 	"github.com/66james99/gig-calendar/internal/database"
 	"github.com/66james99/gig-calendar/internal/metadata"
@@ -24,6 +23,30 @@ type imageLocationPayload struct {
 	IncludeParent bool     `json:"include_parent"`
 	IgnoreDirs    []string `json:"ignore_dirs"`
 	Active        bool     `json:"active"`
+	DefaultCity   string   `json:"default_city"`
+	HeadlinerLast bool     `json:"headliner_last"`
+}
+
+// validate checks the payload, including that the root is a directory this server can
+// scan and the pattern is one the scanner understands.
+func (p imageLocationPayload) validate() validationErrors {
+	var errs validationErrors
+	errs.required("root", p.Root)
+	if p.Root != "" {
+		info, err := os.Stat(p.Root)
+		switch {
+		case os.IsNotExist(err):
+			errs.add("root", "directory %s does not exist", p.Root)
+		case err != nil:
+			errs.add("root", "cannot be read: %v", err)
+		case !info.IsDir():
+			errs.add("root", "%s is not a directory", p.Root)
+		}
+	}
+	if err := images.ValidatePattern(p.Pattern); err != nil {
+		errs.add("pattern", "%v", err)
+	}
+	return errs
 }
 
 func (a *API) CreateImageLocation(c *echo.Context) error {
@@ -31,6 +54,9 @@ func (a *API) CreateImageLocation(c *echo.Context) error {
 	if err := c.Bind(&payload); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
 	}
+	if errs := payload.validate(); len(errs) > 0 {
+		return validationFailed(c, errs)
+	}
 
 	params := database.CreateImageLocationParams{
 		Root:          payload.Root,
@@ -39,10 +65,15 @@ func (a *API) CreateImageLocation(c *echo.Context) error {
 		IncludeParent: payload.IncludeParent,
 		IgnoreDirs:    payload.IgnoreDirs,
 		Active:        payload.Active,
+		DefaultCity:   payload.DefaultCity,
+		HeadlinerLast: payload.HeadlinerLast,
 	}
 
 	newLocation, err := a.queries.CreateImageLocation(c.Request().Context(), params)
 	if err != nil {
+		if status, body, ok := constraintViolation(err); ok {
+			return c.JSON(status, body)
+		}
 		log.Printf("Error creating image location: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to create image location"})
 	}
@@ -50,22 +81,38 @@ func (a *API) CreateImageLocation(c *echo.Context) error {
 	return c.JSON(http.StatusCreated, newLocation)
 }
 
+// imageLocationSorts are the columns image locations can be sorted by with ?sort=.
+var imageLocationSorts = []string{"root", "created", "updated"}
+
 func (a *API) ListImageLocations(c *echo.Context) error {
-	locations, err := a.queries.ListImageLocations(c.Request().Context())
+	list, err := parseListQuery(c, imageLocationSorts, "root")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	ctx := c.Request().Context()
+	total, err := a.queries.CountImageLocations(ctx, list.Query)
+	if err != nil {
+		log.Printf("Error counting image locations: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve image locations"})
+	}
+	params := database.ListImageLocationsPageParams{Query: list.Query, Sort: list.Sort, MaxRows: list.Limit, Skip: list.Offset}
+	locations, err := a.queries.ListImageLocationsPage(ctx, params)
 	if err != nil {
 		log.Printf("Error listing image locations: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve image locations"})
 	}
+	setTotalCount(c, total)
 	return c.JSON(http.StatusOK, locations)
 }
 
 func (a *API) GetImageLocation(c *echo.Context) error {
-	id, err := strconv.Atoi(c.Param("id"))
+	id, err := rowID(c, a.queries.GetImageLocationIDByUUID)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+		return idError(c, err, "Image location")
 	}
 
-	location, err := a.queries.GetImageLocation(c.Request().Context(), int32(id))
+	location, err := a.queries.GetImageLocation(c.Request().Context(), id)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return c.JSON(http.StatusNotFound, map[string]string{"error": "Image location not found"})
@@ -74,50 +121,78 @@ func (a *API) GetImageLocation(c *echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve image location"})
 	}
 
+	setETag(c, location.Updated)
 	return c.JSON(http.StatusOK, location)
 }
 
 func (a *API) UpdateImageLocation(c *echo.Context) error {
-	id, err := strconv.Atoi(c.Param("id"))
+	id, err := rowID(c, a.queries.GetImageLocationIDByUUID)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+		return idError(c, err, "Image location")
 	}
 
 	var payload imageLocationPayload
 	if err := c.Bind(&payload); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
 	}
+	if errs := payload.validate(); len(errs) > 0 {
+		return validationFailed(c, errs)
+	}
 
 	params := database.UpdateImageLocationParams{
-		ID:            int32(id),
+		ID:            id,
 		Root:          payload.Root,
 		Pattern:       payload.Pattern,
 		DateFromExif:  payload.DateFromExif,
 		IncludeParent: payload.IncludeParent,
 		IgnoreDirs:    payload.IgnoreDirs,
 		Active:        payload.Active,
+		DefaultCity:   payload.DefaultCity,
+		HeadlinerLast: payload.HeadlinerLast,
 	}
 
-	updatedLocation, err := a.queries.UpdateImageLocation(c.Request().Context(), params)
+	var updatedLocation database.ImageLocation
+	err = a.writeIfMatch(c, id, (*database.Queries).LockImageLocation, func(q *database.Queries) (err error) {
+		updatedLocation, err = q.UpdateImageLocation(c.Request().Context(), params)
+		return err
+	})
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return c.JSON(http.StatusNotFound, map[string]string{"error": "Image location not found"})
 		}
+		if status, body, ok := preconditionFailed(err); ok {
+			return c.JSON(status, body)
+		}
+		if status, body, ok := constraintViolation(err); ok {
+			return c.JSON(status, body)
+		}
 		log.Printf("Error updating image location: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to update image location"})
 	}
 
+	setETag(c, updatedLocation.Updated)
 	return c.JSON(http.StatusOK, updatedLocation)
 }
 
 func (a *API) DeleteImageLocation(c *echo.Context) error {
-	id, err := strconv.Atoi(c.Param("id"))
+	id, err := rowID(c, a.queries.GetImageLocationIDByUUID)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+		return idError(c, err, "Image location")
 	}
 
-	err = a.queries.DeleteImageLocation(c.Request().Context(), int32(id))
+	err = a.writeIfMatch(c, id, (*database.Queries).LockImageLocation, func(q *database.Queries) error {
+		return q.DeleteImageLocation(c.Request().Context(), id)
+	})
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Image location not found"})
+		}
+		if status, body, ok := preconditionFailed(err); ok {
+			return c.JSON(status, body)
+		}
+		if status, body, ok := constraintViolation(err); ok {
+			return c.JSON(status, body)
+		}
 		log.Printf("Error deleting image location: %v", err)
 		// We check if the error is sql.ErrNoRows, but Delete returns no rows, so we can't be sure if it was not found or another error.
 		// A robust way is to check existence first, but for simplicity, we'll assume other errors are server errors.
@@ -131,13 +206,13 @@ func (a *API) DeleteImageLocation(c *echo.Context) error {
 // directories would be found and how they would be parsed for a given image_location config.
 func (a *API) PreviewImageLocationScan(c *echo.Context) error {
 	// 1. Get the ID from the URL parameter.
-	id, err := strconv.Atoi(c.Param("id"))
+	id, err := rowID(c, a.queries.GetImageLocationIDByUUID)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+		return idError(c, err, "Image location")
 	}
 
 	// 2. Fetch the image_location configuration from the database.
-	location, err := a.queries.GetImageLocation(c.Request().Context(), int32(id))
+	location, err := a.queries.GetImageLocation(c.Request().Context(), id)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return c.JSON(http.StatusNotFound, map[string]string{"error": "Image location not found"})
@@ -152,14 +227,23 @@ func (a *API) PreviewImageLocationScan(c *echo.Context) error {
 		Pattern:       	location.Pattern,
 		IncludeParent: 	location.IncludeParent,
 		IgnoreDirs:    	location.IgnoreDirs,
+		DefaultCity:   	location.DefaultCity,
+		HeadlinerLast: 	location.HeadlinerLast,
 		Queries:       	a.queries,
 		Patterns:      	a.patternsArray,
+		StageRoles:    	a.stageRoles,
+		Indexes:       	a.indexes,
 		BaseConfig: 	metadata.BaseConfig{
 			// Set debug to true to get detailed error messages from the scan
 			Debug: c.QueryParam("debug") == "true",
 		},
 	}
 
+	// Boost candidates connected by past events unless the preview asks for raw matches
+	if c.QueryParam("history") != "false" {
+		config.History = a.history
+	}
+
 	// 4. Execute the core logic from the finder tool.
 	scanResult, err := images.ExecuteScan(config)
 	if err != nil {
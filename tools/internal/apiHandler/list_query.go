@@ -0,0 +1,70 @@
+package apiHandler
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v5"
+)
+
+// maxListLimit is the largest page a list request may ask for with ?limit=.
+const maxListLimit = 1000
+
+// totalCountHeader carries the number of rows matching a list request, before pagination.
+const totalCountHeader = "X-Total-Count"
+
+// listQuery holds the pagination, filtering and sorting parameters of a list request.
+// ?q= keeps rows whose name contains it, ignoring case, ?sort= orders them by one of the
+// columns allowed for the list, descending when prefixed with "-", and ?limit= and
+// ?offset= page through them. Without ?limit= every matching row is returned.
+type listQuery struct {
+	Query  string // ?q= escaped for use in a LIKE pattern
+	Sort   string
+	Limit  sql.NullInt32
+	Offset int32
+}
+
+// likeEscaper escapes the characters LIKE treats specially, so ?q= matches literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// parseListQuery reads the list parameters of a request, allowing sorts on the given
+// columns and using defaultSort when ?sort= is not given.
+func parseListQuery(c *echo.Context, sorts []string, defaultSort string) (listQuery, error) {
+	list := listQuery{
+		Query: likeEscaper.Replace(c.QueryParam("q")),
+		Sort:  defaultSort,
+	}
+
+	if sort := c.QueryParam("sort"); sort != "" {
+		if !slices.Contains(sorts, strings.TrimPrefix(sort, "-")) {
+			return list, fmt.Errorf("sort must be one of %s, optionally prefixed with -", strings.Join(sorts, ", "))
+		}
+		list.Sort = sort
+	}
+
+	if limit := c.QueryParam("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > maxListLimit {
+			return list, fmt.Errorf("limit must be a number from 1 to %d", maxListLimit)
+		}
+		list.Limit = sql.NullInt32{Int32: int32(n), Valid: true}
+	}
+
+	if offset := c.QueryParam("offset"); offset != "" {
+		n, err := strconv.Atoi(offset)
+		if err != nil || n < 0 {
+			return list, fmt.Errorf("offset must be a number of at least 0")
+		}
+		list.Offset = int32(n)
+	}
+
+	return list, nil
+}
+
+// setTotalCount reports the number of rows matching a list request in its response headers.
+func setTotalCount(c *echo.Context, total int32) {
+	c.Response().Header().Set(totalCountHeader, strconv.Itoa(int(total)))
+}
@@ -0,0 +1,80 @@
+package apiHandler
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+)
+
+// newTestContext returns a context for a request to target, and the recorder of its response.
+func newTestContext(method, target string) (*echo.Context, *httptest.ResponseRecorder) {
+	rec := httptest.NewRecorder()
+	return echo.New().NewContext(httptest.NewRequest(method, target, nil), rec), rec
+}
+
+func TestParseListQuery(t *testing.T) {
+	sorts := []string{"name", "created"}
+
+	tests := []struct {
+		name    string
+		target  string
+		want    listQuery
+		wantErr string
+	}{
+		{name: "Defaults", target: "/performers", want: listQuery{Sort: "name"}},
+		{
+			name:   "All parameters",
+			target: "/performers?q=abc&sort=-created&limit=20&offset=40",
+			want:   listQuery{Query: "abc", Sort: "-created", Limit: sql.NullInt32{Int32: 20, Valid: true}, Offset: 40},
+		},
+		{name: "Query escaped for LIKE", target: `/performers?q=50%25_off%5C`, want: listQuery{Query: `50\%\_off\\`, Sort: "name"}},
+		{name: "Unknown sort", target: "/performers?sort=city", wantErr: "sort must be one of name, created, optionally prefixed with -"},
+		{name: "Limit not a number", target: "/performers?limit=all", wantErr: "limit must be a number from 1 to 1000"},
+		{name: "Limit zero", target: "/performers?limit=0", wantErr: "limit must be a number from 1 to 1000"},
+		{name: "Limit too large", target: "/performers?limit=1001", wantErr: "limit must be a number from 1 to 1000"},
+		{name: "Largest limit", target: "/performers?limit=1000", want: listQuery{Sort: "name", Limit: sql.NullInt32{Int32: 1000, Valid: true}}},
+		{name: "Negative offset", target: "/performers?offset=-1", wantErr: "offset must be a number of at least 0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newTestContext(http.MethodGet, tt.target)
+			got, err := parseListQuery(c, sorts, "name")
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("parseListQuery() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseListQuery() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("parseListQuery() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseListQuery_FestivalSorts(t *testing.T) {
+	for _, sort := range []string{"start_date", "-end_date", "name", "created", "-created", "updated", "-updated"} {
+		t.Run(sort, func(t *testing.T) {
+			c, _ := newTestContext(http.MethodGet, "/festivals?sort="+sort)
+			got, err := parseListQuery(c, festivalSorts, "-start_date")
+			if err != nil || got.Sort != sort {
+				t.Errorf("parseListQuery() = %+v, %v, want sort %q", got, err, sort)
+			}
+		})
+	}
+}
+
+func TestSetTotalCount(t *testing.T) {
+	c, rec := newTestContext(http.MethodGet, "/performers")
+	setTotalCount(c, 42)
+	if got := rec.Header().Get(totalCountHeader); got != "42" {
+		t.Errorf("%s = %q, want %q", totalCountHeader, got, "42")
+	}
+}
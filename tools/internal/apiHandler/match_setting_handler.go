@@ -0,0 +1,99 @@
+package apiHandler
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"slices"
+
+	"github.com/66james99/gig-calendar/internal/database"
+	"github.com/66james99/gig-calendar/internal/metadata"
+	"github.com/labstack/echo/v5"
+)
+
+// The match settings rows are fixed, one for each entity type, so they can only be listed,
+// read and updated. Changes reach the matcher indexes when they are next refreshed.
+
+func (a *API) ListMatchSettings(c *echo.Context) error {
+	settings, err := a.queries.ListMatchSettings(c.Request().Context())
+	if err != nil {
+		log.Printf("Error listing match settings: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve match settings"})
+	}
+	return c.JSON(http.StatusOK, settings)
+}
+
+func (a *API) GetMatchSetting(c *echo.Context) error {
+	entity := c.Param("entity")
+	if !slices.Contains(metadata.EntityTypes, entity) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid entity type"})
+	}
+
+	setting, err := a.queries.GetMatchSetting(c.Request().Context(), entity)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Match setting not found"})
+		}
+		log.Printf("Error getting match setting: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve match setting"})
+	}
+
+	return c.JSON(http.StatusOK, setting)
+}
+
+func (a *API) UpdateMatchSetting(c *echo.Context) error {
+	entity := c.Param("entity")
+	if !slices.Contains(metadata.EntityTypes, entity) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid entity type"})
+	}
+
+	var payload metadata.MatchSettings
+	if err := c.Bind(&payload); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
+	}
+	if err := payload.Validate(); err != nil {
+		return settingFailed(c, err)
+	}
+	// Scans would refuse settings that let the history boost lift candidates across stages
+	if entity == metadata.EntityVenue || entity == metadata.EntityPerformer {
+		if err := a.history.Validate(payload); err != nil {
+			return settingFailed(c, err)
+		}
+	}
+
+	params := database.UpdateMatchSettingParams{
+		AliasConfidence:      int32(payload.AliasConfidence),
+		FuzzyNameConfidence:  int32(payload.FuzzyNameConfidence),
+		FuzzyAliasConfidence: int32(payload.FuzzyAliasConfidence),
+		PhoneticConfidence:   int32(payload.PhoneticConfidence),
+		MinSimilarity:        payload.MinSimilarity,
+		ShortLength:          int32(payload.ShortLength),
+		ShortMaxEdits:        int32(payload.ShortMaxEdits),
+		MaxEdits:             int32(payload.MaxEdits),
+		EntityType:           entity,
+	}
+
+	setting, err := a.queries.UpdateMatchSetting(c.Request().Context(), params)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Match setting not found"})
+		}
+		if status, body, ok := constraintViolation(err); ok {
+			return c.JSON(status, body)
+		}
+		log.Printf("Error updating match setting: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to update match setting"})
+	}
+
+	return c.JSON(http.StatusOK, setting)
+}
+
+// settingFailed responds to match settings that failed validation.
+func settingFailed(c *echo.Context, err error) error {
+	var settingErr *metadata.SettingError
+	if !errors.As(err, &settingErr) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid match settings: " + err.Error()})
+	}
+	return validationFailed(c, validationErrors{{Field: settingErr.Field, Message: settingErr.Message}})
+}
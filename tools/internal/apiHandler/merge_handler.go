@@ -0,0 +1,330 @@
+package apiHandler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/66james99/gig-calendar/internal/database"
+	"github.com/66james99/gig-calendar/internal/metadata"
+	"github.com/labstack/echo/v5"
+)
+
+// Merging folds a duplicate performer, venue, promoter or festival into the row that
+// survives it: everything referring to the duplicate is moved across, its name is kept as
+// an alias of the survivor and it is deleted, all in one transaction.
+
+// mergePayload defines the JSON body for merging a row into another of the same type.
+type mergePayload struct {
+	Into int32 `json:"into"`
+}
+
+// validate checks the payload for merging the entity with the given id.
+func (p mergePayload) validate(entity string, id int32) validationErrors {
+	var errs validationErrors
+	errs.requiredID("into", p.Into)
+	if p.Into == id {
+		errs.add("into", "cannot merge a %s into itself", entity)
+	}
+	return errs
+}
+
+// mergeResult reports what a merge did. Moved counts the rows re-pointed at the survivor
+// and Dropped the links the survivor already had, by table. Links are only dropped when
+// they record the same as the survivor's; a merge that would lose anything is refused.
+type mergeResult struct {
+	From       map[string]interface{} `json:"from"`
+	Into       map[string]interface{} `json:"into"`
+	Moved      map[string]int64       `json:"moved"`
+	Dropped    map[string]int64       `json:"dropped"`
+	AliasAdded bool                   `json:"alias_added"`
+}
+
+// mergeFunc merges the row source into target, filling in the result. It reports
+// sql.ErrNoRows if either row does not exist.
+type mergeFunc func(ctx context.Context, q *database.Queries, source, target int32, r *mergeResult) error
+
+// moveStep records the n rows a merge query changed in table, or wraps its error.
+func moveStep(counts map[string]int64, table string, n int64, err error) error {
+	if err != nil {
+		return fmt.Errorf("merging %s: %w", table, err)
+	}
+	if n > 0 {
+		counts[table] += n
+	}
+	return nil
+}
+
+// checkDropped refuses a merge when n of the links that would be dropped, as the survivor
+// already has them, record something different from the survivor's, described by what.
+func checkDropped(table string, n int32, err error, what string) error {
+	if err != nil {
+		return fmt.Errorf("merging %s: %w", table, err)
+	}
+	if n > 0 {
+		return conflictError(fmt.Sprintf("%d %s; make them agree before merging", n, what))
+	}
+	return nil
+}
+
+// merge merges the row named by the :id of a request, found by UUID with lookup, into the
+// row named by the payload.
+func (a *API) merge(c *echo.Context, entity string, lookup idLookup, fn mergeFunc) error {
+	id, err := rowID(c, lookup)
+	if err != nil {
+		return idError(c, err, strings.ToUpper(entity[:1])+entity[1:])
+	}
+
+	var payload mergePayload
+	if err := c.Bind(&payload); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
+	}
+	if errs := payload.validate(entity, id); len(errs) > 0 {
+		return validationFailed(c, errs)
+	}
+
+	ctx := c.Request().Context()
+	result := mergeResult{Moved: map[string]int64{}, Dropped: map[string]int64{}}
+	err = a.inTx(ctx, func(q *database.Queries) error {
+		return fn(ctx, q, id, payload.Into, &result)
+	})
+	if err != nil {
+		var conflict conflictError
+		switch {
+		case errors.As(err, &conflict):
+			return c.JSON(http.StatusConflict, map[string]string{"error": conflict.Error()})
+		case errors.Is(err, sql.ErrNoRows):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Both " + entity + "s must exist to merge them"})
+		}
+		if status, body, ok := constraintViolation(err); ok {
+			return c.JSON(status, body)
+		}
+		log.Printf("Error merging %s: %v", entity, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to merge " + entity})
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// --- Merge Handlers ---
+
+func (a *API) MergePerformer(c *echo.Context) error {
+	return a.merge(c, "performer", a.queries.GetPerformerIDByUUID, mergePerformers)
+}
+
+func (a *API) MergeVenue(c *echo.Context) error {
+	return a.merge(c, "venue", a.queries.GetVenueIDByUUID, mergeVenues)
+}
+
+func (a *API) MergePromoter(c *echo.Context) error {
+	return a.merge(c, "promoter", a.queries.GetPromoterIDByUUID, mergePromoters)
+}
+
+func (a *API) MergeFestival(c *echo.Context) error {
+	return a.merge(c, "festival", a.queries.GetFestivalIDByUUID, mergeFestivals)
+}
+
+func mergePerformers(ctx context.Context, q *database.Queries, source, target int32, r *mergeResult) error {
+	from, err := q.GetPerformer(ctx, source)
+	if err != nil {
+		return err
+	}
+	into, err := q.GetPerformer(ctx, target)
+	if err != nil {
+		return err
+	}
+	r.From = map[string]interface{}{"ID": from.ID, "Uuid": from.Uuid, "Name": from.Name}
+	r.Into = map[string]interface{}{"ID": into.ID, "Uuid": into.Uuid, "Name": into.Name}
+
+	differing, err := q.CountDifferingEventPerformers(ctx, database.CountDifferingEventPerformersParams{Target: target, Source: source})
+	if err := checkDropped("event_performer", differing, err,
+		fmt.Sprintf("lineup entries of %s differ in headliner or role from those of %s in the same events", from.Name, into.Name)); err != nil {
+		return err
+	}
+	n, err := q.DeleteOverlappingEventPerformers(ctx, database.DeleteOverlappingEventPerformersParams{Source: source, Target: target})
+	if err := moveStep(r.Dropped, "event_performer", n, err); err != nil {
+		return err
+	}
+	n, err = q.MoveEventPerformers(ctx, database.MoveEventPerformersParams{Target: target, Source: source})
+	if err := moveStep(r.Moved, "event_performer", n, err); err != nil {
+		return err
+	}
+	n, err = q.MovePerformerAliases(ctx, database.MovePerformerAliasesParams{Target: target, Source: source})
+	if err := moveStep(r.Moved, "performer_alias", n, err); err != nil {
+		return err
+	}
+
+	if from.Name != into.Name {
+		params := database.CreateMergedPerformerAliasParams{Performer: target, Alias: from.Name, Phonetic: metadata.PhoneticKey(from.Name)}
+		if n, err = q.CreateMergedPerformerAlias(ctx, params); err != nil {
+			return err
+		}
+		r.AliasAdded = n > 0
+	}
+	return q.DeletePerformer(ctx, source)
+}
+
+// mergeVenues refuses to merge venues that both held an event of the same name on the
+// same day, as the events themselves would need merging first.
+func mergeVenues(ctx context.Context, q *database.Queries, source, target int32, r *mergeResult) error {
+	from, err := q.GetVenue(ctx, source)
+	if err != nil {
+		return err
+	}
+	into, err := q.GetVenue(ctx, target)
+	if err != nil {
+		return err
+	}
+	r.From = map[string]interface{}{"ID": from.ID, "Uuid": from.Uuid, "Name": from.Name, "City": from.City}
+	r.Into = map[string]interface{}{"ID": into.ID, "Uuid": into.Uuid, "Name": into.Name, "City": into.City}
+
+	clashes, err := q.CountClashingVenueEvents(ctx, database.CountClashingVenueEventsParams{Target: target, Source: source})
+	if err != nil {
+		return err
+	}
+	if clashes > 0 {
+		return conflictError(fmt.Sprintf("%d events at %s clash with events of the same name and date at %s", clashes, from.Name, into.Name))
+	}
+
+	n, err := q.MoveVenueEvents(ctx, database.MoveVenueEventsParams{Target: target, Source: source})
+	if err := moveStep(r.Moved, "event", n, err); err != nil {
+		return err
+	}
+	differing, err := q.CountDifferingVenueFestivals(ctx, database.CountDifferingVenueFestivalsParams{Target: target, Source: source})
+	if err := checkDropped("festival_venue", differing, err,
+		fmt.Sprintf("festivals list %s with a different stage order or primary venue from %s", from.Name, into.Name)); err != nil {
+		return err
+	}
+	n, err = q.DeleteOverlappingVenueFestivals(ctx, database.DeleteOverlappingVenueFestivalsParams{Source: source, Target: target})
+	if err := moveStep(r.Dropped, "festival_venue", n, err); err != nil {
+		return err
+	}
+	n, err = q.MoveVenueFestivals(ctx, database.MoveVenueFestivalsParams{Target: target, Source: source})
+	if err := moveStep(r.Moved, "festival_venue", n, err); err != nil {
+		return err
+	}
+	n, err = q.MoveVenueAliases(ctx, database.MoveVenueAliasesParams{Target: target, Source: source})
+	if err := moveStep(r.Moved, "venue_alias", n, err); err != nil {
+		return err
+	}
+
+	if from.Name != into.Name {
+		if n, err = q.CreateMergedVenueAlias(ctx, database.CreateMergedVenueAliasParams{Venue: target, Alias: from.Name}); err != nil {
+			return err
+		}
+		r.AliasAdded = n > 0
+	}
+	return q.DeleteVenue(ctx, source)
+}
+
+func mergePromoters(ctx context.Context, q *database.Queries, source, target int32, r *mergeResult) error {
+	from, err := q.GetPromoter(ctx, source)
+	if err != nil {
+		return err
+	}
+	into, err := q.GetPromoter(ctx, target)
+	if err != nil {
+		return err
+	}
+	r.From = map[string]interface{}{"ID": from.ID, "Uuid": from.Uuid, "Name": from.Name}
+	r.Into = map[string]interface{}{"ID": into.ID, "Uuid": into.Uuid, "Name": into.Name}
+
+	differing, err := q.CountDifferingEventPromoters(ctx, database.CountDifferingEventPromotersParams{Target: target, Source: source})
+	if err := checkDropped("event_promoter", differing, err,
+		fmt.Sprintf("events have only one of %s and %s as their primary promoter", from.Name, into.Name)); err != nil {
+		return err
+	}
+	n, err := q.DeleteOverlappingEventPromoters(ctx, database.DeleteOverlappingEventPromotersParams{Source: source, Target: target})
+	if err := moveStep(r.Dropped, "event_promoter", n, err); err != nil {
+		return err
+	}
+	n, err = q.MoveEventPromoters(ctx, database.MoveEventPromotersParams{Target: target, Source: source})
+	if err := moveStep(r.Moved, "event_promoter", n, err); err != nil {
+		return err
+	}
+	differing, err = q.CountDifferingPromoterFestivals(ctx, database.CountDifferingPromoterFestivalsParams{Target: target, Source: source})
+	if err := checkDropped("festival_promoter", differing, err,
+		fmt.Sprintf("festivals give %s a different role from %s", from.Name, into.Name)); err != nil {
+		return err
+	}
+	n, err = q.DeleteOverlappingPromoterFestivals(ctx, database.DeleteOverlappingPromoterFestivalsParams{Source: source, Target: target})
+	if err := moveStep(r.Dropped, "festival_promoter", n, err); err != nil {
+		return err
+	}
+	n, err = q.MovePromoterFestivals(ctx, database.MovePromoterFestivalsParams{Target: target, Source: source})
+	if err := moveStep(r.Moved, "festival_promoter", n, err); err != nil {
+		return err
+	}
+	n, err = q.MovePromotedFestivals(ctx, database.MovePromotedFestivalsParams{Target: target, Source: source})
+	if err := moveStep(r.Moved, "festival", n, err); err != nil {
+		return err
+	}
+	n, err = q.MovePromoterAliases(ctx, database.MovePromoterAliasesParams{Target: target, Source: source})
+	if err := moveStep(r.Moved, "promoter_alias", n, err); err != nil {
+		return err
+	}
+
+	if from.Name != into.Name {
+		if n, err = q.CreateMergedPromoterAlias(ctx, database.CreateMergedPromoterAliasParams{Promoter: target, Alias: from.Name}); err != nil {
+			return err
+		}
+		r.AliasAdded = n > 0
+	}
+	return q.DeletePromoter(ctx, source)
+}
+
+func mergeFestivals(ctx context.Context, q *database.Queries, source, target int32, r *mergeResult) error {
+	from, err := q.GetFestival(ctx, source)
+	if err != nil {
+		return err
+	}
+	into, err := q.GetFestival(ctx, target)
+	if err != nil {
+		return err
+	}
+	r.From = map[string]interface{}{"ID": from.ID, "Uuid": from.Uuid, "Name": from.Name}
+	r.Into = map[string]interface{}{"ID": into.ID, "Uuid": into.Uuid, "Name": into.Name}
+
+	differing, err := q.CountDifferingFestivalVenues(ctx, database.CountDifferingFestivalVenuesParams{Target: target, Source: source})
+	if err := checkDropped("festival_venue", differing, err,
+		fmt.Sprintf("venues of %s have a different stage order or primary venue at %s", from.Name, into.Name)); err != nil {
+		return err
+	}
+	n, err := q.DeleteOverlappingFestivalVenues(ctx, database.DeleteOverlappingFestivalVenuesParams{Source: source, Target: target})
+	if err := moveStep(r.Dropped, "festival_venue", n, err); err != nil {
+		return err
+	}
+	n, err = q.MoveFestivalVenues(ctx, database.MoveFestivalVenuesParams{Target: target, Source: source})
+	if err := moveStep(r.Moved, "festival_venue", n, err); err != nil {
+		return err
+	}
+	differing, err = q.CountDifferingFestivalPromoters(ctx, database.CountDifferingFestivalPromotersParams{Target: target, Source: source})
+	if err := checkDropped("festival_promoter", differing, err,
+		fmt.Sprintf("promoters of %s have a different role at %s", from.Name, into.Name)); err != nil {
+		return err
+	}
+	n, err = q.DeleteOverlappingFestivalPromoters(ctx, database.DeleteOverlappingFestivalPromotersParams{Source: source, Target: target})
+	if err := moveStep(r.Dropped, "festival_promoter", n, err); err != nil {
+		return err
+	}
+	n, err = q.MoveFestivalPromoters(ctx, database.MoveFestivalPromotersParams{Target: target, Source: source})
+	if err := moveStep(r.Moved, "festival_promoter", n, err); err != nil {
+		return err
+	}
+	n, err = q.MoveFestivalAliases(ctx, database.MoveFestivalAliasesParams{Target: target, Source: source})
+	if err := moveStep(r.Moved, "festival_alias", n, err); err != nil {
+		return err
+	}
+
+	if from.Name != into.Name {
+		if n, err = q.CreateMergedFestivalAlias(ctx, database.CreateMergedFestivalAliasParams{Festival: target, Alias: from.Name}); err != nil {
+			return err
+		}
+		r.AliasAdded = n > 0
+	}
+	return q.DeleteFestival(ctx, source)
+}
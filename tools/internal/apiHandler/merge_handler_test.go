@@ -0,0 +1,95 @@
+package apiHandler
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/66james99/gig-calendar/internal/database"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+)
+
+func TestMergePayload_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		into       int32
+		wantFields []string
+	}{
+		{name: "Another row", into: 2},
+		{name: "Missing target", into: 0, wantFields: []string{"into"}},
+		{name: "Into itself", into: 1, wantFields: []string{"into"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := mergePayload{Into: tt.into}.validate("performer", 1)
+			if got := errorFields(errs); !slices.Equal(got, tt.wantFields) {
+				t.Errorf("validate() fields = %v, want %v", got, tt.wantFields)
+			}
+		})
+	}
+
+	errs := mergePayload{Into: 1}.validate("venue", 1)
+	if len(errs) != 1 || errs[0].Message != "cannot merge a venue into itself" {
+		t.Errorf("validate() = %v, want the venue named", errs)
+	}
+}
+
+func TestMergePerformers(t *testing.T) {
+	performerRows := func(id int32, name string) *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "uuid", "created", "updated", "name", "phonetic"}).
+			AddRow(id, uuid.New(), time.Now(), time.Now(), name, "")
+	}
+
+	tests := []struct {
+		name         string
+		differing    int
+		wantConflict bool
+	}{
+		{name: "Shared lineup entries agree", differing: 0},
+		{name: "Shared lineup entries differ", differing: 2, wantConflict: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to open sqlmock: %v", err)
+			}
+			defer db.Close()
+
+			mock.ExpectQuery(`-- name: GetPerformer :one`).WithArgs(1).WillReturnRows(performerRows(1, "Alicia"))
+			mock.ExpectQuery(`-- name: GetPerformer :one`).WithArgs(2).WillReturnRows(performerRows(2, "Alice"))
+			mock.ExpectQuery(`-- name: CountDifferingEventPerformers :one`).WithArgs(2, 1).
+				WillReturnRows(sqlmock.NewRows([]string{"differing"}).AddRow(tt.differing))
+			if !tt.wantConflict {
+				mock.ExpectExec(`-- name: DeleteOverlappingEventPerformers :execrows`).WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`-- name: MoveEventPerformers :execrows`).WithArgs(2, 1).WillReturnResult(sqlmock.NewResult(0, 3))
+				mock.ExpectExec(`-- name: MovePerformerAliases :execrows`).WithArgs(2, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`-- name: CreateMergedPerformerAlias :execrows`).WithArgs(2, "Alicia", sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`-- name: DeletePerformer :exec`).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			result := mergeResult{Moved: map[string]int64{}, Dropped: map[string]int64{}}
+			err = mergePerformers(context.Background(), database.New(db), 1, 2, &result)
+			var conflict conflictError
+			if errors.As(err, &conflict) != tt.wantConflict {
+				t.Fatalf("mergePerformers() error = %v, want conflict %v", err, tt.wantConflict)
+			}
+			if !tt.wantConflict {
+				if err != nil {
+					t.Fatalf("mergePerformers() error = %v", err)
+				}
+				if result.Dropped["event_performer"] != 1 || result.Moved["event_performer"] != 3 || !result.AliasAdded {
+					t.Errorf("mergePerformers() result = %+v, want 1 lineup entry dropped, 3 moved and an alias added", result)
+				}
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
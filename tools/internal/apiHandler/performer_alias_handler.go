@@ -4,9 +4,9 @@ import (
 	"database/sql"
 	"log"
 	"net/http"
-	"strconv"
 
 	"github.com/66james99/gig-calendar/internal/database"
+	"github.com/66james99/gig-calendar/internal/metadata"
 	"github.com/labstack/echo/v5"
 )
 
@@ -16,19 +16,33 @@ type performerAliasPayload struct {
 	Alias       string `json:"alias"`
 }
 
+func (p performerAliasPayload) validate() validationErrors {
+	var errs validationErrors
+	errs.requiredID("performer_id", p.PerformerID)
+	errs.required("alias", p.Alias)
+	return errs
+}
+
 func (a *API) CreatePerformerAlias(c *echo.Context) error {
 	var payload performerAliasPayload
 	if err := c.Bind(&payload); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
 	}
+	if errs := payload.validate(); len(errs) > 0 {
+		return validationFailed(c, errs)
+	}
 
 	params := database.CreatePerformerAliasParams{
 		Performer: payload.PerformerID,
 		Alias:     payload.Alias,
+		Phonetic:  metadata.PhoneticKey(payload.Alias),
 	}
 
 	newAlias, err := a.queries.CreatePerformerAlias(c.Request().Context(), params)
 	if err != nil {
+		if status, body, ok := constraintViolation(err); ok {
+			return c.JSON(status, body)
+		}
 		log.Printf("Error creating performer alias: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to create performer alias"})
 	}
@@ -36,22 +50,38 @@ func (a *API) CreatePerformerAlias(c *echo.Context) error {
 	return c.JSON(http.StatusCreated, newAlias)
 }
 
+// performerAliasSorts are the columns performer aliases can be sorted by with ?sort=.
+var performerAliasSorts = []string{"alias", "created", "updated"}
+
 func (a *API) ListPerformerAliases(c *echo.Context) error {
-	aliases, err := a.queries.ListPerformerAliases(c.Request().Context())
+	list, err := parseListQuery(c, performerAliasSorts, "alias")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	ctx := c.Request().Context()
+	total, err := a.queries.CountPerformerAliases(ctx, list.Query)
+	if err != nil {
+		log.Printf("Error counting performer aliases: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve performer aliases"})
+	}
+	params := database.ListPerformerAliasesPageParams{Query: list.Query, Sort: list.Sort, MaxRows: list.Limit, Skip: list.Offset}
+	aliases, err := a.queries.ListPerformerAliasesPage(ctx, params)
 	if err != nil {
 		log.Printf("Error listing performer aliases: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve performer aliases"})
 	}
+	setTotalCount(c, total)
 	return c.JSON(http.StatusOK, aliases)
 }
 
 func (a *API) GetPerformerAlias(c *echo.Context) error {
-	id, err := strconv.Atoi(c.Param("id"))
+	id, err := rowID(c, a.queries.GetPerformerAliasIDByUUID)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+		return idError(c, err, "Performer alias")
 	}
 
-	alias, err := a.queries.GetPerformerAlias(c.Request().Context(), int32(id))
+	alias, err := a.queries.GetPerformerAlias(c.Request().Context(), id)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return c.JSON(http.StatusNotFound, map[string]string{"error": "Performer alias not found"})
@@ -60,49 +90,76 @@ func (a *API) GetPerformerAlias(c *echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve performer alias"})
 	}
 
+	setETag(c, alias.Updated)
 	return c.JSON(http.StatusOK, alias)
 }
 
 func (a *API) UpdatePerformerAlias(c *echo.Context) error {
-	id, err := strconv.Atoi(c.Param("id"))
+	id, err := rowID(c, a.queries.GetPerformerAliasIDByUUID)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+		return idError(c, err, "Performer alias")
 	}
 
 	var payload performerAliasPayload
 	if err := c.Bind(&payload); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
 	}
+	if errs := payload.validate(); len(errs) > 0 {
+		return validationFailed(c, errs)
+	}
 
 	params := database.UpdatePerformerAliasParams{
-		ID:        int32(id),
+		ID:        id,
 		Performer: payload.PerformerID,
 		Alias:     payload.Alias,
+		Phonetic:  metadata.PhoneticKey(payload.Alias),
 	}
 
-	updatedAlias, err := a.queries.UpdatePerformerAlias(c.Request().Context(), params)
+	var updatedAlias database.UpdatePerformerAliasRow
+	err = a.writeIfMatch(c, id, (*database.Queries).LockPerformerAlias, func(q *database.Queries) (err error) {
+		updatedAlias, err = q.UpdatePerformerAlias(c.Request().Context(), params)
+		return err
+	})
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return c.JSON(http.StatusNotFound, map[string]string{"error": "Performer alias not found"})
 		}
+		if status, body, ok := preconditionFailed(err); ok {
+			return c.JSON(status, body)
+		}
+		if status, body, ok := constraintViolation(err); ok {
+			return c.JSON(status, body)
+		}
 		log.Printf("Error updating performer alias: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to update performer alias"})
 	}
 
+	setETag(c, updatedAlias.Updated)
 	return c.JSON(http.StatusOK, updatedAlias)
 }
 
 func (a *API) DeletePerformerAlias(c *echo.Context) error {
-	id, err := strconv.Atoi(c.Param("id"))
+	id, err := rowID(c, a.queries.GetPerformerAliasIDByUUID)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+		return idError(c, err, "Performer alias")
 	}
 
-	err = a.queries.DeletePerformerAlias(c.Request().Context(), int32(id))
+	err = a.writeIfMatch(c, id, (*database.Queries).LockPerformerAlias, func(q *database.Queries) error {
+		return q.DeletePerformerAlias(c.Request().Context(), id)
+	})
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return c.JSON(http.StatusNotFound, map[string]string{"error": "Performer alias not found"})
 		}
+		if status, body, ok := preconditionFailed(err); ok {
+			return c.JSON(status, body)
+		}
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Performer alias not found"})
+		}
+		if status, body, ok := constraintViolation(err); ok {
+			return c.JSON(status, body)
+		}
 		log.Printf("Error deleting performer alias: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to delete performer alias"})
 	}
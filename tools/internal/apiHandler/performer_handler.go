@@ -4,9 +4,9 @@ import (
 	"database/sql"
 	"log"
 	"net/http"
-	"strconv"
 
 	"github.com/66james99/gig-calendar/internal/database"
+	"github.com/66james99/gig-calendar/internal/metadata"
 	"github.com/labstack/echo/v5"
 )
 
@@ -14,14 +14,31 @@ type performerPayload struct {
 	Name string `json:"name"`
 }
 
+func (p performerPayload) validate() validationErrors {
+	var errs validationErrors
+	errs.required("name", p.Name)
+	return errs
+}
+
 func (a *API) CreatePerformer(c *echo.Context) error {
 	var payload performerPayload
 	if err := c.Bind(&payload); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
 	}
+	if errs := payload.validate(); len(errs) > 0 {
+		return validationFailed(c, errs)
+	}
 
-	newPerformer, err := a.queries.CreatePerformer(c.Request().Context(), payload.Name)
+	params := database.CreatePerformerParams{
+		Name:     payload.Name,
+		Phonetic: metadata.PhoneticKey(payload.Name),
+	}
+
+	newPerformer, err := a.queries.CreatePerformer(c.Request().Context(), params)
 	if err != nil {
+		if status, body, ok := constraintViolation(err); ok {
+			return c.JSON(status, body)
+		}
 		log.Printf("Error creating performer: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to create performer"})
 	}
@@ -29,22 +46,38 @@ func (a *API) CreatePerformer(c *echo.Context) error {
 	return c.JSON(http.StatusCreated, newPerformer)
 }
 
+// performerSorts are the columns performers can be sorted by with ?sort=.
+var performerSorts = []string{"name", "created", "updated"}
+
 func (a *API) ListPerformers(c *echo.Context) error {
-	performers, err := a.queries.ListPerformers(c.Request().Context())
+	list, err := parseListQuery(c, performerSorts, "name")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	ctx := c.Request().Context()
+	total, err := a.queries.CountPerformers(ctx, list.Query)
+	if err != nil {
+		log.Printf("Error counting performers: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve performers"})
+	}
+	params := database.ListPerformersPageParams{Query: list.Query, Sort: list.Sort, MaxRows: list.Limit, Skip: list.Offset}
+	performers, err := a.queries.ListPerformersPage(ctx, params)
 	if err != nil {
 		log.Printf("Error listing performers: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve performers"})
 	}
+	setTotalCount(c, total)
 	return c.JSON(http.StatusOK, performers)
 }
 
 func (a *API) GetPerformer(c *echo.Context) error {
-	id, err := strconv.Atoi(c.Param("id"))
+	id, err := rowID(c, a.queries.GetPerformerIDByUUID)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+		return idError(c, err, "Performer")
 	}
 
-	performer, err := a.queries.GetPerformer(c.Request().Context(), int32(id))
+	performer, err := a.queries.GetPerformer(c.Request().Context(), id)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return c.JSON(http.StatusNotFound, map[string]string{"error": "Performer not found"})
@@ -53,45 +86,72 @@ func (a *API) GetPerformer(c *echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve performer"})
 	}
 
+	setETag(c, performer.Updated)
 	return c.JSON(http.StatusOK, performer)
 }
 
 func (a *API) UpdatePerformer(c *echo.Context) error {
-	id, err := strconv.Atoi(c.Param("id"))
+	id, err := rowID(c, a.queries.GetPerformerIDByUUID)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+		return idError(c, err, "Performer")
 	}
 
 	var payload performerPayload
 	if err := c.Bind(&payload); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
 	}
+	if errs := payload.validate(); len(errs) > 0 {
+		return validationFailed(c, errs)
+	}
 
 	params := database.UpdatePerformerParams{
-		Name: payload.Name,
-		ID:   int32(id),
+		Name:     payload.Name,
+		Phonetic: metadata.PhoneticKey(payload.Name),
+		ID:       id,
 	}
 
-	updatedPerformer, err := a.queries.UpdatePerformer(c.Request().Context(), params)
+	var updatedPerformer database.Performer
+	err = a.writeIfMatch(c, id, (*database.Queries).LockPerformer, func(q *database.Queries) (err error) {
+		updatedPerformer, err = q.UpdatePerformer(c.Request().Context(), params)
+		return err
+	})
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return c.JSON(http.StatusNotFound, map[string]string{"error": "Performer not found"})
 		}
+		if status, body, ok := preconditionFailed(err); ok {
+			return c.JSON(status, body)
+		}
+		if status, body, ok := constraintViolation(err); ok {
+			return c.JSON(status, body)
+		}
 		log.Printf("Error updating performer: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to update performer"})
 	}
 
+	setETag(c, updatedPerformer.Updated)
 	return c.JSON(http.StatusOK, updatedPerformer)
 }
 
 func (a *API) DeletePerformer(c *echo.Context) error {
-	id, err := strconv.Atoi(c.Param("id"))
+	id, err := rowID(c, a.queries.GetPerformerIDByUUID)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+		return idError(c, err, "Performer")
 	}
 
-	err = a.queries.DeletePerformer(c.Request().Context(), int32(id))
+	err = a.writeIfMatch(c, id, (*database.Queries).LockPerformer, func(q *database.Queries) error {
+		return q.DeletePerformer(c.Request().Context(), id)
+	})
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Performer not found"})
+		}
+		if status, body, ok := preconditionFailed(err); ok {
+			return c.JSON(status, body)
+		}
+		if status, body, ok := constraintViolation(err); ok {
+			return c.JSON(status, body)
+		}
 		log.Printf("Error deleting performer: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to delete performer"})
 	}
@@ -4,7 +4,6 @@ import (
 	"database/sql"
 	"log"
 	"net/http"
-	"strconv"
 
 	"github.com/66james99/gig-calendar/internal/database"
 	"github.com/labstack/echo/v5"
@@ -16,11 +15,21 @@ type promoterAliasPayload struct {
 	Alias      string `json:"alias"`
 }
 
+func (p promoterAliasPayload) validate() validationErrors {
+	var errs validationErrors
+	errs.requiredID("promoter_id", p.PromoterID)
+	errs.required("alias", p.Alias)
+	return errs
+}
+
 func (a *API) CreatePromoterAlias(c *echo.Context) error {
 	var payload promoterAliasPayload
 	if err := c.Bind(&payload); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
 	}
+	if errs := payload.validate(); len(errs) > 0 {
+		return validationFailed(c, errs)
+	}
 
 	params := database.CreatePromoterAliasParams{
 		Promoter: payload.PromoterID,
@@ -29,6 +38,9 @@ func (a *API) CreatePromoterAlias(c *echo.Context) error {
 
 	newAlias, err := a.queries.CreatePromoterAlias(c.Request().Context(), params)
 	if err != nil {
+		if status, body, ok := constraintViolation(err); ok {
+			return c.JSON(status, body)
+		}
 		log.Printf("Error creating promoter alias: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to create promoter alias"})
 	}
@@ -36,22 +48,38 @@ func (a *API) CreatePromoterAlias(c *echo.Context) error {
 	return c.JSON(http.StatusCreated, newAlias)
 }
 
+// promoterAliasSorts are the columns promoter aliases can be sorted by with ?sort=.
+var promoterAliasSorts = []string{"alias", "created", "updated"}
+
 func (a *API) ListPromoterAliases(c *echo.Context) error {
-	aliases, err := a.queries.ListPromoterAliases(c.Request().Context())
+	list, err := parseListQuery(c, promoterAliasSorts, "alias")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	ctx := c.Request().Context()
+	total, err := a.queries.CountPromoterAliases(ctx, list.Query)
+	if err != nil {
+		log.Printf("Error counting promoter aliases: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve promoter aliases"})
+	}
+	params := database.ListPromoterAliasesPageParams{Query: list.Query, Sort: list.Sort, MaxRows: list.Limit, Skip: list.Offset}
+	aliases, err := a.queries.ListPromoterAliasesPage(ctx, params)
 	if err != nil {
 		log.Printf("Error listing promoter aliases: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve promoter aliases"})
 	}
+	setTotalCount(c, total)
 	return c.JSON(http.StatusOK, aliases)
 }
 
 func (a *API) GetPromoterAlias(c *echo.Context) error {
-	id, err := strconv.Atoi(c.Param("id"))
+	id, err := rowID(c, a.queries.GetPromoterAliasIDByUUID)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+		return idError(c, err, "Promoter alias")
 	}
 
-	alias, err := a.queries.GetPromoterAlias(c.Request().Context(), int32(id))
+	alias, err := a.queries.GetPromoterAlias(c.Request().Context(), id)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return c.JSON(http.StatusNotFound, map[string]string{"error": "Promoter alias not found"})
@@ -60,49 +88,75 @@ func (a *API) GetPromoterAlias(c *echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve promoter alias"})
 	}
 
+	setETag(c, alias.Updated)
 	return c.JSON(http.StatusOK, alias)
 }
 
 func (a *API) UpdatePromoterAlias(c *echo.Context) error {
-	id, err := strconv.Atoi(c.Param("id"))
+	id, err := rowID(c, a.queries.GetPromoterAliasIDByUUID)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+		return idError(c, err, "Promoter alias")
 	}
 
 	var payload promoterAliasPayload
 	if err := c.Bind(&payload); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
 	}
+	if errs := payload.validate(); len(errs) > 0 {
+		return validationFailed(c, errs)
+	}
 
 	params := database.UpdatePromoterAliasParams{
-		ID:       int32(id),
+		ID:       id,
 		Promoter: payload.PromoterID,
 		Alias:    payload.Alias,
 	}
 
-	updatedAlias, err := a.queries.UpdatePromoterAlias(c.Request().Context(), params)
+	var updatedAlias database.UpdatePromoterAliasRow
+	err = a.writeIfMatch(c, id, (*database.Queries).LockPromoterAlias, func(q *database.Queries) (err error) {
+		updatedAlias, err = q.UpdatePromoterAlias(c.Request().Context(), params)
+		return err
+	})
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return c.JSON(http.StatusNotFound, map[string]string{"error": "Promoter alias not found"})
 		}
+		if status, body, ok := preconditionFailed(err); ok {
+			return c.JSON(status, body)
+		}
+		if status, body, ok := constraintViolation(err); ok {
+			return c.JSON(status, body)
+		}
 		log.Printf("Error updating promoter alias: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to update promoter alias"})
 	}
 
+	setETag(c, updatedAlias.Updated)
 	return c.JSON(http.StatusOK, updatedAlias)
 }
 
 func (a *API) DeletePromoterAlias(c *echo.Context) error {
-	id, err := strconv.Atoi(c.Param("id"))
+	id, err := rowID(c, a.queries.GetPromoterAliasIDByUUID)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+		return idError(c, err, "Promoter alias")
 	}
 
-	err = a.queries.DeletePromoterAlias(c.Request().Context(), int32(id))
+	err = a.writeIfMatch(c, id, (*database.Queries).LockPromoterAlias, func(q *database.Queries) error {
+		return q.DeletePromoterAlias(c.Request().Context(), id)
+	})
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return c.JSON(http.StatusNotFound, map[string]string{"error": "Promoter alias not found"})
 		}
+		if status, body, ok := preconditionFailed(err); ok {
+			return c.JSON(status, body)
+		}
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Promoter alias not found"})
+		}
+		if status, body, ok := constraintViolation(err); ok {
+			return c.JSON(status, body)
+		}
 		log.Printf("Error deleting promoter alias: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to delete promoter alias"})
 	}
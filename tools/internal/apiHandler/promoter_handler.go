@@ -4,7 +4,6 @@ import (
 	"database/sql"
 	"log"
 	"net/http"
-	"strconv"
 
 	"github.com/66james99/gig-calendar/internal/database"
 	"github.com/labstack/echo/v5"
@@ -14,14 +13,26 @@ type promoterPayload struct {
 	Name string `json:"name"`
 }
 
+func (p promoterPayload) validate() validationErrors {
+	var errs validationErrors
+	errs.required("name", p.Name)
+	return errs
+}
+
 func (a *API) CreatePromoter(c *echo.Context) error {
 	var payload promoterPayload
 	if err := c.Bind(&payload); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
 	}
+	if errs := payload.validate(); len(errs) > 0 {
+		return validationFailed(c, errs)
+	}
 
 	newPromoter, err := a.queries.CreatePromoter(c.Request().Context(), payload.Name)
 	if err != nil {
+		if status, body, ok := constraintViolation(err); ok {
+			return c.JSON(status, body)
+		}
 		log.Printf("Error creating promoter: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to create promoter"})
 	}
@@ -29,22 +40,38 @@ func (a *API) CreatePromoter(c *echo.Context) error {
 	return c.JSON(http.StatusCreated, newPromoter)
 }
 
+// promoterSorts are the columns promoters can be sorted by with ?sort=.
+var promoterSorts = []string{"name", "created", "updated"}
+
 func (a *API) ListPromoters(c *echo.Context) error {
-	promoters, err := a.queries.ListPromoters(c.Request().Context())
+	list, err := parseListQuery(c, promoterSorts, "name")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	ctx := c.Request().Context()
+	total, err := a.queries.CountPromoters(ctx, list.Query)
+	if err != nil {
+		log.Printf("Error counting promoters: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve promoters"})
+	}
+	params := database.ListPromotersPageParams{Query: list.Query, Sort: list.Sort, MaxRows: list.Limit, Skip: list.Offset}
+	promoters, err := a.queries.ListPromotersPage(ctx, params)
 	if err != nil {
 		log.Printf("Error listing promoters: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve promoters"})
 	}
+	setTotalCount(c, total)
 	return c.JSON(http.StatusOK, promoters)
 }
 
 func (a *API) GetPromoter(c *echo.Context) error {
-	id, err := strconv.Atoi(c.Param("id"))
+	id, err := rowID(c, a.queries.GetPromoterIDByUUID)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+		return idError(c, err, "Promoter")
 	}
 
-	promoter, err := a.queries.GetPromoter(c.Request().Context(), int32(id))
+	promoter, err := a.queries.GetPromoter(c.Request().Context(), id)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return c.JSON(http.StatusNotFound, map[string]string{"error": "Promoter not found"})
@@ -53,45 +80,71 @@ func (a *API) GetPromoter(c *echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve promoter"})
 	}
 
+	setETag(c, promoter.Updated)
 	return c.JSON(http.StatusOK, promoter)
 }
 
 func (a *API) UpdatePromoter(c *echo.Context) error {
-	id, err := strconv.Atoi(c.Param("id"))
+	id, err := rowID(c, a.queries.GetPromoterIDByUUID)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+		return idError(c, err, "Promoter")
 	}
 
 	var payload promoterPayload
 	if err := c.Bind(&payload); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
 	}
+	if errs := payload.validate(); len(errs) > 0 {
+		return validationFailed(c, errs)
+	}
 
 	params := database.UpdatePromoterParams{
 		Name: payload.Name,
-		ID:   int32(id),
+		ID:   id,
 	}
 
-	updatedPromoter, err := a.queries.UpdatePromoter(c.Request().Context(), params)
+	var updatedPromoter database.Promoter
+	err = a.writeIfMatch(c, id, (*database.Queries).LockPromoter, func(q *database.Queries) (err error) {
+		updatedPromoter, err = q.UpdatePromoter(c.Request().Context(), params)
+		return err
+	})
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return c.JSON(http.StatusNotFound, map[string]string{"error": "Promoter not found"})
 		}
+		if status, body, ok := preconditionFailed(err); ok {
+			return c.JSON(status, body)
+		}
+		if status, body, ok := constraintViolation(err); ok {
+			return c.JSON(status, body)
+		}
 		log.Printf("Error updating promoter: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to update promoter"})
 	}
 
+	setETag(c, updatedPromoter.Updated)
 	return c.JSON(http.StatusOK, updatedPromoter)
 }
 
 func (a *API) DeletePromoter(c *echo.Context) error {
-	id, err := strconv.Atoi(c.Param("id"))
+	id, err := rowID(c, a.queries.GetPromoterIDByUUID)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+		return idError(c, err, "Promoter")
 	}
 
-	err = a.queries.DeletePromoter(c.Request().Context(), int32(id))
+	err = a.writeIfMatch(c, id, (*database.Queries).LockPromoter, func(q *database.Queries) error {
+		return q.DeletePromoter(c.Request().Context(), id)
+	})
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Promoter not found"})
+		}
+		if status, body, ok := preconditionFailed(err); ok {
+			return c.JSON(status, body)
+		}
+		if status, body, ok := constraintViolation(err); ok {
+			return c.JSON(status, body)
+		}
 		log.Printf("Error deleting promoter: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to delete promoter"})
 	}
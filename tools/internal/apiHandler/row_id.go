@@ -0,0 +1,54 @@
+package apiHandler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+)
+
+// Rows are addressed in routes by their serial id or, for links and files kept outside the
+// database, by their uuid, which never changes. Either can be given wherever a route has
+// an :id, as /performers/42 or /performers/4c1d…-…, and responses carry both.
+
+// errInvalidID is reported for a route parameter that is neither an id nor a uuid.
+var errInvalidID = errors.New("invalid ID format")
+
+// idLookup finds the id of the row with a uuid, as the GetXIDByUUID queries do.
+type idLookup func(ctx context.Context, uuid uuid.UUID) (int32, error)
+
+// parseRowID converts an id or uuid given in a route to the id of the row. A uuid no row
+// has is reported as sql.ErrNoRows.
+func parseRowID(ctx context.Context, param string, lookup idLookup) (int32, error) {
+	if id, err := strconv.ParseInt(param, 10, 32); err == nil {
+		return int32(id), nil
+	}
+	u, err := uuid.Parse(param)
+	if err != nil {
+		return 0, errInvalidID
+	}
+	return lookup(ctx, u)
+}
+
+// rowID reads the :id parameter of a request as the id of a row, resolving a uuid with lookup.
+func rowID(c *echo.Context, lookup idLookup) (int32, error) {
+	return parseRowID(c.Request().Context(), c.Param("id"), lookup)
+}
+
+// idError responds to an error from rowID, naming what was looked up if it was not found.
+func idError(c *echo.Context, err error, resource string) error {
+	switch {
+	case errors.Is(err, errInvalidID):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+	case errors.Is(err, sql.ErrNoRows):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": resource + " not found"})
+	}
+	log.Printf("Error looking up %s by UUID: %v", strings.ToLower(resource), err)
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve " + strings.ToLower(resource)})
+}
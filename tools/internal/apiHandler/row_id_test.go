@@ -0,0 +1,70 @@
+package apiHandler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+)
+
+func TestRowID(t *testing.T) {
+	known := uuid.MustParse("4c1d4d35-8a9a-4b7e-9d8c-2f3a1b0c9e11")
+	broken := uuid.MustParse("9b2f6a8e-1c3d-4e5f-8a7b-6c5d4e3f2a1b")
+	lookup := func(ctx context.Context, u uuid.UUID) (int32, error) {
+		switch u {
+		case known:
+			return 7, nil
+		case broken:
+			return 0, errors.New("connection refused")
+		}
+		return 0, sql.ErrNoRows
+	}
+
+	tests := []struct {
+		name       string
+		param      string
+		wantID     int32
+		wantStatus int
+		wantError  string
+	}{
+		{name: "ID", param: "42", wantID: 42},
+		{name: "Known UUID", param: known.String(), wantID: 7},
+		{name: "Unknown UUID", param: uuid.Nil.String(), wantStatus: http.StatusNotFound, wantError: "Performer not found"},
+		{name: "Neither", param: "forty-two", wantStatus: http.StatusBadRequest, wantError: "Invalid ID format"},
+		{name: "ID out of range", param: "2147483648", wantStatus: http.StatusBadRequest, wantError: "Invalid ID format"},
+		{name: "Lookup failed", param: broken.String(), wantStatus: http.StatusInternalServerError, wantError: "Failed to retrieve performer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rec := newTestContext(http.MethodGet, "/performers/"+tt.param)
+			c.SetPathValues(echo.PathValues{{Name: "id", Value: tt.param}})
+
+			id, err := rowID(c, lookup)
+			if tt.wantStatus == 0 {
+				if err != nil || id != tt.wantID {
+					t.Errorf("rowID() = %d, %v, want %d", id, err, tt.wantID)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("rowID() = %d, want an error", id)
+			}
+			if err := idError(c, err, "Performer"); err != nil {
+				t.Fatalf("idError() error = %v", err)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			var body map[string]string
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body["error"] != tt.wantError {
+				t.Errorf("body = %s, want error %q", rec.Body, tt.wantError)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package apiHandler
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/66james99/gig-calendar/internal/database"
+	"github.com/labstack/echo/v5"
+)
+
+// Search results are limited to defaultSearchLimit unless ?limit= asks for up to
+// maxSearchLimit.
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+// mapSearchResult describes a search match as the entity it found. A match on an alias
+// names the alias under Alias, alongside the entity it belongs to.
+func mapSearchResult(r database.SearchEntitiesRow) map[string]interface{} {
+	result := map[string]interface{}{
+		"Type":  r.EntityType,
+		"ID":    r.ID,
+		"Uuid":  r.Uuid,
+		"Name":  r.Name,
+		"Score": r.Score,
+	}
+	if r.EntityType == "venue" {
+		result["City"] = r.Detail
+	}
+	if r.AliasID != 0 {
+		result["Alias"] = map[string]interface{}{
+			"ID":   r.AliasID,
+			"Uuid": r.AliasUuid.UUID,
+			"Name": r.Alias,
+		}
+	}
+	return result
+}
+
+// Search looks for ?q= across performers, venues, promoters, festivals, event types and
+// their aliases, returning the best matches first.
+func (a *API) Search(c *echo.Context) error {
+	query := strings.TrimSpace(c.QueryParam("q"))
+	if query == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "q is required"})
+	}
+
+	limit := defaultSearchLimit
+	if l := c.QueryParam("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n < 1 || n > maxSearchLimit {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("limit must be a number from 1 to %d", maxSearchLimit)})
+		}
+		limit = n
+	}
+
+	params := database.SearchEntitiesParams{
+		Query:      query,
+		Pattern:    likeEscaper.Replace(query),
+		MaxResults: int32(limit),
+	}
+	results, err := a.queries.SearchEntities(c.Request().Context(), params)
+	if err != nil {
+		log.Printf("Error searching for %q: %v", query, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to search"})
+	}
+
+	response := make([]map[string]interface{}, len(results))
+	for i, r := range results {
+		response[i] = mapSearchResult(r)
+	}
+	return c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,63 @@
+package apiHandler
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/66james99/gig-calendar/internal/database"
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestSearch_Query(t *testing.T) {
+	tests := []struct {
+		name        string
+		target      string
+		wantStatus  int
+		wantError   string
+		wantQuery   string
+		wantPattern string
+		wantLimit   int
+	}{
+		{name: "Query", target: "/search?q=Alice", wantStatus: http.StatusOK, wantQuery: "Alice", wantPattern: "Alice", wantLimit: defaultSearchLimit},
+		{name: "Query trimmed and escaped", target: "/search?q=+50%25_off+", wantStatus: http.StatusOK, wantQuery: "50%_off", wantPattern: `50\%\_off`, wantLimit: defaultSearchLimit},
+		{name: "Largest limit", target: "/search?q=Alice&limit=100", wantStatus: http.StatusOK, wantQuery: "Alice", wantPattern: "Alice", wantLimit: maxSearchLimit},
+		{name: "Missing query", target: "/search", wantStatus: http.StatusBadRequest, wantError: "q is required"},
+		{name: "Empty query", target: "/search?q=+++", wantStatus: http.StatusBadRequest, wantError: "q is required"},
+		{name: "Limit not a number", target: "/search?q=Alice&limit=all", wantStatus: http.StatusBadRequest, wantError: "limit must be a number from 1 to 100"},
+		{name: "Limit zero", target: "/search?q=Alice&limit=0", wantStatus: http.StatusBadRequest, wantError: "limit must be a number from 1 to 100"},
+		{name: "Limit too large", target: "/search?q=Alice&limit=101", wantStatus: http.StatusBadRequest, wantError: "limit must be a number from 1 to 100"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to open sqlmock: %v", err)
+			}
+			defer db.Close()
+			if tt.wantStatus == http.StatusOK {
+				mock.ExpectQuery(`-- name: SearchEntities :many`).WithArgs(tt.wantQuery, tt.wantPattern, tt.wantLimit).
+					WillReturnRows(sqlmock.NewRows([]string{"entity_type", "id", "uuid", "name", "detail", "alias_id", "alias_uuid", "alias", "score"}))
+			}
+
+			a := &API{queries: database.New(db)}
+			c, rec := newTestContext(http.MethodGet, tt.target)
+			if err := a.Search(c); err != nil {
+				t.Fatalf("Search() error = %v", err)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantError != "" {
+				var body map[string]string
+				if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body["error"] != tt.wantError {
+					t.Errorf("body = %s, want error %q", rec.Body, tt.wantError)
+				}
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
@@ -4,14 +4,32 @@ import (
 	"database/sql"
 	"log"
 	"net/http"
-	"strconv"
 
 	"github.com/66james99/gig-calendar/internal/database"
+	"github.com/66james99/gig-calendar/internal/metadata"
 	"github.com/labstack/echo/v5"
 )
 
 type stageRolePayload struct {
 	Pattern string `json:"pattern"`
+	Kind    string `json:"kind"`
+}
+
+// kind returns the kind of stage role in the payload, support when none was given.
+func (p stageRolePayload) kind() (string, bool) {
+	if p.Kind == "" {
+		return string(metadata.StageRoleSupport), true
+	}
+	return p.Kind, metadata.ValidStageRoleKind(p.Kind)
+}
+
+func (p stageRolePayload) validate() validationErrors {
+	var errs validationErrors
+	errs.required("pattern", p.Pattern)
+	if _, ok := p.kind(); !ok {
+		errs.add("kind", "must be a known stage role kind, not %q", p.Kind)
+	}
+	return errs
 }
 
 func (a *API) CreateStageRole(c *echo.Context) error {
@@ -19,9 +37,20 @@ func (a *API) CreateStageRole(c *echo.Context) error {
 	if err := c.Bind(&payload); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
 	}
+	if errs := payload.validate(); len(errs) > 0 {
+		return validationFailed(c, errs)
+	}
 
-	role, err := a.queries.CreateStageRole(c.Request().Context(), payload.Pattern)
+	kind, _ := payload.kind()
+
+	role, err := a.queries.CreateStageRole(c.Request().Context(), database.CreateStageRoleParams{
+		Pattern: payload.Pattern,
+		Kind:    kind,
+	})
 	if err != nil {
+		if status, body, ok := constraintViolation(err); ok {
+			return c.JSON(status, body)
+		}
 		log.Printf("Error creating stage role: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to create stage role"})
 	}
@@ -29,22 +58,38 @@ func (a *API) CreateStageRole(c *echo.Context) error {
 	return c.JSON(http.StatusCreated, role)
 }
 
+// stageRoleSorts are the columns stage roles can be sorted by with ?sort=.
+var stageRoleSorts = []string{"pattern", "kind", "created", "updated"}
+
 func (a *API) ListStageRoles(c *echo.Context) error {
-	roles, err := a.queries.ListStageRoles(c.Request().Context())
+	list, err := parseListQuery(c, stageRoleSorts, "pattern")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	ctx := c.Request().Context()
+	total, err := a.queries.CountStageRoles(ctx, list.Query)
+	if err != nil {
+		log.Printf("Error counting stage roles: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve stage roles"})
+	}
+	params := database.ListStageRolesPageParams{Query: list.Query, Sort: list.Sort, MaxRows: list.Limit, Skip: list.Offset}
+	roles, err := a.queries.ListStageRolesPage(ctx, params)
 	if err != nil {
 		log.Printf("Error listing stage roles: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve stage roles"})
 	}
+	setTotalCount(c, total)
 	return c.JSON(http.StatusOK, roles)
 }
 
 func (a *API) GetStageRole(c *echo.Context) error {
-	id, err := strconv.Atoi(c.Param("id"))
+	id, err := rowID(c, a.queries.GetStageRoleIDByUUID)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+		return idError(c, err, "Stage role")
 	}
 
-	role, err := a.queries.GetStageRole(c.Request().Context(), int32(id))
+	role, err := a.queries.GetStageRole(c.Request().Context(), id)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return c.JSON(http.StatusNotFound, map[string]string{"error": "Stage role not found"})
@@ -53,45 +98,74 @@ func (a *API) GetStageRole(c *echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve stage role"})
 	}
 
+	setETag(c, role.Updated)
 	return c.JSON(http.StatusOK, role)
 }
 
 func (a *API) UpdateStageRole(c *echo.Context) error {
-	id, err := strconv.Atoi(c.Param("id"))
+	id, err := rowID(c, a.queries.GetStageRoleIDByUUID)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+		return idError(c, err, "Stage role")
 	}
 
 	var payload stageRolePayload
 	if err := c.Bind(&payload); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
 	}
+	if errs := payload.validate(); len(errs) > 0 {
+		return validationFailed(c, errs)
+	}
+
+	kind, _ := payload.kind()
 
 	params := database.UpdateStageRoleParams{
 		Pattern: payload.Pattern,
-		ID:   int32(id),
+		Kind:    kind,
+		ID:   id,
 	}
 
-	role, err := a.queries.UpdateStageRole(c.Request().Context(), params)
+	var role database.UpdateStageRoleRow
+	err = a.writeIfMatch(c, id, (*database.Queries).LockStageRole, func(q *database.Queries) (err error) {
+		role, err = q.UpdateStageRole(c.Request().Context(), params)
+		return err
+	})
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return c.JSON(http.StatusNotFound, map[string]string{"error": "Stage role not found"})
 		}
+		if status, body, ok := preconditionFailed(err); ok {
+			return c.JSON(status, body)
+		}
+		if status, body, ok := constraintViolation(err); ok {
+			return c.JSON(status, body)
+		}
 		log.Printf("Error updating stage role: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to update stage role"})
 	}
 
+	setETag(c, role.Updated)
 	return c.JSON(http.StatusOK, role)
 }
 
 func (a *API) DeleteStageRole(c *echo.Context) error {
-	id, err := strconv.Atoi(c.Param("id"))
+	id, err := rowID(c, a.queries.GetStageRoleIDByUUID)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+		return idError(c, err, "Stage role")
 	}
 
-	err = a.queries.DeleteStageRole(c.Request().Context(), int32(id))
+	err = a.writeIfMatch(c, id, (*database.Queries).LockStageRole, func(q *database.Queries) error {
+		return q.DeleteStageRole(c.Request().Context(), id)
+	})
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Stage role not found"})
+		}
+		if status, body, ok := preconditionFailed(err); ok {
+			return c.JSON(status, body)
+		}
+		if status, body, ok := constraintViolation(err); ok {
+			return c.JSON(status, body)
+		}
 		log.Printf("Error deleting stage role: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to delete stage role"})
 	}
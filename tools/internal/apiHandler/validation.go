@@ -0,0 +1,56 @@
+package apiHandler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v5"
+)
+
+// Payloads are validated after binding and before any query runs. A payload that parses
+// but fails validation is answered with 422 Unprocessable Entity and a body listing every
+// problem found, as {"errors": [{"field": ..., "message": ...}]}, so forms can show each
+// message next to its field.
+
+// fieldError describes a problem with one field of a request payload. Fields of list items
+// are named with their index, such as performers[2].slot.
+type fieldError struct {
+	Field      string `json:"field"`
+	Message    string `json:"message"`
+	Constraint string `json:"constraint,omitempty"` // set when the database found the problem
+}
+
+// validationErrors collects the problems found in a payload.
+type validationErrors []fieldError
+
+func (v *validationErrors) add(field, format string, args ...interface{}) {
+	*v = append(*v, fieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// required checks that a text field is not empty or only spaces.
+func (v *validationErrors) required(field, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.add(field, "is required")
+	}
+}
+
+// requiredID checks that a field referring to another row has been given.
+func (v *validationErrors) requiredID(field string, id int32) {
+	if id <= 0 {
+		v.add(field, "is required")
+	}
+}
+
+// requiredDate checks that a date field has been given.
+func (v *validationErrors) requiredDate(field string, t time.Time) {
+	if t.IsZero() {
+		v.add(field, "is required")
+	}
+}
+
+// validationFailed responds to a payload that failed validation.
+func validationFailed(c *echo.Context, errs validationErrors) error {
+	return c.JSON(http.StatusUnprocessableEntity, map[string]validationErrors{"errors": errs})
+}
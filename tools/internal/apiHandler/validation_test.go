@@ -0,0 +1,78 @@
+package apiHandler
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// errorFields returns the fields named by errs, in order.
+func errorFields(errs validationErrors) []string {
+	var fields []string
+	for _, e := range errs {
+		fields = append(fields, e.Field)
+	}
+	return fields
+}
+
+func TestValidationErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		validate func(v *validationErrors)
+		want     []string
+	}{
+		{name: "Text given", validate: func(v *validationErrors) { v.required("name", "The Who") }},
+		{name: "Text empty", validate: func(v *validationErrors) { v.required("name", "") }, want: []string{"name"}},
+		{name: "Text only spaces", validate: func(v *validationErrors) { v.required("name", "  \t") }, want: []string{"name"}},
+		{name: "ID given", validate: func(v *validationErrors) { v.requiredID("venue_id", 3) }},
+		{name: "ID missing", validate: func(v *validationErrors) { v.requiredID("venue_id", 0) }, want: []string{"venue_id"}},
+		{name: "ID negative", validate: func(v *validationErrors) { v.requiredID("venue_id", -1) }, want: []string{"venue_id"}},
+		{name: "Date given", validate: func(v *validationErrors) { v.requiredDate("date", time.Date(2025, 6, 14, 0, 0, 0, 0, time.UTC)) }},
+		{name: "Date missing", validate: func(v *validationErrors) { v.requiredDate("date", time.Time{}) }, want: []string{"date"}},
+		{
+			name: "Every problem reported",
+			validate: func(v *validationErrors) {
+				v.required("name", "")
+				v.requiredID("performers[2].performer_id", 0)
+			},
+			want: []string{"name", "performers[2].performer_id"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var errs validationErrors
+			tt.validate(&errs)
+			if len(errs) != len(tt.want) {
+				t.Fatalf("errors = %v, want fields %v", errs, tt.want)
+			}
+			for i, e := range errs {
+				if e.Field != tt.want[i] || e.Message != "is required" {
+					t.Errorf("errors[%d] = %+v, want %s is required", i, e, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestValidationFailed(t *testing.T) {
+	c, rec := newTestContext(http.MethodPost, "/performers")
+	errs := validationErrors{}
+	errs.add("slot", "must be at least %d", 0)
+	if err := validationFailed(c, errs); err != nil {
+		t.Fatalf("validationFailed() error = %v", err)
+	}
+
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusUnprocessableEntity)
+	}
+	var body map[string][]map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("body %s is not JSON: %v", rec.Body, err)
+	}
+	want := map[string]string{"field": "slot", "message": "must be at least 0"}
+	if got := body["errors"]; len(got) != 1 || len(got[0]) != len(want) || got[0]["field"] != want["field"] || got[0]["message"] != want["message"] {
+		t.Errorf("body = %s, want errors [%v] with no constraint", rec.Body, want)
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"database/sql"
 	"log"
 	"net/http"
-	"strconv"
 
 	"github.com/66james99/gig-calendar/internal/database"
 	"github.com/labstack/echo/v5"
@@ -16,11 +15,21 @@ type venueAliasPayload struct {
 	Alias   string `json:"alias"`
 }
 
+func (p venueAliasPayload) validate() validationErrors {
+	var errs validationErrors
+	errs.requiredID("venue_id", p.VenueID)
+	errs.required("alias", p.Alias)
+	return errs
+}
+
 func (a *API) CreateVenueAlias(c *echo.Context) error {
 	var payload venueAliasPayload
 	if err := c.Bind(&payload); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
 	}
+	if errs := payload.validate(); len(errs) > 0 {
+		return validationFailed(c, errs)
+	}
 
 	params := database.CreateVenueAliasParams{
 		Venue: payload.VenueID,
@@ -29,6 +38,9 @@ func (a *API) CreateVenueAlias(c *echo.Context) error {
 
 	newAlias, err := a.queries.CreateVenueAlias(c.Request().Context(), params)
 	if err != nil {
+		if status, body, ok := constraintViolation(err); ok {
+			return c.JSON(status, body)
+		}
 		log.Printf("Error creating venue alias: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to create venue alias"})
 	}
@@ -36,22 +48,38 @@ func (a *API) CreateVenueAlias(c *echo.Context) error {
 	return c.JSON(http.StatusCreated, newAlias)
 }
 
+// venueAliasSorts are the columns venue aliases can be sorted by with ?sort=.
+var venueAliasSorts = []string{"alias", "created", "updated"}
+
 func (a *API) ListVenueAliases(c *echo.Context) error {
-	aliases, err := a.queries.ListVenueAliases(c.Request().Context())
+	list, err := parseListQuery(c, venueAliasSorts, "alias")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	ctx := c.Request().Context()
+	total, err := a.queries.CountVenueAliases(ctx, list.Query)
+	if err != nil {
+		log.Printf("Error counting venue aliases: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve venue aliases"})
+	}
+	params := database.ListVenueAliasesPageParams{Query: list.Query, Sort: list.Sort, MaxRows: list.Limit, Skip: list.Offset}
+	aliases, err := a.queries.ListVenueAliasesPage(ctx, params)
 	if err != nil {
 		log.Printf("Error listing venue aliases: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve venue aliases"})
 	}
+	setTotalCount(c, total)
 	return c.JSON(http.StatusOK, aliases)
 }
 
 func (a *API) GetVenueAlias(c *echo.Context) error {
-	id, err := strconv.Atoi(c.Param("id"))
+	id, err := rowID(c, a.queries.GetVenueAliasIDByUUID)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+		return idError(c, err, "Venue alias")
 	}
 
-	alias, err := a.queries.GetVenueAlias(c.Request().Context(), int32(id))
+	alias, err := a.queries.GetVenueAlias(c.Request().Context(), id)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return c.JSON(http.StatusNotFound, map[string]string{"error": "Venue alias not found"})
@@ -60,51 +88,77 @@ func (a *API) GetVenueAlias(c *echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve venue alias"})
 	}
 
+	setETag(c, alias.Updated)
 	return c.JSON(http.StatusOK, alias)
 }
 
 func (a *API) UpdateVenueAlias(c *echo.Context) error {
-	id, err := strconv.Atoi(c.Param("id"))
+	id, err := rowID(c, a.queries.GetVenueAliasIDByUUID)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+		return idError(c, err, "Venue alias")
 	}
 
 	var payload venueAliasPayload
 	if err := c.Bind(&payload); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
 	}
+	if errs := payload.validate(); len(errs) > 0 {
+		return validationFailed(c, errs)
+	}
 
 	params := database.UpdateVenueAliasParams{
-		ID:    int32(id),
+		ID:    id,
 		Venue: payload.VenueID,
 		Alias: payload.Alias,
 	}
 
-	updatedAlias, err := a.queries.UpdateVenueAlias(c.Request().Context(), params)
+	var updatedAlias database.UpdateVenueAliasRow
+	err = a.writeIfMatch(c, id, (*database.Queries).LockVenueAlias, func(q *database.Queries) (err error) {
+		updatedAlias, err = q.UpdateVenueAlias(c.Request().Context(), params)
+		return err
+	})
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return c.JSON(http.StatusNotFound, map[string]string{"error": "Venue alias not found"})
 		}
+		if status, body, ok := preconditionFailed(err); ok {
+			return c.JSON(status, body)
+		}
+		if status, body, ok := constraintViolation(err); ok {
+			return c.JSON(status, body)
+		}
 		log.Printf("Error updating venue alias: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to update venue alias"})
 	}
 
+	setETag(c, updatedAlias.Updated)
 	return c.JSON(http.StatusOK, updatedAlias)
 }
 
 func (a *API) DeleteVenueAlias(c *echo.Context) error {
-	id, err := strconv.Atoi(c.Param("id"))
+	id, err := rowID(c, a.queries.GetVenueAliasIDByUUID)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+		return idError(c, err, "Venue alias")
 	}
 
-	err = a.queries.DeleteVenueAlias(c.Request().Context(), int32(id))
+	err = a.writeIfMatch(c, id, (*database.Queries).LockVenueAlias, func(q *database.Queries) error {
+		return q.DeleteVenueAlias(c.Request().Context(), id)
+	})
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Venue alias not found"})
+		}
+		if status, body, ok := preconditionFailed(err); ok {
+			return c.JSON(status, body)
+		}
 		// sqlc's Delete methods return sql.ErrNoRows if no row was found to delete.
 		// We should handle this specifically if we want to return a 404.
 		if err == sql.ErrNoRows {
 			return c.JSON(http.StatusNotFound, map[string]string{"error": "Venue alias not found"})
 		}
+		if status, body, ok := constraintViolation(err); ok {
+			return c.JSON(status, body)
+		}
 		log.Printf("Error deleting venue alias: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to delete venue alias"})
 	}
@@ -4,7 +4,6 @@ import (
 	"database/sql"
 	"log"
 	"net/http"
-	"strconv"
 
 	"github.com/66james99/gig-calendar/internal/database"
 	"github.com/labstack/echo/v5"
@@ -12,7 +11,40 @@ import (
 
 // venuePayload defines the shape of the JSON body for venue create and update requests.
 type venuePayload struct {
-	Name string `json:"name"`
+	Name      string   `json:"name"`
+	City      string   `json:"city"`
+	Country   string   `json:"country"`
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+}
+
+// validate checks the payload, including that latitude and longitude are given together
+// and are in range.
+func (p venuePayload) validate() validationErrors {
+	var errs validationErrors
+	errs.required("name", p.Name)
+	switch {
+	case p.Latitude == nil && p.Longitude != nil:
+		errs.add("latitude", "is required with longitude")
+	case p.Latitude != nil && p.Longitude == nil:
+		errs.add("longitude", "is required with latitude")
+	}
+	if p.Latitude != nil && (*p.Latitude < -90 || *p.Latitude > 90) {
+		errs.add("latitude", "must be from -90 to 90")
+	}
+	if p.Longitude != nil && (*p.Longitude < -180 || *p.Longitude > 180) {
+		errs.add("longitude", "must be from -180 to 180")
+	}
+	return errs
+}
+
+// coordinates converts the optional latitude and longitude of a validated payload to
+// nullable values.
+func (p venuePayload) coordinates() (latitude, longitude sql.NullFloat64) {
+	if p.Latitude == nil || p.Longitude == nil {
+		return latitude, longitude
+	}
+	return sql.NullFloat64{Float64: *p.Latitude, Valid: true}, sql.NullFloat64{Float64: *p.Longitude, Valid: true}
 }
 
 func (a *API) CreateVenue(c *echo.Context) error {
@@ -20,9 +52,25 @@ func (a *API) CreateVenue(c *echo.Context) error {
 	if err := c.Bind(&payload); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
 	}
+	if errs := payload.validate(); len(errs) > 0 {
+		return validationFailed(c, errs)
+	}
+
+	latitude, longitude := payload.coordinates()
+
+	params := database.CreateVenueParams{
+		Name:      payload.Name,
+		City:      payload.City,
+		Country:   payload.Country,
+		Latitude:  latitude,
+		Longitude: longitude,
+	}
 
-	newVenue, err := a.queries.CreateVenue(c.Request().Context(), payload.Name)
+	newVenue, err := a.queries.CreateVenue(c.Request().Context(), params)
 	if err != nil {
+		if status, body, ok := constraintViolation(err); ok {
+			return c.JSON(status, body)
+		}
 		log.Printf("Error creating venue: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to create venue"})
 	}
@@ -30,22 +78,38 @@ func (a *API) CreateVenue(c *echo.Context) error {
 	return c.JSON(http.StatusCreated, newVenue)
 }
 
+// venueSorts are the columns venues can be sorted by with ?sort=.
+var venueSorts = []string{"name", "city", "country", "created", "updated"}
+
 func (a *API) ListVenues(c *echo.Context) error {
-	venues, err := a.queries.ListVenues(c.Request().Context())
+	list, err := parseListQuery(c, venueSorts, "name")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	ctx := c.Request().Context()
+	total, err := a.queries.CountVenues(ctx, list.Query)
+	if err != nil {
+		log.Printf("Error counting venues: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve venues"})
+	}
+	params := database.ListVenuesPageParams{Query: list.Query, Sort: list.Sort, MaxRows: list.Limit, Skip: list.Offset}
+	venues, err := a.queries.ListVenuesPage(ctx, params)
 	if err != nil {
 		log.Printf("Error listing venues: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve venues"})
 	}
+	setTotalCount(c, total)
 	return c.JSON(http.StatusOK, venues)
 }
 
 func (a *API) GetVenue(c *echo.Context) error {
-	id, err := strconv.Atoi(c.Param("id"))
+	id, err := rowID(c, a.queries.GetVenueIDByUUID)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+		return idError(c, err, "Venue")
 	}
 
-	venue, err := a.queries.GetVenue(c.Request().Context(), int32(id))
+	venue, err := a.queries.GetVenue(c.Request().Context(), id)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return c.JSON(http.StatusNotFound, map[string]string{"error": "Venue not found"})
@@ -54,45 +118,77 @@ func (a *API) GetVenue(c *echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve venue"})
 	}
 
+	setETag(c, venue.Updated)
 	return c.JSON(http.StatusOK, venue)
 }
 
 func (a *API) UpdateVenue(c *echo.Context) error {
-	id, err := strconv.Atoi(c.Param("id"))
+	id, err := rowID(c, a.queries.GetVenueIDByUUID)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+		return idError(c, err, "Venue")
 	}
 
 	var payload venuePayload
 	if err := c.Bind(&payload); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
 	}
+	if errs := payload.validate(); len(errs) > 0 {
+		return validationFailed(c, errs)
+	}
+
+	latitude, longitude := payload.coordinates()
 
 	params := database.UpdateVenueParams{
-		ID:   int32(id),
-		Name: payload.Name,
+		ID:        id,
+		Name:      payload.Name,
+		City:      payload.City,
+		Country:   payload.Country,
+		Latitude:  latitude,
+		Longitude: longitude,
 	}
 
-	updatedVenue, err := a.queries.UpdateVenue(c.Request().Context(), params)
+	var updatedVenue database.Venue
+	err = a.writeIfMatch(c, id, (*database.Queries).LockVenue, func(q *database.Queries) (err error) {
+		updatedVenue, err = q.UpdateVenue(c.Request().Context(), params)
+		return err
+	})
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return c.JSON(http.StatusNotFound, map[string]string{"error": "Venue not found"})
 		}
+		if status, body, ok := preconditionFailed(err); ok {
+			return c.JSON(status, body)
+		}
+		if status, body, ok := constraintViolation(err); ok {
+			return c.JSON(status, body)
+		}
 		log.Printf("Error updating venue: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to update venue"})
 	}
 
+	setETag(c, updatedVenue.Updated)
 	return c.JSON(http.StatusOK, updatedVenue)
 }
 
 func (a *API) DeleteVenue(c *echo.Context) error {
-	id, err := strconv.Atoi(c.Param("id"))
+	id, err := rowID(c, a.queries.GetVenueIDByUUID)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+		return idError(c, err, "Venue")
 	}
 
-	err = a.queries.DeleteVenue(c.Request().Context(), int32(id))
+	err = a.writeIfMatch(c, id, (*database.Queries).LockVenue, func(q *database.Queries) error {
+		return q.DeleteVenue(c.Request().Context(), id)
+	})
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Venue not found"})
+		}
+		if status, body, ok := preconditionFailed(err); ok {
+			return c.JSON(status, body)
+		}
+		if status, body, ok := constraintViolation(err); ok {
+			return c.JSON(status, body)
+		}
 		log.Printf("Error deleting venue: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to delete venue"})
 	}
@@ -38,6 +38,64 @@ func (q *Queries) GetPatternConsts(ctx context.Context) ([]string, error) {
 	return items, nil
 }
 
+const getStageRoleKinds = `-- name: GetStageRoleKinds :many
+SELECT pattern, kind FROM stage_role
+ORDER BY pattern
+`
+
+type GetStageRoleKindsRow struct {
+	Pattern string
+	Kind    string
+}
+
+func (q *Queries) GetStageRoleKinds(ctx context.Context) ([]GetStageRoleKindsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getStageRoleKinds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetStageRoleKindsRow
+	for rows.Next() {
+		var i GetStageRoleKindsRow
+		if err := rows.Scan(&i.Pattern, &i.Kind); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lastModifiedFestivals = `-- name: LastModifiedFestivals :one
+SELECT MAX(last_modified)::timestamptz AS last_modified FROM dbcollections_meta
+WHERE table_name IN ('festival', 'festival_alias')
+`
+
+func (q *Queries) LastModifiedFestivals(ctx context.Context) (time.Time, error) {
+	row := q.db.QueryRowContext(ctx, lastModifiedFestivals)
+	var last_modified time.Time
+	err := row.Scan(&last_modified)
+	return last_modified, err
+}
+
+const lastModifiedMatchSettings = `-- name: LastModifiedMatchSettings :one
+SELECT last_modified FROM dbcollections_meta
+WHERE table_name = 'match_setting'
+LIMIT 1
+`
+
+func (q *Queries) LastModifiedMatchSettings(ctx context.Context) (time.Time, error) {
+	row := q.db.QueryRowContext(ctx, lastModifiedMatchSettings)
+	var last_modified time.Time
+	err := row.Scan(&last_modified)
+	return last_modified, err
+}
+
 const lastModifiedPatternConsts = `-- name: LastModifiedPatternConsts :one
 SELECT last_modified FROM dbcollections_meta 
 WHERE table_name = 'stage_role'
@@ -50,3 +108,39 @@ func (q *Queries) LastModifiedPatternConsts(ctx context.Context) (time.Time, err
 	err := row.Scan(&last_modified)
 	return last_modified, err
 }
+
+const lastModifiedPerformers = `-- name: LastModifiedPerformers :one
+SELECT MAX(last_modified)::timestamptz AS last_modified FROM dbcollections_meta
+WHERE table_name IN ('performer', 'performer_alias')
+`
+
+func (q *Queries) LastModifiedPerformers(ctx context.Context) (time.Time, error) {
+	row := q.db.QueryRowContext(ctx, lastModifiedPerformers)
+	var last_modified time.Time
+	err := row.Scan(&last_modified)
+	return last_modified, err
+}
+
+const lastModifiedPromoters = `-- name: LastModifiedPromoters :one
+SELECT MAX(last_modified)::timestamptz AS last_modified FROM dbcollections_meta
+WHERE table_name IN ('promoter', 'promoter_alias')
+`
+
+func (q *Queries) LastModifiedPromoters(ctx context.Context) (time.Time, error) {
+	row := q.db.QueryRowContext(ctx, lastModifiedPromoters)
+	var last_modified time.Time
+	err := row.Scan(&last_modified)
+	return last_modified, err
+}
+
+const lastModifiedVenues = `-- name: LastModifiedVenues :one
+SELECT MAX(last_modified)::timestamptz AS last_modified FROM dbcollections_meta
+WHERE table_name IN ('venue', 'venue_alias')
+`
+
+func (q *Queries) LastModifiedVenues(ctx context.Context) (time.Time, error) {
+	row := q.db.QueryRowContext(ctx, lastModifiedVenues)
+	var last_modified time.Time
+	err := row.Scan(&last_modified)
+	return last_modified, err
+}
@@ -7,26 +7,41 @@ package database
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+const countEventTypes = `-- name: CountEventTypes :one
+SELECT COUNT(*)::integer AS total
+FROM event_type
+WHERE name ILIKE '%' || $1::text || '%'
+`
+
+func (q *Queries) CountEventTypes(ctx context.Context, query string) (int32, error) {
+	row := q.db.QueryRowContext(ctx, countEventTypes, query)
+	var total int32
+	err := row.Scan(&total)
+	return total, err
+}
+
 const createEventType = `-- name: CreateEventType :one
 INSERT INTO event_type (name)
 VALUES ($1)
-RETURNING id, uuid, name
+RETURNING id, uuid, name, created, updated
 `
 
-type CreateEventTypeRow struct {
-	ID   int32
-	Uuid uuid.UUID
-	Name string
-}
-
-func (q *Queries) CreateEventType(ctx context.Context, name string) (CreateEventTypeRow, error) {
+func (q *Queries) CreateEventType(ctx context.Context, name string) (EventType, error) {
 	row := q.db.QueryRowContext(ctx, createEventType, name)
-	var i CreateEventTypeRow
-	err := row.Scan(&i.ID, &i.Uuid, &i.Name)
+	var i EventType
+	err := row.Scan(
+		&i.ID,
+		&i.Uuid,
+		&i.Name,
+		&i.Created,
+		&i.Updated,
+	)
 	return i, err
 }
 
@@ -41,46 +56,110 @@ func (q *Queries) DeleteEventType(ctx context.Context, id int32) error {
 }
 
 const getEventType = `-- name: GetEventType :one
-SELECT id, uuid, name
+SELECT id, uuid, name, created, updated
 FROM event_type
 WHERE id = $1
 `
 
-type GetEventTypeRow struct {
-	ID   int32
-	Uuid uuid.UUID
-	Name string
-}
-
-func (q *Queries) GetEventType(ctx context.Context, id int32) (GetEventTypeRow, error) {
+func (q *Queries) GetEventType(ctx context.Context, id int32) (EventType, error) {
 	row := q.db.QueryRowContext(ctx, getEventType, id)
-	var i GetEventTypeRow
-	err := row.Scan(&i.ID, &i.Uuid, &i.Name)
+	var i EventType
+	err := row.Scan(
+		&i.ID,
+		&i.Uuid,
+		&i.Name,
+		&i.Created,
+		&i.Updated,
+	)
 	return i, err
 }
 
+const getEventTypeIDByUUID = `-- name: GetEventTypeIDByUUID :one
+SELECT id FROM event_type
+WHERE uuid = $1
+`
+
+func (q *Queries) GetEventTypeIDByUUID(ctx context.Context, uuid uuid.UUID) (int32, error) {
+	row := q.db.QueryRowContext(ctx, getEventTypeIDByUUID, uuid)
+	var id int32
+	err := row.Scan(&id)
+	return id, err
+}
+
 const listEventTypes = `-- name: ListEventTypes :many
-SELECT id, uuid, name
+SELECT id, uuid, name, created, updated
 FROM event_type
 ORDER BY name
 `
 
-type ListEventTypesRow struct {
-	ID   int32
-	Uuid uuid.UUID
-	Name string
+func (q *Queries) ListEventTypes(ctx context.Context) ([]EventType, error) {
+	rows, err := q.db.QueryContext(ctx, listEventTypes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []EventType
+	for rows.Next() {
+		var i EventType
+		if err := rows.Scan(
+			&i.ID,
+			&i.Uuid,
+			&i.Name,
+			&i.Created,
+			&i.Updated,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-func (q *Queries) ListEventTypes(ctx context.Context) ([]ListEventTypesRow, error) {
-	rows, err := q.db.QueryContext(ctx, listEventTypes)
+const listEventTypesPage = `-- name: ListEventTypesPage :many
+SELECT id, uuid, name, created, updated
+FROM event_type
+WHERE name ILIKE '%' || $1::text || '%'
+ORDER BY
+    CASE WHEN $2::text = 'name' THEN name END,
+    CASE WHEN $2::text = '-name' THEN name END DESC,
+    id
+LIMIT $3 OFFSET $4
+`
+
+type ListEventTypesPageParams struct {
+	Query   string
+	Sort    string
+	MaxRows sql.NullInt32
+	Skip    int32
+}
+
+func (q *Queries) ListEventTypesPage(ctx context.Context, arg ListEventTypesPageParams) ([]EventType, error) {
+	rows, err := q.db.QueryContext(ctx, listEventTypesPage,
+		arg.Query,
+		arg.Sort,
+		arg.MaxRows,
+		arg.Skip,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListEventTypesRow
+	var items []EventType
 	for rows.Next() {
-		var i ListEventTypesRow
-		if err := rows.Scan(&i.ID, &i.Uuid, &i.Name); err != nil {
+		var i EventType
+		if err := rows.Scan(
+			&i.ID,
+			&i.Uuid,
+			&i.Name,
+			&i.Created,
+			&i.Updated,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -94,11 +173,24 @@ func (q *Queries) ListEventTypes(ctx context.Context) ([]ListEventTypesRow, erro
 	return items, nil
 }
 
+const lockEventType = `-- name: LockEventType :one
+SELECT updated FROM event_type
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) LockEventType(ctx context.Context, id int32) (time.Time, error) {
+	row := q.db.QueryRowContext(ctx, lockEventType, id)
+	var updated time.Time
+	err := row.Scan(&updated)
+	return updated, err
+}
+
 const updateEventType = `-- name: UpdateEventType :one
 UPDATE event_type
-SET name = $2
+SET name = $2, updated = NOW()
 WHERE id = $1
-RETURNING id, uuid, name
+RETURNING id, uuid, name, created, updated
 `
 
 type UpdateEventTypeParams struct {
@@ -106,15 +198,15 @@ type UpdateEventTypeParams struct {
 	Name string
 }
 
-type UpdateEventTypeRow struct {
-	ID   int32
-	Uuid uuid.UUID
-	Name string
-}
-
-func (q *Queries) UpdateEventType(ctx context.Context, arg UpdateEventTypeParams) (UpdateEventTypeRow, error) {
+func (q *Queries) UpdateEventType(ctx context.Context, arg UpdateEventTypeParams) (EventType, error) {
 	row := q.db.QueryRowContext(ctx, updateEventType, arg.ID, arg.Name)
-	var i UpdateEventTypeRow
-	err := row.Scan(&i.ID, &i.Uuid, &i.Name)
+	var i EventType
+	err := row.Scan(
+		&i.ID,
+		&i.Uuid,
+		&i.Name,
+		&i.Created,
+		&i.Updated,
+	)
 	return i, err
 }
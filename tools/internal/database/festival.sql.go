@@ -9,8 +9,36 @@ import (
 	"context"
 	"database/sql"
 	"time"
+
+	"github.com/google/uuid"
 )
 
+const countFestivalAliases = `-- name: CountFestivalAliases :one
+SELECT COUNT(*)::integer AS total
+FROM festival_alias
+WHERE alias ILIKE '%' || $1::text || '%'
+`
+
+func (q *Queries) CountFestivalAliases(ctx context.Context, query string) (int32, error) {
+	row := q.db.QueryRowContext(ctx, countFestivalAliases, query)
+	var total int32
+	err := row.Scan(&total)
+	return total, err
+}
+
+const countFestivals = `-- name: CountFestivals :one
+SELECT COUNT(*)::integer AS total
+FROM festival
+WHERE name ILIKE '%' || $1::text || '%'
+`
+
+func (q *Queries) CountFestivals(ctx context.Context, query string) (int32, error) {
+	row := q.db.QueryRowContext(ctx, countFestivals, query)
+	var total int32
+	err := row.Scan(&total)
+	return total, err
+}
+
 const createFestival = `-- name: CreateFestival :one
 INSERT INTO festival (
     name,
@@ -21,7 +49,7 @@ INSERT INTO festival (
 ) VALUES (
     $1, $2, $3, $4, $5
 )
-RETURNING id, uuid, name, promoter, start_date, end_date, description
+RETURNING id, uuid, name, promoter, start_date, end_date, description, created, updated
 `
 
 type CreateFestivalParams struct {
@@ -49,6 +77,8 @@ func (q *Queries) CreateFestival(ctx context.Context, arg CreateFestivalParams)
 		&i.StartDate,
 		&i.EndDate,
 		&i.Description,
+		&i.Created,
+		&i.Updated,
 	)
 	return i, err
 }
@@ -103,7 +133,7 @@ func (q *Queries) DeleteFestivalAlias(ctx context.Context, id int32) error {
 }
 
 const getFestival = `-- name: GetFestival :one
-SELECT id, uuid, name, promoter, start_date, end_date, description FROM festival
+SELECT id, uuid, name, promoter, start_date, end_date, description, created, updated FROM festival
 WHERE id = $1 LIMIT 1
 `
 
@@ -118,6 +148,8 @@ func (q *Queries) GetFestival(ctx context.Context, id int32) (Festival, error) {
 		&i.StartDate,
 		&i.EndDate,
 		&i.Description,
+		&i.Created,
+		&i.Updated,
 	)
 	return i, err
 }
@@ -141,8 +173,20 @@ func (q *Queries) GetFestivalAlias(ctx context.Context, id int32) (FestivalAlias
 	return i, err
 }
 
+const getFestivalAliasIDByUUID = `-- name: GetFestivalAliasIDByUUID :one
+SELECT id FROM festival_alias
+WHERE uuid = $1
+`
+
+func (q *Queries) GetFestivalAliasIDByUUID(ctx context.Context, uuid uuid.UUID) (int32, error) {
+	row := q.db.QueryRowContext(ctx, getFestivalAliasIDByUUID, uuid)
+	var id int32
+	err := row.Scan(&id)
+	return id, err
+}
+
 const getFestivalByName = `-- name: GetFestivalByName :one
-SELECT id, uuid, name, promoter, start_date, end_date, description FROM festival
+SELECT id, uuid, name, promoter, start_date, end_date, description, created, updated FROM festival
 WHERE name = $1 LIMIT 1
 `
 
@@ -157,10 +201,24 @@ func (q *Queries) GetFestivalByName(ctx context.Context, name string) (Festival,
 		&i.StartDate,
 		&i.EndDate,
 		&i.Description,
+		&i.Created,
+		&i.Updated,
 	)
 	return i, err
 }
 
+const getFestivalIDByUUID = `-- name: GetFestivalIDByUUID :one
+SELECT id FROM festival
+WHERE uuid = $1
+`
+
+func (q *Queries) GetFestivalIDByUUID(ctx context.Context, uuid uuid.UUID) (int32, error) {
+	row := q.db.QueryRowContext(ctx, getFestivalIDByUUID, uuid)
+	var id int32
+	err := row.Scan(&id)
+	return id, err
+}
+
 const listFestivalAliases = `-- name: ListFestivalAliases :many
 SELECT id, uuid, festival, alias, created, updated FROM festival_alias
 ORDER BY alias
@@ -196,8 +254,64 @@ func (q *Queries) ListFestivalAliases(ctx context.Context) ([]FestivalAlias, err
 	return items, nil
 }
 
+const listFestivalAliasesPage = `-- name: ListFestivalAliasesPage :many
+SELECT id, uuid, festival, alias, created, updated FROM festival_alias
+WHERE alias ILIKE '%' || $1::text || '%'
+ORDER BY
+    CASE WHEN $2::text = 'alias' THEN alias END,
+    CASE WHEN $2::text = '-alias' THEN alias END DESC,
+    CASE WHEN $2::text = 'created' THEN created END,
+    CASE WHEN $2::text = '-created' THEN created END DESC,
+    CASE WHEN $2::text = 'updated' THEN updated END,
+    CASE WHEN $2::text = '-updated' THEN updated END DESC,
+    id
+LIMIT $3 OFFSET $4
+`
+
+type ListFestivalAliasesPageParams struct {
+	Query   string
+	Sort    string
+	MaxRows sql.NullInt32
+	Skip    int32
+}
+
+func (q *Queries) ListFestivalAliasesPage(ctx context.Context, arg ListFestivalAliasesPageParams) ([]FestivalAlias, error) {
+	rows, err := q.db.QueryContext(ctx, listFestivalAliasesPage,
+		arg.Query,
+		arg.Sort,
+		arg.MaxRows,
+		arg.Skip,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FestivalAlias
+	for rows.Next() {
+		var i FestivalAlias
+		if err := rows.Scan(
+			&i.ID,
+			&i.Uuid,
+			&i.Festival,
+			&i.Alias,
+			&i.Created,
+			&i.Updated,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listFestivals = `-- name: ListFestivals :many
-SELECT id, uuid, name, promoter, start_date, end_date, description FROM festival
+SELECT id, uuid, name, promoter, start_date, end_date, description, created, updated FROM festival
 ORDER BY start_date DESC
 `
 
@@ -218,6 +332,8 @@ func (q *Queries) ListFestivals(ctx context.Context) ([]Festival, error) {
 			&i.StartDate,
 			&i.EndDate,
 			&i.Description,
+			&i.Created,
+			&i.Updated,
 		); err != nil {
 			return nil, err
 		}
@@ -232,6 +348,213 @@ func (q *Queries) ListFestivals(ctx context.Context) ([]Festival, error) {
 	return items, nil
 }
 
+const listFestivalsAtVenueOnDate = `-- name: ListFestivalsAtVenueOnDate :many
+SELECT f.id, f.uuid, f.name, f.promoter, f.start_date, f.end_date, f.description, f.created, f.updated
+FROM festival f
+JOIN festival_venue fv ON fv.festival = f.id
+WHERE fv.venue = $1
+  AND $2::date BETWEEN f.start_date AND f.end_date
+ORDER BY f.start_date DESC, f.name
+`
+
+type ListFestivalsAtVenueOnDateParams struct {
+	Venue int32
+	Date  time.Time
+}
+
+func (q *Queries) ListFestivalsAtVenueOnDate(ctx context.Context, arg ListFestivalsAtVenueOnDateParams) ([]Festival, error) {
+	rows, err := q.db.QueryContext(ctx, listFestivalsAtVenueOnDate, arg.Venue, arg.Date)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Festival
+	for rows.Next() {
+		var i Festival
+		if err := rows.Scan(
+			&i.ID,
+			&i.Uuid,
+			&i.Name,
+			&i.Promoter,
+			&i.StartDate,
+			&i.EndDate,
+			&i.Description,
+			&i.Created,
+			&i.Updated,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFestivalsPage = `-- name: ListFestivalsPage :many
+SELECT id, uuid, name, promoter, start_date, end_date, description, created, updated FROM festival
+WHERE name ILIKE '%' || $1::text || '%'
+ORDER BY
+    CASE WHEN $2::text = 'start_date' THEN start_date END,
+    CASE WHEN $2::text = '-start_date' THEN start_date END DESC,
+    CASE WHEN $2::text = 'end_date' THEN end_date END,
+    CASE WHEN $2::text = '-end_date' THEN end_date END DESC,
+    CASE WHEN $2::text = 'name' THEN name END,
+    CASE WHEN $2::text = '-name' THEN name END DESC,
+    CASE WHEN $2::text = 'created' THEN created END,
+    CASE WHEN $2::text = '-created' THEN created END DESC,
+    CASE WHEN $2::text = 'updated' THEN updated END,
+    CASE WHEN $2::text = '-updated' THEN updated END DESC,
+    id
+LIMIT $3 OFFSET $4
+`
+
+type ListFestivalsPageParams struct {
+	Query   string
+	Sort    string
+	MaxRows sql.NullInt32
+	Skip    int32
+}
+
+func (q *Queries) ListFestivalsPage(ctx context.Context, arg ListFestivalsPageParams) ([]Festival, error) {
+	rows, err := q.db.QueryContext(ctx, listFestivalsPage,
+		arg.Query,
+		arg.Sort,
+		arg.MaxRows,
+		arg.Skip,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Festival
+	for rows.Next() {
+		var i Festival
+		if err := rows.Scan(
+			&i.ID,
+			&i.Uuid,
+			&i.Name,
+			&i.Promoter,
+			&i.StartDate,
+			&i.EndDate,
+			&i.Description,
+			&i.Created,
+			&i.Updated,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockFestival = `-- name: LockFestival :one
+SELECT updated FROM festival
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) LockFestival(ctx context.Context, id int32) (time.Time, error) {
+	row := q.db.QueryRowContext(ctx, lockFestival, id)
+	var updated time.Time
+	err := row.Scan(&updated)
+	return updated, err
+}
+
+const lockFestivalAlias = `-- name: LockFestivalAlias :one
+SELECT updated FROM festival_alias
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) LockFestivalAlias(ctx context.Context, id int32) (time.Time, error) {
+	row := q.db.QueryRowContext(ctx, lockFestivalAlias, id)
+	var updated time.Time
+	err := row.Scan(&updated)
+	return updated, err
+}
+
+const searchFestivalCandidates = `-- name: SearchFestivalCandidates :many
+SELECT id, name, 0::integer AS alias_id, ''::text AS alias,
+    similarity(name, $1::text)::real AS score
+FROM festival
+WHERE name % $1::text
+UNION ALL
+SELECT e.id, e.name, a.id AS alias_id, a.alias,
+    similarity(a.alias, $1::text)::real AS score
+FROM festival_alias a
+JOIN festival e ON e.id = a.festival
+WHERE a.alias % $1::text
+ORDER BY score DESC, name
+LIMIT $2
+`
+
+type SearchFestivalCandidatesParams struct {
+	Query      string
+	MaxResults int32
+}
+
+type SearchFestivalCandidatesRow struct {
+	ID      int32
+	Name    string
+	AliasID int32
+	Alias   string
+	Score   float32
+}
+
+func (q *Queries) SearchFestivalCandidates(ctx context.Context, arg SearchFestivalCandidatesParams) ([]SearchFestivalCandidatesRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchFestivalCandidates, arg.Query, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchFestivalCandidatesRow
+	for rows.Next() {
+		var i SearchFestivalCandidatesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.AliasID,
+			&i.Alias,
+			&i.Score,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const touchFestival = `-- name: TouchFestival :one
+UPDATE festival
+SET updated = NOW()
+WHERE id = $1
+RETURNING updated
+`
+
+func (q *Queries) TouchFestival(ctx context.Context, id int32) (time.Time, error) {
+	row := q.db.QueryRowContext(ctx, touchFestival, id)
+	var updated time.Time
+	err := row.Scan(&updated)
+	return updated, err
+}
+
 const updateFestival = `-- name: UpdateFestival :one
 UPDATE festival
 SET
@@ -239,9 +562,10 @@ SET
     promoter = $3,
     start_date = $4,
     end_date = $5,
-    description = $6
+    description = $6,
+    updated = NOW()
 WHERE id = $1
-RETURNING id, uuid, name, promoter, start_date, end_date, description
+RETURNING id, uuid, name, promoter, start_date, end_date, description, created, updated
 `
 
 type UpdateFestivalParams struct {
@@ -271,6 +595,8 @@ func (q *Queries) UpdateFestival(ctx context.Context, arg UpdateFestivalParams)
 		&i.StartDate,
 		&i.EndDate,
 		&i.Description,
+		&i.Created,
+		&i.Updated,
 	)
 	return i, err
 }
@@ -279,7 +605,8 @@ const updateFestivalAlias = `-- name: UpdateFestivalAlias :one
 UPDATE festival_alias
 SET
     festival = $2,
-    alias = $3
+    alias = $3,
+    updated = NOW()
 WHERE id = $1
 RETURNING id, uuid, festival, alias, created, updated
 `
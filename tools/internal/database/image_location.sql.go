@@ -7,10 +7,26 @@ package database
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/lib/pq"
 )
 
+const countImageLocations = `-- name: CountImageLocations :one
+SELECT COUNT(*)::integer AS total
+FROM image_location
+WHERE root ILIKE '%' || $1::text || '%'
+`
+
+func (q *Queries) CountImageLocations(ctx context.Context, query string) (int32, error) {
+	row := q.db.QueryRowContext(ctx, countImageLocations, query)
+	var total int32
+	err := row.Scan(&total)
+	return total, err
+}
+
 const createImageLocation = `-- name: CreateImageLocation :one
 INSERT INTO image_location (
     root,
@@ -18,10 +34,12 @@ INSERT INTO image_location (
     date_from_exif,
     include_parent,
     ignore_dirs,
-    active
+    active,
+    default_city,
+    headliner_last
 ) VALUES (
-    $1, $2, $3, $4, $5, $6
-) RETURNING id, root, created, updated, pattern, date_from_exif, include_parent, ignore_dirs, active
+    $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING id, root, created, updated, pattern, date_from_exif, include_parent, ignore_dirs, active, default_city, headliner_last, uuid
 `
 
 type CreateImageLocationParams struct {
@@ -31,6 +49,8 @@ type CreateImageLocationParams struct {
 	IncludeParent bool
 	IgnoreDirs    []string
 	Active        bool
+	DefaultCity   string
+	HeadlinerLast bool
 }
 
 func (q *Queries) CreateImageLocation(ctx context.Context, arg CreateImageLocationParams) (ImageLocation, error) {
@@ -41,6 +61,8 @@ func (q *Queries) CreateImageLocation(ctx context.Context, arg CreateImageLocati
 		arg.IncludeParent,
 		pq.Array(arg.IgnoreDirs),
 		arg.Active,
+		arg.DefaultCity,
+		arg.HeadlinerLast,
 	)
 	var i ImageLocation
 	err := row.Scan(
@@ -53,6 +75,9 @@ func (q *Queries) CreateImageLocation(ctx context.Context, arg CreateImageLocati
 		&i.IncludeParent,
 		pq.Array(&i.IgnoreDirs),
 		&i.Active,
+		&i.DefaultCity,
+		&i.HeadlinerLast,
+		&i.Uuid,
 	)
 	return i, err
 }
@@ -68,7 +93,7 @@ func (q *Queries) DeleteImageLocation(ctx context.Context, id int32) error {
 }
 
 const getImageLocation = `-- name: GetImageLocation :one
-SELECT id, root, created, updated, pattern, date_from_exif, include_parent, ignore_dirs, active FROM image_location
+SELECT id, root, created, updated, pattern, date_from_exif, include_parent, ignore_dirs, active, default_city, headliner_last, uuid FROM image_location
 WHERE id = $1 LIMIT 1
 `
 
@@ -85,12 +110,27 @@ func (q *Queries) GetImageLocation(ctx context.Context, id int32) (ImageLocation
 		&i.IncludeParent,
 		pq.Array(&i.IgnoreDirs),
 		&i.Active,
+		&i.DefaultCity,
+		&i.HeadlinerLast,
+		&i.Uuid,
 	)
 	return i, err
 }
 
+const getImageLocationIDByUUID = `-- name: GetImageLocationIDByUUID :one
+SELECT id FROM image_location
+WHERE uuid = $1
+`
+
+func (q *Queries) GetImageLocationIDByUUID(ctx context.Context, uuid uuid.UUID) (int32, error) {
+	row := q.db.QueryRowContext(ctx, getImageLocationIDByUUID, uuid)
+	var id int32
+	err := row.Scan(&id)
+	return id, err
+}
+
 const listImageLocations = `-- name: ListImageLocations :many
-SELECT id, root, created, updated, pattern, date_from_exif, include_parent, ignore_dirs, active FROM image_location
+SELECT id, root, created, updated, pattern, date_from_exif, include_parent, ignore_dirs, active, default_city, headliner_last, uuid FROM image_location
 ORDER BY root
 `
 
@@ -113,6 +153,9 @@ func (q *Queries) ListImageLocations(ctx context.Context) ([]ImageLocation, erro
 			&i.IncludeParent,
 			pq.Array(&i.IgnoreDirs),
 			&i.Active,
+			&i.DefaultCity,
+			&i.HeadlinerLast,
+			&i.Uuid,
 		); err != nil {
 			return nil, err
 		}
@@ -127,6 +170,81 @@ func (q *Queries) ListImageLocations(ctx context.Context) ([]ImageLocation, erro
 	return items, nil
 }
 
+const listImageLocationsPage = `-- name: ListImageLocationsPage :many
+SELECT id, root, created, updated, pattern, date_from_exif, include_parent, ignore_dirs, active, default_city, headliner_last, uuid FROM image_location
+WHERE root ILIKE '%' || $1::text || '%'
+ORDER BY
+    CASE WHEN $2::text = 'root' THEN root END,
+    CASE WHEN $2::text = '-root' THEN root END DESC,
+    CASE WHEN $2::text = 'created' THEN created END,
+    CASE WHEN $2::text = '-created' THEN created END DESC,
+    CASE WHEN $2::text = 'updated' THEN updated END,
+    CASE WHEN $2::text = '-updated' THEN updated END DESC,
+    id
+LIMIT $3 OFFSET $4
+`
+
+type ListImageLocationsPageParams struct {
+	Query   string
+	Sort    string
+	MaxRows sql.NullInt32
+	Skip    int32
+}
+
+func (q *Queries) ListImageLocationsPage(ctx context.Context, arg ListImageLocationsPageParams) ([]ImageLocation, error) {
+	rows, err := q.db.QueryContext(ctx, listImageLocationsPage,
+		arg.Query,
+		arg.Sort,
+		arg.MaxRows,
+		arg.Skip,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ImageLocation
+	for rows.Next() {
+		var i ImageLocation
+		if err := rows.Scan(
+			&i.ID,
+			&i.Root,
+			&i.Created,
+			&i.Updated,
+			&i.Pattern,
+			&i.DateFromExif,
+			&i.IncludeParent,
+			pq.Array(&i.IgnoreDirs),
+			&i.Active,
+			&i.DefaultCity,
+			&i.HeadlinerLast,
+			&i.Uuid,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockImageLocation = `-- name: LockImageLocation :one
+SELECT updated FROM image_location
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) LockImageLocation(ctx context.Context, id int32) (time.Time, error) {
+	row := q.db.QueryRowContext(ctx, lockImageLocation, id)
+	var updated time.Time
+	err := row.Scan(&updated)
+	return updated, err
+}
+
 const updateImageLocation = `-- name: UpdateImageLocation :one
 UPDATE image_location
 SET
@@ -136,9 +254,11 @@ SET
     include_parent = $5,
     ignore_dirs = $6,
     active = $7,
+    default_city = $8,
+    headliner_last = $9,
     updated = now()
 WHERE id = $1
-RETURNING id, root, created, updated, pattern, date_from_exif, include_parent, ignore_dirs, active
+RETURNING id, root, created, updated, pattern, date_from_exif, include_parent, ignore_dirs, active, default_city, headliner_last, uuid
 `
 
 type UpdateImageLocationParams struct {
@@ -149,6 +269,8 @@ type UpdateImageLocationParams struct {
 	IncludeParent bool
 	IgnoreDirs    []string
 	Active        bool
+	DefaultCity   string
+	HeadlinerLast bool
 }
 
 func (q *Queries) UpdateImageLocation(ctx context.Context, arg UpdateImageLocationParams) (ImageLocation, error) {
@@ -160,6 +282,8 @@ func (q *Queries) UpdateImageLocation(ctx context.Context, arg UpdateImageLocati
 		arg.IncludeParent,
 		pq.Array(arg.IgnoreDirs),
 		arg.Active,
+		arg.DefaultCity,
+		arg.HeadlinerLast,
 	)
 	var i ImageLocation
 	err := row.Scan(
@@ -172,6 +296,9 @@ func (q *Queries) UpdateImageLocation(ctx context.Context, arg UpdateImageLocati
 		&i.IncludeParent,
 		pq.Array(&i.IgnoreDirs),
 		&i.Active,
+		&i.DefaultCity,
+		&i.HeadlinerLast,
+		&i.Uuid,
 	)
 	return i, err
 }
@@ -34,6 +34,7 @@ type EventPerformer struct {
 	Updated   time.Time
 	Headliner bool
 	Slot      int32
+	Role      sql.NullString
 }
 
 type EventPromoter struct {
@@ -60,6 +61,8 @@ type Festival struct {
 	StartDate   time.Time
 	EndDate     time.Time
 	Description sql.NullString
+	Created     time.Time
+	Updated     time.Time
 }
 
 type FestivalAlias struct {
@@ -94,6 +97,9 @@ type ImageLocation struct {
 	IncludeParent bool
 	IgnoreDirs    []string
 	Active        bool
+	DefaultCity   string
+	HeadlinerLast bool
+	Uuid          uuid.UUID
 }
 
 type ImageLocationScan struct {
@@ -107,12 +113,27 @@ type ImageLocationScan struct {
 	Failed       int32
 }
 
+type MatchSetting struct {
+	EntityType           string
+	AliasConfidence      int32
+	FuzzyNameConfidence  int32
+	FuzzyAliasConfidence int32
+	PhoneticConfidence   int32
+	MinSimilarity        float64
+	ShortLength          int32
+	ShortMaxEdits        int32
+	MaxEdits             int32
+	Created              time.Time
+	Updated              time.Time
+}
+
 type Performer struct {
-	ID      int32
-	Uuid    uuid.UUID
-	Created time.Time
-	Updated time.Time
-	Name    string
+	ID       int32
+	Uuid     uuid.UUID
+	Created  time.Time
+	Updated  time.Time
+	Name     string
+	Phonetic string
 }
 
 type PerformerAlias struct {
@@ -122,6 +143,7 @@ type PerformerAlias struct {
 	Alias     string
 	Created   time.Time
 	Updated   time.Time
+	Phonetic  string
 }
 
 type Promoter struct {
@@ -157,14 +179,19 @@ type StageRole struct {
 	Pattern string
 	Created time.Time
 	Updated time.Time
+	Kind    string
 }
 
 type Venue struct {
-	ID      int32
-	Uuid    uuid.UUID
-	Created time.Time
-	Updated time.Time
-	Name    string
+	ID        int32
+	Uuid      uuid.UUID
+	Created   time.Time
+	Updated   time.Time
+	Name      string
+	City      string
+	Country   string
+	Latitude  sql.NullFloat64
+	Longitude sql.NullFloat64
 }
 
 type VenueAlias struct {
@@ -7,16 +7,38 @@ package database
 
 import (
 	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
 )
 
+const countPerformers = `-- name: CountPerformers :one
+SELECT COUNT(*)::integer AS total
+FROM performer
+WHERE name ILIKE '%' || $1::text || '%'
+`
+
+func (q *Queries) CountPerformers(ctx context.Context, query string) (int32, error) {
+	row := q.db.QueryRowContext(ctx, countPerformers, query)
+	var total int32
+	err := row.Scan(&total)
+	return total, err
+}
+
 const createPerformer = `-- name: CreatePerformer :one
-INSERT INTO performer (name)
-VALUES ($1)
-RETURNING id, uuid, created, updated, name
+INSERT INTO performer (name, phonetic)
+VALUES ($1, $2)
+RETURNING id, uuid, created, updated, name, phonetic
 `
 
-func (q *Queries) CreatePerformer(ctx context.Context, name string) (Performer, error) {
-	row := q.db.QueryRowContext(ctx, createPerformer, name)
+type CreatePerformerParams struct {
+	Name     string
+	Phonetic string
+}
+
+func (q *Queries) CreatePerformer(ctx context.Context, arg CreatePerformerParams) (Performer, error) {
+	row := q.db.QueryRowContext(ctx, createPerformer, arg.Name, arg.Phonetic)
 	var i Performer
 	err := row.Scan(
 		&i.ID,
@@ -24,6 +46,7 @@ func (q *Queries) CreatePerformer(ctx context.Context, name string) (Performer,
 		&i.Created,
 		&i.Updated,
 		&i.Name,
+		&i.Phonetic,
 	)
 	return i, err
 }
@@ -39,7 +62,7 @@ func (q *Queries) DeletePerformer(ctx context.Context, id int32) error {
 }
 
 const getPerformer = `-- name: GetPerformer :one
-SELECT id, uuid, created, updated, name FROM performer
+SELECT id, uuid, created, updated, name, phonetic FROM performer
 WHERE id = $1
 `
 
@@ -52,12 +75,13 @@ func (q *Queries) GetPerformer(ctx context.Context, id int32) (Performer, error)
 		&i.Created,
 		&i.Updated,
 		&i.Name,
+		&i.Phonetic,
 	)
 	return i, err
 }
 
 const getPerformerByName = `-- name: GetPerformerByName :one
-SELECT id, uuid, created, updated, name FROM performer
+SELECT id, uuid, created, updated, name, phonetic FROM performer
 WHERE name = $1 LIMIT 1
 `
 
@@ -70,12 +94,25 @@ func (q *Queries) GetPerformerByName(ctx context.Context, name string) (Performe
 		&i.Created,
 		&i.Updated,
 		&i.Name,
+		&i.Phonetic,
 	)
 	return i, err
 }
 
+const getPerformerIDByUUID = `-- name: GetPerformerIDByUUID :one
+SELECT id FROM performer
+WHERE uuid = $1
+`
+
+func (q *Queries) GetPerformerIDByUUID(ctx context.Context, uuid uuid.UUID) (int32, error) {
+	row := q.db.QueryRowContext(ctx, getPerformerIDByUUID, uuid)
+	var id int32
+	err := row.Scan(&id)
+	return id, err
+}
+
 const listPerformers = `-- name: ListPerformers :many
-SELECT id, uuid, created, updated, name FROM performer
+SELECT id, uuid, created, updated, name, phonetic FROM performer
 ORDER BY name
 `
 
@@ -94,6 +131,133 @@ func (q *Queries) ListPerformers(ctx context.Context) ([]Performer, error) {
 			&i.Created,
 			&i.Updated,
 			&i.Name,
+			&i.Phonetic,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPerformersPage = `-- name: ListPerformersPage :many
+SELECT id, uuid, created, updated, name, phonetic FROM performer
+WHERE name ILIKE '%' || $1::text || '%'
+ORDER BY
+    CASE WHEN $2::text = 'name' THEN name END,
+    CASE WHEN $2::text = '-name' THEN name END DESC,
+    CASE WHEN $2::text = 'created' THEN created END,
+    CASE WHEN $2::text = '-created' THEN created END DESC,
+    CASE WHEN $2::text = 'updated' THEN updated END,
+    CASE WHEN $2::text = '-updated' THEN updated END DESC,
+    id
+LIMIT $3 OFFSET $4
+`
+
+type ListPerformersPageParams struct {
+	Query   string
+	Sort    string
+	MaxRows sql.NullInt32
+	Skip    int32
+}
+
+func (q *Queries) ListPerformersPage(ctx context.Context, arg ListPerformersPageParams) ([]Performer, error) {
+	rows, err := q.db.QueryContext(ctx, listPerformersPage,
+		arg.Query,
+		arg.Sort,
+		arg.MaxRows,
+		arg.Skip,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Performer
+	for rows.Next() {
+		var i Performer
+		if err := rows.Scan(
+			&i.ID,
+			&i.Uuid,
+			&i.Created,
+			&i.Updated,
+			&i.Name,
+			&i.Phonetic,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockPerformer = `-- name: LockPerformer :one
+SELECT updated FROM performer
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) LockPerformer(ctx context.Context, id int32) (time.Time, error) {
+	row := q.db.QueryRowContext(ctx, lockPerformer, id)
+	var updated time.Time
+	err := row.Scan(&updated)
+	return updated, err
+}
+
+const searchPerformerCandidates = `-- name: SearchPerformerCandidates :many
+SELECT id, name, 0::integer AS alias_id, ''::text AS alias,
+    similarity(name, $1::text)::real AS score
+FROM performer
+WHERE name % $1::text
+UNION ALL
+SELECT e.id, e.name, a.id AS alias_id, a.alias,
+    similarity(a.alias, $1::text)::real AS score
+FROM performer_alias a
+JOIN performer e ON e.id = a.performer
+WHERE a.alias % $1::text
+ORDER BY score DESC, name
+LIMIT $2
+`
+
+type SearchPerformerCandidatesParams struct {
+	Query      string
+	MaxResults int32
+}
+
+type SearchPerformerCandidatesRow struct {
+	ID      int32
+	Name    string
+	AliasID int32
+	Alias   string
+	Score   float32
+}
+
+func (q *Queries) SearchPerformerCandidates(ctx context.Context, arg SearchPerformerCandidatesParams) ([]SearchPerformerCandidatesRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchPerformerCandidates, arg.Query, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchPerformerCandidatesRow
+	for rows.Next() {
+		var i SearchPerformerCandidatesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.AliasID,
+			&i.Alias,
+			&i.Score,
 		); err != nil {
 			return nil, err
 		}
@@ -110,18 +274,19 @@ func (q *Queries) ListPerformers(ctx context.Context) ([]Performer, error) {
 
 const updatePerformer = `-- name: UpdatePerformer :one
 UPDATE performer
-SET name = $1, updated = NOW()
-WHERE id = $2
-RETURNING id, uuid, created, updated, name
+SET name = $1, phonetic = $2, updated = NOW()
+WHERE id = $3
+RETURNING id, uuid, created, updated, name, phonetic
 `
 
 type UpdatePerformerParams struct {
-	Name string
-	ID   int32
+	Name     string
+	Phonetic string
+	ID       int32
 }
 
 func (q *Queries) UpdatePerformer(ctx context.Context, arg UpdatePerformerParams) (Performer, error) {
-	row := q.db.QueryRowContext(ctx, updatePerformer, arg.Name, arg.ID)
+	row := q.db.QueryRowContext(ctx, updatePerformer, arg.Name, arg.Phonetic, arg.ID)
 	var i Performer
 	err := row.Scan(
 		&i.ID,
@@ -129,6 +294,7 @@ func (q *Queries) UpdatePerformer(ctx context.Context, arg UpdatePerformerParams
 		&i.Created,
 		&i.Updated,
 		&i.Name,
+		&i.Phonetic,
 	)
 	return i, err
 }
@@ -7,20 +7,35 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+const countPerformerAliases = `-- name: CountPerformerAliases :one
+SELECT COUNT(*)::integer AS total
+FROM performer_alias
+WHERE alias ILIKE '%' || $1::text || '%'
+`
+
+func (q *Queries) CountPerformerAliases(ctx context.Context, query string) (int32, error) {
+	row := q.db.QueryRowContext(ctx, countPerformerAliases, query)
+	var total int32
+	err := row.Scan(&total)
+	return total, err
+}
+
 const createPerformerAlias = `-- name: CreatePerformerAlias :one
-INSERT INTO performer_alias (performer, alias)
-VALUES ($1, $2)
-RETURNING id, uuid, performer, created, updated, alias
+INSERT INTO performer_alias (performer, alias, phonetic)
+VALUES ($1, $2, $3)
+RETURNING id, uuid, performer, created, updated, alias, phonetic
 `
 
 type CreatePerformerAliasParams struct {
 	Performer int32
 	Alias     string
+	Phonetic  string
 }
 
 type CreatePerformerAliasRow struct {
@@ -30,10 +45,11 @@ type CreatePerformerAliasRow struct {
 	Created   time.Time
 	Updated   time.Time
 	Alias     string
+	Phonetic  string
 }
 
 func (q *Queries) CreatePerformerAlias(ctx context.Context, arg CreatePerformerAliasParams) (CreatePerformerAliasRow, error) {
-	row := q.db.QueryRowContext(ctx, createPerformerAlias, arg.Performer, arg.Alias)
+	row := q.db.QueryRowContext(ctx, createPerformerAlias, arg.Performer, arg.Alias, arg.Phonetic)
 	var i CreatePerformerAliasRow
 	err := row.Scan(
 		&i.ID,
@@ -42,6 +58,7 @@ func (q *Queries) CreatePerformerAlias(ctx context.Context, arg CreatePerformerA
 		&i.Created,
 		&i.Updated,
 		&i.Alias,
+		&i.Phonetic,
 	)
 	return i, err
 }
@@ -57,7 +74,7 @@ func (q *Queries) DeletePerformerAlias(ctx context.Context, id int32) error {
 }
 
 const getPerformerAlias = `-- name: GetPerformerAlias :one
-SELECT id, uuid, performer, created, updated, alias FROM performer_alias
+SELECT id, uuid, performer, created, updated, alias, phonetic FROM performer_alias
 WHERE id = $1
 `
 
@@ -68,6 +85,7 @@ type GetPerformerAliasRow struct {
 	Created   time.Time
 	Updated   time.Time
 	Alias     string
+	Phonetic  string
 }
 
 func (q *Queries) GetPerformerAlias(ctx context.Context, id int32) (GetPerformerAliasRow, error) {
@@ -80,12 +98,25 @@ func (q *Queries) GetPerformerAlias(ctx context.Context, id int32) (GetPerformer
 		&i.Created,
 		&i.Updated,
 		&i.Alias,
+		&i.Phonetic,
 	)
 	return i, err
 }
 
+const getPerformerAliasIDByUUID = `-- name: GetPerformerAliasIDByUUID :one
+SELECT id FROM performer_alias
+WHERE uuid = $1
+`
+
+func (q *Queries) GetPerformerAliasIDByUUID(ctx context.Context, uuid uuid.UUID) (int32, error) {
+	row := q.db.QueryRowContext(ctx, getPerformerAliasIDByUUID, uuid)
+	var id int32
+	err := row.Scan(&id)
+	return id, err
+}
+
 const listPerformerAliases = `-- name: ListPerformerAliases :many
-SELECT id, uuid, performer, created, updated, alias FROM performer_alias
+SELECT id, uuid, performer, created, updated, alias, phonetic FROM performer_alias
 ORDER BY alias
 `
 
@@ -96,6 +127,7 @@ type ListPerformerAliasesRow struct {
 	Created   time.Time
 	Updated   time.Time
 	Alias     string
+	Phonetic  string
 }
 
 func (q *Queries) ListPerformerAliases(ctx context.Context) ([]ListPerformerAliasesRow, error) {
@@ -114,6 +146,74 @@ func (q *Queries) ListPerformerAliases(ctx context.Context) ([]ListPerformerAlia
 			&i.Created,
 			&i.Updated,
 			&i.Alias,
+			&i.Phonetic,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPerformerAliasesPage = `-- name: ListPerformerAliasesPage :many
+SELECT id, uuid, performer, created, updated, alias, phonetic FROM performer_alias
+WHERE alias ILIKE '%' || $1::text || '%'
+ORDER BY
+    CASE WHEN $2::text = 'alias' THEN alias END,
+    CASE WHEN $2::text = '-alias' THEN alias END DESC,
+    CASE WHEN $2::text = 'created' THEN created END,
+    CASE WHEN $2::text = '-created' THEN created END DESC,
+    CASE WHEN $2::text = 'updated' THEN updated END,
+    CASE WHEN $2::text = '-updated' THEN updated END DESC,
+    id
+LIMIT $3 OFFSET $4
+`
+
+type ListPerformerAliasesPageParams struct {
+	Query   string
+	Sort    string
+	MaxRows sql.NullInt32
+	Skip    int32
+}
+
+type ListPerformerAliasesPageRow struct {
+	ID        int32
+	Uuid      uuid.UUID
+	Performer int32
+	Created   time.Time
+	Updated   time.Time
+	Alias     string
+	Phonetic  string
+}
+
+func (q *Queries) ListPerformerAliasesPage(ctx context.Context, arg ListPerformerAliasesPageParams) ([]ListPerformerAliasesPageRow, error) {
+	rows, err := q.db.QueryContext(ctx, listPerformerAliasesPage,
+		arg.Query,
+		arg.Sort,
+		arg.MaxRows,
+		arg.Skip,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPerformerAliasesPageRow
+	for rows.Next() {
+		var i ListPerformerAliasesPageRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Uuid,
+			&i.Performer,
+			&i.Created,
+			&i.Updated,
+			&i.Alias,
+			&i.Phonetic,
 		); err != nil {
 			return nil, err
 		}
@@ -128,16 +228,30 @@ func (q *Queries) ListPerformerAliases(ctx context.Context) ([]ListPerformerAlia
 	return items, nil
 }
 
+const lockPerformerAlias = `-- name: LockPerformerAlias :one
+SELECT updated FROM performer_alias
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) LockPerformerAlias(ctx context.Context, id int32) (time.Time, error) {
+	row := q.db.QueryRowContext(ctx, lockPerformerAlias, id)
+	var updated time.Time
+	err := row.Scan(&updated)
+	return updated, err
+}
+
 const updatePerformerAlias = `-- name: UpdatePerformerAlias :one
 UPDATE performer_alias
-SET performer = $1, alias = $2, updated = NOW()
-WHERE id = $3
-RETURNING id, uuid, performer, created, updated, alias
+SET performer = $1, alias = $2, phonetic = $3, updated = NOW()
+WHERE id = $4
+RETURNING id, uuid, performer, created, updated, alias, phonetic
 `
 
 type UpdatePerformerAliasParams struct {
 	Performer int32
 	Alias     string
+	Phonetic  string
 	ID        int32
 }
 
@@ -148,10 +262,11 @@ type UpdatePerformerAliasRow struct {
 	Created   time.Time
 	Updated   time.Time
 	Alias     string
+	Phonetic  string
 }
 
 func (q *Queries) UpdatePerformerAlias(ctx context.Context, arg UpdatePerformerAliasParams) (UpdatePerformerAliasRow, error) {
-	row := q.db.QueryRowContext(ctx, updatePerformerAlias, arg.Performer, arg.Alias, arg.ID)
+	row := q.db.QueryRowContext(ctx, updatePerformerAlias, arg.Performer, arg.Alias, arg.Phonetic, arg.ID)
 	var i UpdatePerformerAliasRow
 	err := row.Scan(
 		&i.ID,
@@ -160,6 +275,7 @@ func (q *Queries) UpdatePerformerAlias(ctx context.Context, arg UpdatePerformerA
 		&i.Created,
 		&i.Updated,
 		&i.Alias,
+		&i.Phonetic,
 	)
 	return i, err
 }
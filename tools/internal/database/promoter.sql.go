@@ -7,8 +7,25 @@ package database
 
 import (
 	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
 )
 
+const countPromoters = `-- name: CountPromoters :one
+SELECT COUNT(*)::integer AS total
+FROM promoter
+WHERE name ILIKE '%' || $1::text || '%'
+`
+
+func (q *Queries) CountPromoters(ctx context.Context, query string) (int32, error) {
+	row := q.db.QueryRowContext(ctx, countPromoters, query)
+	var total int32
+	err := row.Scan(&total)
+	return total, err
+}
+
 const createPromoter = `-- name: CreatePromoter :one
 INSERT INTO promoter (name)
 VALUES ($1)
@@ -56,6 +73,18 @@ func (q *Queries) GetPromoter(ctx context.Context, id int32) (Promoter, error) {
 	return i, err
 }
 
+const getPromoterIDByUUID = `-- name: GetPromoterIDByUUID :one
+SELECT id FROM promoter
+WHERE uuid = $1
+`
+
+func (q *Queries) GetPromoterIDByUUID(ctx context.Context, uuid uuid.UUID) (int32, error) {
+	row := q.db.QueryRowContext(ctx, getPromoterIDByUUID, uuid)
+	var id int32
+	err := row.Scan(&id)
+	return id, err
+}
+
 const listPromoters = `-- name: ListPromoters :many
 SELECT id, uuid, created, updated, name FROM promoter
 ORDER BY name
@@ -90,6 +119,131 @@ func (q *Queries) ListPromoters(ctx context.Context) ([]Promoter, error) {
 	return items, nil
 }
 
+const listPromotersPage = `-- name: ListPromotersPage :many
+SELECT id, uuid, created, updated, name FROM promoter
+WHERE name ILIKE '%' || $1::text || '%'
+ORDER BY
+    CASE WHEN $2::text = 'name' THEN name END,
+    CASE WHEN $2::text = '-name' THEN name END DESC,
+    CASE WHEN $2::text = 'created' THEN created END,
+    CASE WHEN $2::text = '-created' THEN created END DESC,
+    CASE WHEN $2::text = 'updated' THEN updated END,
+    CASE WHEN $2::text = '-updated' THEN updated END DESC,
+    id
+LIMIT $3 OFFSET $4
+`
+
+type ListPromotersPageParams struct {
+	Query   string
+	Sort    string
+	MaxRows sql.NullInt32
+	Skip    int32
+}
+
+func (q *Queries) ListPromotersPage(ctx context.Context, arg ListPromotersPageParams) ([]Promoter, error) {
+	rows, err := q.db.QueryContext(ctx, listPromotersPage,
+		arg.Query,
+		arg.Sort,
+		arg.MaxRows,
+		arg.Skip,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Promoter
+	for rows.Next() {
+		var i Promoter
+		if err := rows.Scan(
+			&i.ID,
+			&i.Uuid,
+			&i.Created,
+			&i.Updated,
+			&i.Name,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockPromoter = `-- name: LockPromoter :one
+SELECT updated FROM promoter
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) LockPromoter(ctx context.Context, id int32) (time.Time, error) {
+	row := q.db.QueryRowContext(ctx, lockPromoter, id)
+	var updated time.Time
+	err := row.Scan(&updated)
+	return updated, err
+}
+
+const searchPromoterCandidates = `-- name: SearchPromoterCandidates :many
+SELECT id, name, 0::integer AS alias_id, ''::text AS alias,
+    similarity(name, $1::text)::real AS score
+FROM promoter
+WHERE name % $1::text
+UNION ALL
+SELECT e.id, e.name, a.id AS alias_id, a.alias,
+    similarity(a.alias, $1::text)::real AS score
+FROM promoter_alias a
+JOIN promoter e ON e.id = a.promoter
+WHERE a.alias % $1::text
+ORDER BY score DESC, name
+LIMIT $2
+`
+
+type SearchPromoterCandidatesParams struct {
+	Query      string
+	MaxResults int32
+}
+
+type SearchPromoterCandidatesRow struct {
+	ID      int32
+	Name    string
+	AliasID int32
+	Alias   string
+	Score   float32
+}
+
+func (q *Queries) SearchPromoterCandidates(ctx context.Context, arg SearchPromoterCandidatesParams) ([]SearchPromoterCandidatesRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchPromoterCandidates, arg.Query, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchPromoterCandidatesRow
+	for rows.Next() {
+		var i SearchPromoterCandidatesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.AliasID,
+			&i.Alias,
+			&i.Score,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updatePromoter = `-- name: UpdatePromoter :one
 UPDATE promoter
 SET name = $1, updated = NOW()
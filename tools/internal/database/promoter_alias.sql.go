@@ -7,11 +7,25 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+const countPromoterAliases = `-- name: CountPromoterAliases :one
+SELECT COUNT(*)::integer AS total
+FROM promoter_alias
+WHERE alias ILIKE '%' || $1::text || '%'
+`
+
+func (q *Queries) CountPromoterAliases(ctx context.Context, query string) (int32, error) {
+	row := q.db.QueryRowContext(ctx, countPromoterAliases, query)
+	var total int32
+	err := row.Scan(&total)
+	return total, err
+}
+
 const createPromoterAlias = `-- name: CreatePromoterAlias :one
 INSERT INTO promoter_alias (promoter, alias)
 VALUES ($1, $2)
@@ -57,7 +71,7 @@ func (q *Queries) DeletePromoterAlias(ctx context.Context, id int32) error {
 }
 
 const getPerformerAliasByName = `-- name: GetPerformerAliasByName :one
-SELECT id, uuid, performer, alias, created, updated FROM performer_alias
+SELECT id, uuid, performer, alias, created, updated, phonetic FROM performer_alias
 WHERE alias = $1 LIMIT 1
 `
 
@@ -71,6 +85,7 @@ func (q *Queries) GetPerformerAliasByName(ctx context.Context, alias string) (Pe
 		&i.Alias,
 		&i.Created,
 		&i.Updated,
+		&i.Phonetic,
 	)
 	return i, err
 }
@@ -103,6 +118,18 @@ func (q *Queries) GetPromoterAlias(ctx context.Context, id int32) (GetPromoterAl
 	return i, err
 }
 
+const getPromoterAliasIDByUUID = `-- name: GetPromoterAliasIDByUUID :one
+SELECT id FROM promoter_alias
+WHERE uuid = $1
+`
+
+func (q *Queries) GetPromoterAliasIDByUUID(ctx context.Context, uuid uuid.UUID) (int32, error) {
+	row := q.db.QueryRowContext(ctx, getPromoterAliasIDByUUID, uuid)
+	var id int32
+	err := row.Scan(&id)
+	return id, err
+}
+
 const listPromoterAliases = `-- name: ListPromoterAliases :many
 SELECT id, uuid, promoter, created, updated, alias FROM promoter_alias
 ORDER BY alias
@@ -147,6 +174,84 @@ func (q *Queries) ListPromoterAliases(ctx context.Context) ([]ListPromoterAliase
 	return items, nil
 }
 
+const listPromoterAliasesPage = `-- name: ListPromoterAliasesPage :many
+SELECT id, uuid, promoter, created, updated, alias FROM promoter_alias
+WHERE alias ILIKE '%' || $1::text || '%'
+ORDER BY
+    CASE WHEN $2::text = 'alias' THEN alias END,
+    CASE WHEN $2::text = '-alias' THEN alias END DESC,
+    CASE WHEN $2::text = 'created' THEN created END,
+    CASE WHEN $2::text = '-created' THEN created END DESC,
+    CASE WHEN $2::text = 'updated' THEN updated END,
+    CASE WHEN $2::text = '-updated' THEN updated END DESC,
+    id
+LIMIT $3 OFFSET $4
+`
+
+type ListPromoterAliasesPageParams struct {
+	Query   string
+	Sort    string
+	MaxRows sql.NullInt32
+	Skip    int32
+}
+
+type ListPromoterAliasesPageRow struct {
+	ID       int32
+	Uuid     uuid.UUID
+	Promoter int32
+	Created  time.Time
+	Updated  time.Time
+	Alias    string
+}
+
+func (q *Queries) ListPromoterAliasesPage(ctx context.Context, arg ListPromoterAliasesPageParams) ([]ListPromoterAliasesPageRow, error) {
+	rows, err := q.db.QueryContext(ctx, listPromoterAliasesPage,
+		arg.Query,
+		arg.Sort,
+		arg.MaxRows,
+		arg.Skip,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPromoterAliasesPageRow
+	for rows.Next() {
+		var i ListPromoterAliasesPageRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Uuid,
+			&i.Promoter,
+			&i.Created,
+			&i.Updated,
+			&i.Alias,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockPromoterAlias = `-- name: LockPromoterAlias :one
+SELECT updated FROM promoter_alias
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) LockPromoterAlias(ctx context.Context, id int32) (time.Time, error) {
+	row := q.db.QueryRowContext(ctx, lockPromoterAlias, id)
+	var updated time.Time
+	err := row.Scan(&updated)
+	return updated, err
+}
+
 const updatePromoterAlias = `-- name: UpdatePromoterAlias :one
 UPDATE promoter_alias
 SET promoter = $1, alias = $2, updated = NOW()
@@ -7,27 +7,47 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+const countStageRoles = `-- name: CountStageRoles :one
+SELECT COUNT(*)::integer AS total
+FROM stage_role
+WHERE pattern ILIKE '%' || $1::text || '%'
+`
+
+func (q *Queries) CountStageRoles(ctx context.Context, query string) (int32, error) {
+	row := q.db.QueryRowContext(ctx, countStageRoles, query)
+	var total int32
+	err := row.Scan(&total)
+	return total, err
+}
+
 const createStageRole = `-- name: CreateStageRole :one
-INSERT INTO stage_role (pattern)
-VALUES ($1)
-RETURNING id, uuid, created, updated, pattern
+INSERT INTO stage_role (pattern, kind)
+VALUES ($1, $2)
+RETURNING id, uuid, created, updated, pattern, kind
 `
 
+type CreateStageRoleParams struct {
+	Pattern string
+	Kind    string
+}
+
 type CreateStageRoleRow struct {
 	ID      int32
 	Uuid    uuid.UUID
 	Created time.Time
 	Updated time.Time
 	Pattern string
+	Kind    string
 }
 
-func (q *Queries) CreateStageRole(ctx context.Context, pattern string) (CreateStageRoleRow, error) {
-	row := q.db.QueryRowContext(ctx, createStageRole, pattern)
+func (q *Queries) CreateStageRole(ctx context.Context, arg CreateStageRoleParams) (CreateStageRoleRow, error) {
+	row := q.db.QueryRowContext(ctx, createStageRole, arg.Pattern, arg.Kind)
 	var i CreateStageRoleRow
 	err := row.Scan(
 		&i.ID,
@@ -35,6 +55,7 @@ func (q *Queries) CreateStageRole(ctx context.Context, pattern string) (CreateSt
 		&i.Created,
 		&i.Updated,
 		&i.Pattern,
+		&i.Kind,
 	)
 	return i, err
 }
@@ -50,7 +71,7 @@ func (q *Queries) DeleteStageRole(ctx context.Context, id int32) error {
 }
 
 const getStageRole = `-- name: GetStageRole :one
-SELECT id, uuid, created, updated, pattern FROM stage_role
+SELECT id, uuid, created, updated, pattern, kind FROM stage_role
 WHERE id = $1
 `
 
@@ -60,6 +81,7 @@ type GetStageRoleRow struct {
 	Created time.Time
 	Updated time.Time
 	Pattern string
+	Kind    string
 }
 
 func (q *Queries) GetStageRole(ctx context.Context, id int32) (GetStageRoleRow, error) {
@@ -71,12 +93,25 @@ func (q *Queries) GetStageRole(ctx context.Context, id int32) (GetStageRoleRow,
 		&i.Created,
 		&i.Updated,
 		&i.Pattern,
+		&i.Kind,
 	)
 	return i, err
 }
 
+const getStageRoleIDByUUID = `-- name: GetStageRoleIDByUUID :one
+SELECT id FROM stage_role
+WHERE uuid = $1
+`
+
+func (q *Queries) GetStageRoleIDByUUID(ctx context.Context, uuid uuid.UUID) (int32, error) {
+	row := q.db.QueryRowContext(ctx, getStageRoleIDByUUID, uuid)
+	var id int32
+	err := row.Scan(&id)
+	return id, err
+}
+
 const listStageRoles = `-- name: ListStageRoles :many
-SELECT id, uuid, created, updated, pattern FROM stage_role
+SELECT id, uuid, created, updated, pattern, kind FROM stage_role
 ORDER BY pattern
 `
 
@@ -86,6 +121,7 @@ type ListStageRolesRow struct {
 	Created time.Time
 	Updated time.Time
 	Pattern string
+	Kind    string
 }
 
 func (q *Queries) ListStageRoles(ctx context.Context) ([]ListStageRolesRow, error) {
@@ -103,6 +139,7 @@ func (q *Queries) ListStageRoles(ctx context.Context) ([]ListStageRolesRow, erro
 			&i.Created,
 			&i.Updated,
 			&i.Pattern,
+			&i.Kind,
 		); err != nil {
 			return nil, err
 		}
@@ -117,15 +154,96 @@ func (q *Queries) ListStageRoles(ctx context.Context) ([]ListStageRolesRow, erro
 	return items, nil
 }
 
+const listStageRolesPage = `-- name: ListStageRolesPage :many
+SELECT id, uuid, created, updated, pattern, kind FROM stage_role
+WHERE pattern ILIKE '%' || $1::text || '%'
+ORDER BY
+    CASE WHEN $2::text = 'pattern' THEN pattern END,
+    CASE WHEN $2::text = '-pattern' THEN pattern END DESC,
+    CASE WHEN $2::text = 'kind' THEN kind END,
+    CASE WHEN $2::text = '-kind' THEN kind END DESC,
+    CASE WHEN $2::text = 'created' THEN created END,
+    CASE WHEN $2::text = '-created' THEN created END DESC,
+    CASE WHEN $2::text = 'updated' THEN updated END,
+    CASE WHEN $2::text = '-updated' THEN updated END DESC,
+    id
+LIMIT $3 OFFSET $4
+`
+
+type ListStageRolesPageParams struct {
+	Query   string
+	Sort    string
+	MaxRows sql.NullInt32
+	Skip    int32
+}
+
+type ListStageRolesPageRow struct {
+	ID      int32
+	Uuid    uuid.UUID
+	Created time.Time
+	Updated time.Time
+	Pattern string
+	Kind    string
+}
+
+func (q *Queries) ListStageRolesPage(ctx context.Context, arg ListStageRolesPageParams) ([]ListStageRolesPageRow, error) {
+	rows, err := q.db.QueryContext(ctx, listStageRolesPage,
+		arg.Query,
+		arg.Sort,
+		arg.MaxRows,
+		arg.Skip,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListStageRolesPageRow
+	for rows.Next() {
+		var i ListStageRolesPageRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Uuid,
+			&i.Created,
+			&i.Updated,
+			&i.Pattern,
+			&i.Kind,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockStageRole = `-- name: LockStageRole :one
+SELECT updated FROM stage_role
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) LockStageRole(ctx context.Context, id int32) (time.Time, error) {
+	row := q.db.QueryRowContext(ctx, lockStageRole, id)
+	var updated time.Time
+	err := row.Scan(&updated)
+	return updated, err
+}
+
 const updateStageRole = `-- name: UpdateStageRole :one
 UPDATE stage_role
-SET pattern = $1, updated = NOW()
-WHERE id = $2
-RETURNING id, uuid, created, updated, pattern
+SET pattern = $1, kind = $2, updated = NOW()
+WHERE id = $3
+RETURNING id, uuid, created, updated, pattern, kind
 `
 
 type UpdateStageRoleParams struct {
 	Pattern string
+	Kind    string
 	ID      int32
 }
 
@@ -135,10 +253,11 @@ type UpdateStageRoleRow struct {
 	Created time.Time
 	Updated time.Time
 	Pattern string
+	Kind    string
 }
 
 func (q *Queries) UpdateStageRole(ctx context.Context, arg UpdateStageRoleParams) (UpdateStageRoleRow, error) {
-	row := q.db.QueryRowContext(ctx, updateStageRole, arg.Pattern, arg.ID)
+	row := q.db.QueryRowContext(ctx, updateStageRole, arg.Pattern, arg.Kind, arg.ID)
 	var i UpdateStageRoleRow
 	err := row.Scan(
 		&i.ID,
@@ -146,6 +265,7 @@ func (q *Queries) UpdateStageRole(ctx context.Context, arg UpdateStageRoleParams
 		&i.Created,
 		&i.Updated,
 		&i.Pattern,
+		&i.Kind,
 	)
 	return i, err
 }
@@ -7,18 +7,54 @@ package database
 
 import (
 	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
 )
 
+const countVenues = `-- name: CountVenues :one
+SELECT COUNT(*)::integer AS total
+FROM venue
+WHERE (name ILIKE '%' || $1::text || '%'
+    OR city ILIKE '%' || $1::text || '%')
+`
+
+func (q *Queries) CountVenues(ctx context.Context, query string) (int32, error) {
+	row := q.db.QueryRowContext(ctx, countVenues, query)
+	var total int32
+	err := row.Scan(&total)
+	return total, err
+}
+
 const createVenue = `-- name: CreateVenue :one
 INSERT INTO venue (
-    name
+    name,
+    city,
+    country,
+    latitude,
+    longitude
 ) VALUES (
-    $1
-) RETURNING id, uuid, created, updated, name
+    $1, $2, $3, $4, $5
+) RETURNING id, uuid, created, updated, name, city, country, latitude, longitude
 `
 
-func (q *Queries) CreateVenue(ctx context.Context, name string) (Venue, error) {
-	row := q.db.QueryRowContext(ctx, createVenue, name)
+type CreateVenueParams struct {
+	Name      string
+	City      string
+	Country   string
+	Latitude  sql.NullFloat64
+	Longitude sql.NullFloat64
+}
+
+func (q *Queries) CreateVenue(ctx context.Context, arg CreateVenueParams) (Venue, error) {
+	row := q.db.QueryRowContext(ctx, createVenue,
+		arg.Name,
+		arg.City,
+		arg.Country,
+		arg.Latitude,
+		arg.Longitude,
+	)
 	var i Venue
 	err := row.Scan(
 		&i.ID,
@@ -26,6 +62,10 @@ func (q *Queries) CreateVenue(ctx context.Context, name string) (Venue, error) {
 		&i.Created,
 		&i.Updated,
 		&i.Name,
+		&i.City,
+		&i.Country,
+		&i.Latitude,
+		&i.Longitude,
 	)
 	return i, err
 }
@@ -41,7 +81,7 @@ func (q *Queries) DeleteVenue(ctx context.Context, id int32) error {
 }
 
 const getVenue = `-- name: GetVenue :one
-SELECT id, uuid, created, updated, name FROM venue
+SELECT id, uuid, created, updated, name, city, country, latitude, longitude FROM venue
 WHERE id = $1 LIMIT 1
 `
 
@@ -54,12 +94,28 @@ func (q *Queries) GetVenue(ctx context.Context, id int32) (Venue, error) {
 		&i.Created,
 		&i.Updated,
 		&i.Name,
+		&i.City,
+		&i.Country,
+		&i.Latitude,
+		&i.Longitude,
 	)
 	return i, err
 }
 
+const getVenueIDByUUID = `-- name: GetVenueIDByUUID :one
+SELECT id FROM venue
+WHERE uuid = $1
+`
+
+func (q *Queries) GetVenueIDByUUID(ctx context.Context, uuid uuid.UUID) (int32, error) {
+	row := q.db.QueryRowContext(ctx, getVenueIDByUUID, uuid)
+	var id int32
+	err := row.Scan(&id)
+	return id, err
+}
+
 const listVenues = `-- name: ListVenues :many
-SELECT id, uuid, created, updated, name FROM venue
+SELECT id, uuid, created, updated, name, city, country, latitude, longitude FROM venue
 ORDER BY name
 `
 
@@ -78,6 +134,146 @@ func (q *Queries) ListVenues(ctx context.Context) ([]Venue, error) {
 			&i.Created,
 			&i.Updated,
 			&i.Name,
+			&i.City,
+			&i.Country,
+			&i.Latitude,
+			&i.Longitude,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listVenuesPage = `-- name: ListVenuesPage :many
+SELECT id, uuid, created, updated, name, city, country, latitude, longitude FROM venue
+WHERE (name ILIKE '%' || $1::text || '%'
+    OR city ILIKE '%' || $1::text || '%')
+ORDER BY
+    CASE WHEN $2::text = 'name' THEN name END,
+    CASE WHEN $2::text = '-name' THEN name END DESC,
+    CASE WHEN $2::text = 'city' THEN city END,
+    CASE WHEN $2::text = '-city' THEN city END DESC,
+    CASE WHEN $2::text = 'country' THEN country END,
+    CASE WHEN $2::text = '-country' THEN country END DESC,
+    CASE WHEN $2::text = 'created' THEN created END,
+    CASE WHEN $2::text = '-created' THEN created END DESC,
+    CASE WHEN $2::text = 'updated' THEN updated END,
+    CASE WHEN $2::text = '-updated' THEN updated END DESC,
+    id
+LIMIT $3 OFFSET $4
+`
+
+type ListVenuesPageParams struct {
+	Query   string
+	Sort    string
+	MaxRows sql.NullInt32
+	Skip    int32
+}
+
+func (q *Queries) ListVenuesPage(ctx context.Context, arg ListVenuesPageParams) ([]Venue, error) {
+	rows, err := q.db.QueryContext(ctx, listVenuesPage,
+		arg.Query,
+		arg.Sort,
+		arg.MaxRows,
+		arg.Skip,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Venue
+	for rows.Next() {
+		var i Venue
+		if err := rows.Scan(
+			&i.ID,
+			&i.Uuid,
+			&i.Created,
+			&i.Updated,
+			&i.Name,
+			&i.City,
+			&i.Country,
+			&i.Latitude,
+			&i.Longitude,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockVenue = `-- name: LockVenue :one
+SELECT updated FROM venue
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) LockVenue(ctx context.Context, id int32) (time.Time, error) {
+	row := q.db.QueryRowContext(ctx, lockVenue, id)
+	var updated time.Time
+	err := row.Scan(&updated)
+	return updated, err
+}
+
+const searchVenueCandidates = `-- name: SearchVenueCandidates :many
+SELECT id, name, city, 0::integer AS alias_id, ''::text AS alias,
+    similarity(name, $1::text)::real AS score
+FROM venue
+WHERE name % $1::text
+UNION ALL
+SELECT e.id, e.name, e.city, a.id AS alias_id, a.alias,
+    similarity(a.alias, $1::text)::real AS score
+FROM venue_alias a
+JOIN venue e ON e.id = a.venue
+WHERE a.alias % $1::text
+ORDER BY score DESC, name
+LIMIT $2
+`
+
+type SearchVenueCandidatesParams struct {
+	Query      string
+	MaxResults int32
+}
+
+type SearchVenueCandidatesRow struct {
+	ID      int32
+	Name    string
+	City    string
+	AliasID int32
+	Alias   string
+	Score   float32
+}
+
+func (q *Queries) SearchVenueCandidates(ctx context.Context, arg SearchVenueCandidatesParams) ([]SearchVenueCandidatesRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchVenueCandidates, arg.Query, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchVenueCandidatesRow
+	for rows.Next() {
+		var i SearchVenueCandidatesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.City,
+			&i.AliasID,
+			&i.Alias,
+			&i.Score,
 		); err != nil {
 			return nil, err
 		}
@@ -96,18 +292,33 @@ const updateVenue = `-- name: UpdateVenue :one
 UPDATE venue
 SET
     name = $2,
+    city = $3,
+    country = $4,
+    latitude = $5,
+    longitude = $6,
     updated = now()
 WHERE id = $1
-RETURNING id, uuid, created, updated, name
+RETURNING id, uuid, created, updated, name, city, country, latitude, longitude
 `
 
 type UpdateVenueParams struct {
-	ID   int32
-	Name string
+	ID        int32
+	Name      string
+	City      string
+	Country   string
+	Latitude  sql.NullFloat64
+	Longitude sql.NullFloat64
 }
 
 func (q *Queries) UpdateVenue(ctx context.Context, arg UpdateVenueParams) (Venue, error) {
-	row := q.db.QueryRowContext(ctx, updateVenue, arg.ID, arg.Name)
+	row := q.db.QueryRowContext(ctx, updateVenue,
+		arg.ID,
+		arg.Name,
+		arg.City,
+		arg.Country,
+		arg.Latitude,
+		arg.Longitude,
+	)
 	var i Venue
 	err := row.Scan(
 		&i.ID,
@@ -115,6 +326,10 @@ func (q *Queries) UpdateVenue(ctx context.Context, arg UpdateVenueParams) (Venue
 		&i.Created,
 		&i.Updated,
 		&i.Name,
+		&i.City,
+		&i.Country,
+		&i.Latitude,
+		&i.Longitude,
 	)
 	return i, err
 }
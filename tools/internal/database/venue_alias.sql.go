@@ -7,11 +7,25 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+const countVenueAliases = `-- name: CountVenueAliases :one
+SELECT COUNT(*)::integer AS total
+FROM venue_alias
+WHERE alias ILIKE '%' || $1::text || '%'
+`
+
+func (q *Queries) CountVenueAliases(ctx context.Context, query string) (int32, error) {
+	row := q.db.QueryRowContext(ctx, countVenueAliases, query)
+	var total int32
+	err := row.Scan(&total)
+	return total, err
+}
+
 const createVenueAlias = `-- name: CreateVenueAlias :one
 INSERT INTO venue_alias (venue, alias)
 VALUES ($1, $2)
@@ -84,6 +98,18 @@ func (q *Queries) GetVenueAlias(ctx context.Context, id int32) (GetVenueAliasRow
 	return i, err
 }
 
+const getVenueAliasIDByUUID = `-- name: GetVenueAliasIDByUUID :one
+SELECT id FROM venue_alias
+WHERE uuid = $1
+`
+
+func (q *Queries) GetVenueAliasIDByUUID(ctx context.Context, uuid uuid.UUID) (int32, error) {
+	row := q.db.QueryRowContext(ctx, getVenueAliasIDByUUID, uuid)
+	var id int32
+	err := row.Scan(&id)
+	return id, err
+}
+
 const listVenueAliases = `-- name: ListVenueAliases :many
 SELECT id, uuid, venue, created, updated, alias FROM venue_alias
 ORDER BY alias
@@ -128,6 +154,84 @@ func (q *Queries) ListVenueAliases(ctx context.Context) ([]ListVenueAliasesRow,
 	return items, nil
 }
 
+const listVenueAliasesPage = `-- name: ListVenueAliasesPage :many
+SELECT id, uuid, venue, created, updated, alias FROM venue_alias
+WHERE alias ILIKE '%' || $1::text || '%'
+ORDER BY
+    CASE WHEN $2::text = 'alias' THEN alias END,
+    CASE WHEN $2::text = '-alias' THEN alias END DESC,
+    CASE WHEN $2::text = 'created' THEN created END,
+    CASE WHEN $2::text = '-created' THEN created END DESC,
+    CASE WHEN $2::text = 'updated' THEN updated END,
+    CASE WHEN $2::text = '-updated' THEN updated END DESC,
+    id
+LIMIT $3 OFFSET $4
+`
+
+type ListVenueAliasesPageParams struct {
+	Query   string
+	Sort    string
+	MaxRows sql.NullInt32
+	Skip    int32
+}
+
+type ListVenueAliasesPageRow struct {
+	ID      int32
+	Uuid    uuid.UUID
+	Venue   int32
+	Created time.Time
+	Updated time.Time
+	Alias   string
+}
+
+func (q *Queries) ListVenueAliasesPage(ctx context.Context, arg ListVenueAliasesPageParams) ([]ListVenueAliasesPageRow, error) {
+	rows, err := q.db.QueryContext(ctx, listVenueAliasesPage,
+		arg.Query,
+		arg.Sort,
+		arg.MaxRows,
+		arg.Skip,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListVenueAliasesPageRow
+	for rows.Next() {
+		var i ListVenueAliasesPageRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Uuid,
+			&i.Venue,
+			&i.Created,
+			&i.Updated,
+			&i.Alias,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockVenueAlias = `-- name: LockVenueAlias :one
+SELECT updated FROM venue_alias
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) LockVenueAlias(ctx context.Context, id int32) (time.Time, error) {
+	row := q.db.QueryRowContext(ctx, lockVenueAlias, id)
+	var updated time.Time
+	err := row.Scan(&updated)
+	return updated, err
+}
+
 const updateVenueAlias = `-- name: UpdateVenueAlias :one
 UPDATE venue_alias
 SET venue = $1, alias = $2, updated = NOW()
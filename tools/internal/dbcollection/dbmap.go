@@ -97,6 +97,25 @@ func (m *DBMap[K, V]) GetValue(key K) (V, bool) {
 	return val, ok
 }
 
+// Len returns the number of keys in the cached map.
+func (m *DBMap[K, V]) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.dataMap)
+}
+
+// Range calls f for each key and value in the cached map while holding the read lock,
+// avoiding the copy made by Get. Iteration stops early if f returns false.
+func (m *DBMap[K, V]) Range(f func(key K, value V) bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for k, v := range m.dataMap {
+		if !f(k, v) {
+			return
+		}
+	}
+}
+
 // GetDBQueried returns the number of times the database was queried for the main data.
 func (m *DBMap[K, V]) GetDBQueried() int {
 	m.mu.RLock()
@@ -172,3 +172,38 @@ func TestNewDBMap_NilReturn(t *testing.T) {
 		t.Error("Get() returned non-empty map, expected empty map")
 	}
 }
+
+func TestDBMap_Range(t *testing.T) {
+	ctx := context.Background()
+	m, err := NewDBMap(ctx,
+		func(_ context.Context) (time.Time, error) { return time.Now(), nil },
+		func(_ context.Context) (map[string]int, error) {
+			return map[string]int{"one": 1, "two": 2, "three": 3}, nil
+		},
+	)
+	if err != nil {
+		t.Fatalf("NewDBMap failed: %v", err)
+	}
+
+	if m.Len() != 3 {
+		t.Errorf("Len() = %d, want 3", m.Len())
+	}
+
+	sum := 0
+	m.Range(func(_ string, v int) bool {
+		sum += v
+		return true
+	})
+	if sum != 6 {
+		t.Errorf("Range visited values summing to %d, want 6", sum)
+	}
+
+	visited := 0
+	m.Range(func(_ string, _ int) bool {
+		visited++
+		return false
+	})
+	if visited != 1 {
+		t.Errorf("Range visited %d entries after f returned false, want 1", visited)
+	}
+}
@@ -0,0 +1,124 @@
+package metadata
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Confidence levels assigned to a candidate according to the stage that found it. All but
+// ExactConfidence are defaults that can be tuned for each type of entity, see MatchSettings.
+const (
+	ExactConfidence      = 100 // Exact match on the entity's own name
+	AliasConfidence      = 75  // Exact match on one of the entity's aliases
+	FuzzyNameConfidence  = 50  // Fuzzy match on the entity's own name
+	FuzzyAliasConfidence = 25  // Fuzzy match on one of the entity's aliases
+	PhoneticConfidence   = 20  // Name or alias that sounds the same, see PhoneticKey
+	InferredConfidence   = 40  // Festival inferred from the venue and date of an event rather than its name
+)
+
+// DefaultCandidates is the number of ranked candidates returned by the matchers when
+// no other limit is configured.
+const DefaultCandidates = 5
+
+// Stage identifies the matching stage that found a candidate.
+type Stage string
+
+// Stages of MatchIndex.Candidates, in the order their confidence levels rank.
+const (
+	StageExact      Stage = "exact"
+	StageAlias      Stage = "alias"
+	StageFuzzyName  Stage = "fuzzy-name"
+	StageFuzzyAlias Stage = "fuzzy-alias"
+	StagePhonetic   Stage = "phonetic"
+)
+
+// StageInferred identifies a match that was not found from a name at all, such as a
+// festival inferred from the venue and date of an event.
+const StageInferred Stage = "inferred"
+
+// Candidate is a possible match for a raw name.
+type Candidate struct {
+	ID          int32        `json:"id"`
+	Match       string       `json:"match"`
+	Alias       string       `json:"alias,omitempty"`
+	City        string       `json:"city,omitempty"`
+	Confidence  int          `json:"confidence"`
+	Score       float64      `json:"score"`
+	Explanation *Explanation `json:"explanation,omitempty"`
+	Boost       *Boost       `json:"boost,omitempty"` // Raise in Confidence from past events, see HistoryBoost
+}
+
+// Explanation records why a candidate was matched, so a wrong match can be traced back
+// to the name or alias responsible for it.
+type Explanation struct {
+	Stage    Stage  `json:"stage"`
+	AliasID  int32  `json:"alias_id,omitempty"` // ID of the alias row that matched, if any
+	Alias    string `json:"alias,omitempty"`    // Text of the alias that matched, if any
+	Distance int    `json:"distance"`           // Levenshtein distance between Input and Compared
+	Input    string `json:"input"`              // Normalised form of the raw name
+	Compared string `json:"compared"`           // Normalised form of the name or alias it matched
+}
+
+// String formats the explanation for display on a single line.
+func (e *Explanation) String() string {
+	if e == nil {
+		return "no match"
+	}
+	if e.Stage == StageInferred {
+		return fmt.Sprintf("%s: %q from %q", e.Stage, e.Compared, e.Input)
+	}
+	s := fmt.Sprintf("%s: %q vs %q, distance %d", e.Stage, e.Input, e.Compared, e.Distance)
+	if e.AliasID != 0 {
+		s += fmt.Sprintf(", alias %d %q", e.AliasID, e.Alias)
+	}
+	return s
+}
+
+// SortCandidates orders candidates best first: by confidence, then by score, then by name.
+func SortCandidates(candidates []Candidate) {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidateLess(candidates[i], candidates[j])
+	})
+}
+
+// ContenderMargin is how far the Similarity score of a candidate found at the same stage as
+// the best one may fall short of it for the two still to be treated as equally good.
+const ContenderMargin = 0.05
+
+// Contenders returns the leading candidates that were found at the same stage as the best
+// one with a score within ContenderMargin of it, when there is more than one of them,
+// meaning the raw name matched several distinct entities equally well. Candidates must
+// already be sorted with SortCandidates.
+func Contenders(candidates []Candidate) []Candidate {
+	n, stage := 0, bestStageCount(candidates)
+	for n < stage && candidates[0].Score-candidates[n].Score <= ContenderMargin {
+		n++
+	}
+	if n < 2 {
+		return nil
+	}
+	return candidates[:n]
+}
+
+// bestStageCount returns the number of leading candidates with the best confidence.
+func bestStageCount(candidates []Candidate) int {
+	n := 0
+	for n < len(candidates) && candidates[n].Confidence == candidates[0].Confidence {
+		n++
+	}
+	return n
+}
+
+// candidateLess reports whether a should be ranked ahead of b.
+func candidateLess(a, b Candidate) bool {
+	if a.Confidence != b.Confidence {
+		return a.Confidence > b.Confidence
+	}
+	if a.Score != b.Score {
+		return a.Score > b.Score
+	}
+	if a.Match != b.Match {
+		return a.Match < b.Match
+	}
+	return a.ID < b.ID
+}
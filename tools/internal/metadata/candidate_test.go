@@ -0,0 +1,62 @@
+package metadata
+
+import "testing"
+
+func TestContenders(t *testing.T) {
+	tests := []struct {
+		name       string
+		candidates []Candidate
+		want       []int32
+	}{
+		{
+			name: "Equal exact matches",
+			candidates: []Candidate{
+				{ID: 1, Match: "Bob Dylan", Confidence: ExactConfidence, Score: 1},
+				{ID: 2, Match: "Bob Dylan", Confidence: ExactConfidence, Score: 1},
+			},
+			want: []int32{1, 2},
+		},
+		{
+			name: "Close fuzzy matches",
+			candidates: []Candidate{
+				{ID: 1, Match: "Sophie Hunter", Confidence: FuzzyNameConfidence, Score: 0.92},
+				{ID: 2, Match: "Sophie Hunger", Confidence: FuzzyNameConfidence, Score: 0.90},
+				{ID: 3, Match: "Sophie Hunt", Confidence: FuzzyNameConfidence, Score: 0.71},
+			},
+			want: []int32{1, 2},
+		},
+		{
+			name: "Clear fuzzy winner",
+			candidates: []Candidate{
+				{ID: 1, Match: "Sophie Hunter", Confidence: FuzzyNameConfidence, Score: 0.98},
+				{ID: 2, Match: "Sophie Hunt", Confidence: FuzzyNameConfidence, Score: 0.71},
+			},
+		},
+		{
+			name: "Close score at a worse stage",
+			candidates: []Candidate{
+				{ID: 1, Match: "Sophie Hunter", Confidence: AliasConfidence, Score: 1},
+				{ID: 2, Match: "Sophie Hunter", Confidence: FuzzyNameConfidence, Score: 1},
+			},
+		},
+		{
+			name:       "Single candidate",
+			candidates: []Candidate{{ID: 1, Match: "Alice", Confidence: ExactConfidence, Score: 1}},
+		},
+		{name: "No candidates"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Contenders(tt.candidates)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Contenders() = %v, want IDs %v", got, tt.want)
+			}
+			for i, c := range got {
+				if c.ID != tt.want[i] {
+					t.Errorf("Contenders()[%d].ID = %d, want %d", i, c.ID, tt.want[i])
+				}
+			}
+		})
+	}
+}
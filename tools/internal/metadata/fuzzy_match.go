@@ -1,6 +1,7 @@
 package metadata
 
 import (
+	"sort"
 	"strings"
 	"unicode"
 )
@@ -13,93 +14,261 @@ func PrepareForFuzzy(s string) string {
 	return s
 }
 
-// IsFuzzyMatch determines if two strings are similar enough to be considered a match.
-func IsFuzzyMatch(s1, s2 string) bool {
-	d := Levenshtein(PrepareForFuzzy(s1), PrepareForFuzzy(s2))
-	if d == 0 {
-		return true
+// Weights used to blend the individual measures into a single Similarity score.
+const (
+	levenshteinWeight = 0.4
+	jaroWinklerWeight = 0.3
+	tokenSetWeight    = 0.3
+)
+
+// MinSimilarity is the default lowest Similarity score accepted as a fuzzy match, see MatchSettings.
+const MinSimilarity = 0.85
+
+// Similarity scores how alike two strings are, from 0 (nothing in common) to 1 (identical
+// once prepared for fuzzy matching). It blends normalised Levenshtein distance, which
+// penalises typos, Jaro-Winkler, which favours a shared prefix, and the token-set ratio,
+// which tolerates reordered or extra words.
+func Similarity(s1, s2 string) float64 {
+	p1, p2 := PrepareForFuzzy(s1), PrepareForFuzzy(s2)
+	if p1 == p2 {
+		return 1
+	}
+	return levenshteinWeight*LevenshteinRatio(p1, p2) +
+		jaroWinklerWeight*JaroWinkler(p1, p2) +
+		tokenSetWeight*TokenSetRatio(p1, p2)
+}
+
+// LevenshteinRatio normalises the Levenshtein distance between two strings to a score
+// between 0 and 1, where 1 means the strings are identical.
+func LevenshteinRatio(s1, s2 string) float64 {
+	maxLen := len([]rune(s1))
+	if l := len([]rune(s2)); l > maxLen {
+		maxLen = l
+	}
+	if maxLen == 0 {
+		return 1
 	}
+	return 1 - float64(Levenshtein(s1, s2))/float64(maxLen)
+}
 
-	maxLen := len(s1)
-	if len(s2) > maxLen {
-		maxLen = len(s2)
+// JaroWinkler calculates the Jaro-Winkler similarity between two strings, a score
+// between 0 and 1 that gives extra weight to a common prefix of up to 4 characters.
+func JaroWinkler(s1, s2 string) float64 {
+	r1, r2 := []rune(s1), []rune(s2)
+	n, m := len(r1), len(r2)
+	if n == 0 && m == 0 {
+		return 1
 	}
+	if n == 0 || m == 0 {
+		return 0
+	}
+
+	matchDist := max(n, m)/2 - 1
+	if matchDist < 0 {
+		matchDist = 0
+	}
+	matched1 := make([]bool, n)
+	matched2 := make([]bool, m)
+	matches := 0
+	for i := 0; i < n; i++ {
+		lo := max(0, i-matchDist)
+		hi := min(m-1, i+matchDist)
+		for j := lo; j <= hi; j++ {
+			if matched2[j] || r1[i] != r2[j] {
+				continue
+			}
+			matched1[i], matched2[j] = true, true
+			matches++
+			break
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	transpositions := 0
+	k := 0
+	for i := 0; i < n; i++ {
+		if !matched1[i] {
+			continue
+		}
+		for !matched2[k] {
+			k++
+		}
+		if r1[i] != r2[k] {
+			transpositions++
+		}
+		k++
+	}
+
+	mf := float64(matches)
+	jaro := (mf/float64(n) + mf/float64(m) + (mf-float64(transpositions)/2)/mf) / 3
+
+	prefix := 0
+	for prefix < min(4, n, m) && r1[prefix] == r2[prefix] {
+		prefix++
+	}
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
+
+// TokenSetRatio compares two strings as sets of words, so that word order and words
+// present in only one of the strings count for less. It returns a score between 0 and 1.
+func TokenSetRatio(s1, s2 string) float64 {
+	set1 := tokenSet(s1)
+	set2 := tokenSet(s2)
+
+	var common, only1, only2 []string
+	for t := range set1 {
+		if _, ok := set2[t]; ok {
+			common = append(common, t)
+		} else {
+			only1 = append(only1, t)
+		}
+	}
+	for t := range set2 {
+		if _, ok := set1[t]; !ok {
+			only2 = append(only2, t)
+		}
+	}
+	sort.Strings(common)
+	sort.Strings(only1)
+	sort.Strings(only2)
+
+	intersection := strings.Join(common, " ")
+	combined1 := strings.TrimSpace(intersection + " " + strings.Join(only1, " "))
+	combined2 := strings.TrimSpace(intersection + " " + strings.Join(only2, " "))
+
+	best := LevenshteinRatio(combined1, combined2)
+	if intersection != "" {
+		best = max(best, LevenshteinRatio(intersection, combined1), LevenshteinRatio(intersection, combined2))
+	}
+	return best
+}
 
-	// Allow 1 edit for short strings (<= 5 chars), 2 edits for longer ones.
-	if maxLen <= 5 {
-		return d <= 1
+func tokenSet(s string) map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, t := range strings.Fields(s) {
+		set[t] = struct{}{}
 	}
-	return d <= 2
+	return set
 }
 
 // Levenshtein calculates the Levenshtein distance between two strings.
 func Levenshtein(s1, s2 string) int {
 	r1, r2 := []rune(s1), []rune(s2)
+	return levenshtein(r1, r2, max(len(r1), len(r2)))
+}
+
+// BoundedLevenshtein calculates the Levenshtein distance between two strings when it is
+// at most bound, and returns bound+1 otherwise. It stops as soon as the distance is known
+// to exceed bound, so it is much cheaper than Levenshtein for dissimilar strings.
+func BoundedLevenshtein(s1, s2 string, bound int) int {
+	return levenshtein([]rune(s1), []rune(s2), bound)
+}
+
+// levenshtein calculates the distance between r1 and r2 a row at a time, keeping only the
+// previous row, and gives up with bound+1 once every cell of a row exceeds bound, as no
+// later row can then fall back within it.
+func levenshtein(r1, r2 []rune, bound int) int {
 	n, m := len(r1), len(r2)
-	if n == 0 {
-		return m
-	}
-	if m == 0 {
-		return n
+	if abs(n-m) > bound {
+		return bound + 1
 	}
-	matrix := make([][]int, n+1)
-	for i := range matrix {
-		matrix[i] = make([]int, m+1)
-		matrix[i][0] = i
+	if n == 0 || m == 0 {
+		return max(n, m)
 	}
-	for j := 0; j <= m; j++ {
-		matrix[0][j] = j
+
+	prev := make([]int, m+1)
+	cur := make([]int, m+1)
+	for j := range prev {
+		prev[j] = j
 	}
 	for i := 1; i <= n; i++ {
-		for j := 1; j <= m; j++ {
-			cost := 0
-			if r1[i-1] != r2[j-1] {
-				cost = 1
-			}
-			val1 := matrix[i-1][j] + 1
-			val2 := matrix[i][j-1] + 1
-			val3 := matrix[i-1][j-1] + cost
+		if levenshteinStep(prev, cur, r2, r1[i-1]) > bound {
+			return bound + 1
+		}
+		prev, cur = cur, prev
+	}
+	return min(prev[m], bound+1)
+}
 
-			minVal := val1
-			if val2 < minVal {
-				minVal = val2
-			}
-			if val3 < minVal {
-				minVal = val3
-			}
-			matrix[i][j] = minVal
+// levenshteinStep fills cur with the distances from each prefix of pattern to the text
+// whose distances are in prev extended by c, and returns the smallest of them.
+func levenshteinStep(prev, cur []int, pattern []rune, c rune) int {
+	cur[0] = prev[0] + 1
+	rowMin := cur[0]
+	for j := 1; j < len(cur); j++ {
+		cost := 1
+		if pattern[j-1] == c {
+			cost = 0
 		}
+		cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		rowMin = min(rowMin, cur[j])
+	}
+	return rowMin
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
 	}
-	return matrix[n][m]
+	return x
+}
+
+// appendFuzzyRunes appends the runes r becomes when prepared with PrepareForFuzzy, which
+// lower cases and replaces each rune independently of its neighbours.
+func appendFuzzyRunes(dst []rune, r rune) []rune {
+	switch r {
+	case '&':
+		return append(dst, 'a', 'n', 'd')
+	case '@':
+		return append(dst, 'a', 't')
+	}
+	return append(dst, unicode.ToLower(r))
+}
+
+func isAlphanumeric(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
 }
 
 // SplitFuzzy splits a string s by a separator sep, allowing for fuzzy matching.
-// It respects word boundaries for alphanumeric separators.
+// It respects word boundaries for alphanumeric separators, and allows the edits of
+// DefaultMatchSettings.
 func SplitFuzzy(s, sep string) []string {
+	return DefaultMatchSettings.SplitFuzzy(s, sep)
+}
+
+// SplitFuzzy splits a string s by a separator sep as the SplitFuzzy function does,
+// allowing ShortMaxEdits for separators of up to ShortLength characters and MaxEdits for
+// longer ones.
+// At each position the separator is matched against the text that follows by extending a
+// single row of edit distances one rune at a time, stopping once the row shows no longer
+// text can come within the allowed edits, so each position costs at most a fixed number of
+// steps for the separator and the scan is linear in the length of s.
+func (m MatchSettings) SplitFuzzy(s, sep string) []string {
 	if sep == "" {
 		return []string{s}
 	}
 
-	sepPrepared := PrepareForFuzzy(sep)
-	sepPreparedRunes := []rune(sepPrepared)
-	sepPreparedLen := len(sepPreparedRunes)
-
-	threshold := 2
-	if sepPreparedLen <= 5 {
-		threshold = 1
-	}
+	sepRunes := []rune(PrepareForFuzzy(sep))
+	sepLen := len(sepRunes)
+	threshold := m.maxEdits(sepLen)
+	checkLeftBoundary := isAlphanumeric(sepRunes[0])
+	checkRightBoundary := isAlphanumeric(sepRunes[sepLen-1])
 
+	// Prepare s once, keeping where the runes of each of its runes start
 	sRunes := []rune(s)
 	n := len(sRunes)
-
-	checkLeftBoundary := false
-	if sepPreparedLen > 0 && (unicode.IsLetter(sepPreparedRunes[0]) || unicode.IsDigit(sepPreparedRunes[0])) {
-		checkLeftBoundary = true
-	}
-	checkRightBoundary := false
-	if sepPreparedLen > 0 && (unicode.IsLetter(sepPreparedRunes[sepPreparedLen-1]) || unicode.IsDigit(sepPreparedRunes[sepPreparedLen-1])) {
-		checkRightBoundary = true
+	prepared := make([]rune, 0, n)
+	starts := make([]int, n+1)
+	for k, r := range sRunes {
+		starts[k] = len(prepared)
+		prepared = appendFuzzyRunes(prepared, r)
 	}
+	starts[n] = len(prepared)
+
+	prev := make([]int, sepLen+1)
+	cur := make([]int, sepLen+1)
 
 	var parts []string
 	lastIdx := 0
@@ -108,53 +277,38 @@ func SplitFuzzy(s, sep string) []string {
 		bestDist := threshold + 1
 		bestLen := -1
 
-		minLen := 1
-		maxLen := sepPreparedLen + threshold
-		if maxLen > n-i {
-			maxLen = n - i
+		// A match may only start at a word boundary, and a separator starting with a
+		// non-alphanumeric character (e.g. space) must match text that starts with one.
+		valid := true
+		if i > 0 && isAlphanumeric(sRunes[i-1]) && (checkLeftBoundary || isAlphanumeric(sRunes[i])) {
+			valid = false
+		}
+		if !checkLeftBoundary && isAlphanumeric(sRunes[i]) {
+			valid = false
 		}
 
-		for l := minLen; l <= maxLen; l++ {
-			candidateRaw := string(sRunes[i : i+l])
-			candidatePrepared := PrepareForFuzzy(candidateRaw)
-			dist := Levenshtein(candidatePrepared, sepPrepared)
-
-			if dist <= threshold {
-				valid := true
-				if checkLeftBoundary && i > 0 {
-					prev := sRunes[i-1]
-					if unicode.IsLetter(prev) || unicode.IsDigit(prev) {
-						valid = false
-					}
-				}
-				if valid && checkRightBoundary && i+l < n {
-					next := sRunes[i+l]
-					if unicode.IsLetter(next) || unicode.IsDigit(next) {
-						valid = false
-					}
-				}
-				// Prevent splitting strictly between two alphanumeric characters (mid-word)
-				if valid && i > 0 {
-					if (unicode.IsLetter(sRunes[i]) || unicode.IsDigit(sRunes[i])) &&
-						(unicode.IsLetter(sRunes[i-1]) || unicode.IsDigit(sRunes[i-1])) {
-						valid = false
-					}
-				}
-				// If separator starts with a non-alphanumeric character (e.g. space),
-				// the match should not start with an alphanumeric character.
-				if valid && sepPreparedLen > 0 && !(unicode.IsLetter(sepPreparedRunes[0]) || unicode.IsDigit(sepPreparedRunes[0])) {
-					firstRune := []rune(candidateRaw)[0]
-					if unicode.IsLetter(firstRune) || unicode.IsDigit(firstRune) {
-						valid = false
-					}
+		if valid {
+			maxLen := min(sepLen+threshold, n-i)
+			for j := range prev {
+				prev[j] = j
+			}
+			for l := 1; l <= maxLen; l++ {
+				rowMin := 0
+				for _, c := range prepared[starts[i+l-1]:starts[i+l]] {
+					rowMin = levenshteinStep(prev, cur, sepRunes, c)
+					prev, cur = cur, prev
 				}
 
-				if valid {
-					if dist < bestDist {
+				dist := prev[sepLen]
+				if dist <= threshold && dist < bestDist {
+					if !checkRightBoundary || i+l >= n || !isAlphanumeric(sRunes[i+l]) {
 						bestDist = dist
 						bestLen = l
 					}
 				}
+				if rowMin > threshold {
+					break
+				}
 			}
 		}
 
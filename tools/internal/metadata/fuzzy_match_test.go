@@ -1,8 +1,11 @@
 package metadata
 
 import (
+	"math/rand"
 	"reflect"
+	"strings"
 	"testing"
+	"unicode"
 )
 
 func TestSplitFuzzy(t *testing.T) {
@@ -101,3 +104,247 @@ func TestSplitFuzzy(t *testing.T) {
 		})
 	}
 }
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		name      string
+		s1, s2    string
+		wantMatch bool
+	}{
+		{name: "Identical", s1: "Union Chapel", s2: "Union Chapel", wantMatch: true},
+		{name: "Case and ampersand", s1: "Simon & Garfunkel", s2: "simon and garfunkel", wantMatch: true},
+		{name: "Single typo", s1: "Alice Coper", s2: "Alice Cooper", wantMatch: true},
+		{name: "Transposed letters", s1: "Union Chaple", s2: "Union Chapel", wantMatch: true},
+		{name: "Extra performer", s1: "Alice and Bob", s2: "Alice", wantMatch: false},
+		{name: "Different short names", s1: "Bob", s2: "Rob", wantMatch: false},
+		{name: "Shared first name", s1: "Bob Dylan", s2: "Bob Marley", wantMatch: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Similarity(tt.s1, tt.s2)
+			if got < 0 || got > 1 {
+				t.Fatalf("Similarity(%q, %q) = %f, want value in [0, 1]", tt.s1, tt.s2, got)
+			}
+			if (got >= MinSimilarity) != tt.wantMatch {
+				t.Errorf("Similarity(%q, %q) = %.3f, want match %v (threshold %.2f)", tt.s1, tt.s2, got, tt.wantMatch, MinSimilarity)
+			}
+		})
+	}
+}
+
+func TestSimilarity_Ordering(t *testing.T) {
+	// A closer spelling should always score higher than a more distant one.
+	closer := Similarity("Sophie Hunter", "Sophie Huntr")
+	further := Similarity("Sophie Hunter", "Sofie Huntr")
+	if closer <= further {
+		t.Errorf("Similarity ordering wrong: closer %.3f <= further %.3f", closer, further)
+	}
+}
+
+func TestJaroWinkler(t *testing.T) {
+	tests := []struct {
+		s1, s2 string
+		want   float64
+	}{
+		{"martha", "marhta", 0.961},
+		{"dwayne", "duane", 0.840},
+		{"dixon", "dicksonx", 0.813},
+		{"", "", 1},
+		{"abc", "", 0},
+	}
+
+	for _, tt := range tests {
+		got := JaroWinkler(tt.s1, tt.s2)
+		if diff := got - tt.want; diff > 0.001 || diff < -0.001 {
+			t.Errorf("JaroWinkler(%q, %q) = %.3f, want %.3f", tt.s1, tt.s2, got, tt.want)
+		}
+	}
+}
+
+func TestTokenSetRatio(t *testing.T) {
+	if got := TokenSetRatio("brixton academy", "academy brixton"); got != 1 {
+		t.Errorf("TokenSetRatio with reordered words = %f, want 1", got)
+	}
+	if got := TokenSetRatio("the staves", "staves"); got != 1 {
+		t.Errorf("TokenSetRatio with a subset of words = %f, want 1", got)
+	}
+	if got := TokenSetRatio("alpha", "omega"); got >= 0.5 {
+		t.Errorf("TokenSetRatio of unrelated words = %f, want < 0.5", got)
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		s1, s2 string
+		want   int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+		{"flaw", "lawn", 2},
+		{"café", "cafe", 1},
+		{"union chapel", "union chaple", 2},
+	}
+
+	for _, tt := range tests {
+		if got := Levenshtein(tt.s1, tt.s2); got != tt.want {
+			t.Errorf("Levenshtein(%q, %q) = %d, want %d", tt.s1, tt.s2, got, tt.want)
+		}
+		for bound := 0; bound <= tt.want+1; bound++ {
+			want := min(tt.want, bound+1)
+			if got := BoundedLevenshtein(tt.s1, tt.s2, bound); got != want {
+				t.Errorf("BoundedLevenshtein(%q, %q, %d) = %d, want %d", tt.s1, tt.s2, bound, got, want)
+			}
+		}
+	}
+}
+
+// levenshteinMatrix is the full matrix Levenshtein that the two-row version replaced,
+// kept to check and benchmark it against.
+func levenshteinMatrix(s1, s2 string) int {
+	r1, r2 := []rune(s1), []rune(s2)
+	n, m := len(r1), len(r2)
+	matrix := make([][]int, n+1)
+	for i := range matrix {
+		matrix[i] = make([]int, m+1)
+		matrix[i][0] = i
+	}
+	for j := 0; j <= m; j++ {
+		matrix[0][j] = j
+	}
+	for i := 1; i <= n; i++ {
+		for j := 1; j <= m; j++ {
+			cost := 1
+			if r1[i-1] == r2[j-1] {
+				cost = 0
+			}
+			matrix[i][j] = min(matrix[i-1][j]+1, matrix[i][j-1]+1, matrix[i-1][j-1]+cost)
+		}
+	}
+	return matrix[n][m]
+}
+
+// splitFuzzyQuadratic is the SplitFuzzy that prepared and compared every candidate
+// substring at every position, kept to check and benchmark the linear scan against.
+func splitFuzzyQuadratic(s, sep string, threshold int) []string {
+	sepPreparedRunes := []rune(PrepareForFuzzy(sep))
+	sepPreparedLen := len(sepPreparedRunes)
+	alnum := func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }
+
+	sRunes := []rune(s)
+	n := len(sRunes)
+	var parts []string
+	lastIdx := 0
+	i := 0
+	for i < n {
+		bestDist := threshold + 1
+		bestLen := -1
+		for l := 1; l <= min(sepPreparedLen+threshold, n-i); l++ {
+			candidateRaw := string(sRunes[i : i+l])
+			dist := levenshteinMatrix(PrepareForFuzzy(candidateRaw), string(sepPreparedRunes))
+			if dist > threshold {
+				continue
+			}
+			valid := true
+			if alnum(sepPreparedRunes[0]) && i > 0 && alnum(sRunes[i-1]) {
+				valid = false
+			}
+			if alnum(sepPreparedRunes[sepPreparedLen-1]) && i+l < n && alnum(sRunes[i+l]) {
+				valid = false
+			}
+			if i > 0 && alnum(sRunes[i]) && alnum(sRunes[i-1]) {
+				valid = false
+			}
+			if !alnum(sepPreparedRunes[0]) && alnum(sRunes[i]) {
+				valid = false
+			}
+			if valid && dist < bestDist {
+				bestDist = dist
+				bestLen = l
+			}
+		}
+		if bestLen != -1 {
+			parts = append(parts, string(sRunes[lastIdx:i]))
+			lastIdx = i + bestLen
+			i += bestLen
+		} else {
+			i++
+		}
+	}
+	return append(parts, string(sRunes[lastIdx:]))
+}
+
+// benchSeparators is a large list of stage role patterns, as MultiPerformerMatch tries
+// each of them against a %P entry.
+var benchSeparators = []string{
+	" and ", " & ", " with ", " w/ ", " vs ", " vs. ", " b2b ", " back to back with ",
+	" in conversation with ", " in conversation ", " feat. ", " featuring ", " ft. ",
+	" plus ", " + ", " presents ", " special guest ", " with special guests ",
+	" support from ", " supported by ", " joined by ", " performing with ", " x ",
+	" meets ", " versus ", " alongside ", " and friends ", " band ", " trio ", " quartet ",
+}
+
+// benchPerformers is a long %P entry, as written for a festival stage or an all-dayer.
+var benchPerformers = strings.Repeat("Sophie Hunter & Liv Austin wth Charlotte Campbell in converstation with Beth Keeping, ", 4)
+
+func TestSplitFuzzy_MatchesQuadratic(t *testing.T) {
+	words := []string{"Alice", "Bob", "and", "&", "wth", "with", "b2b", "in", "converstation", "conversation",
+		"feat.", "@", "Café", "AND", "Andy", "Sandy", "x", "+", ",", "Beth"}
+	rng := rand.New(rand.NewSource(1))
+	for k := 0; k < 1000; k++ {
+		var b strings.Builder
+		for w := rng.Intn(8); w >= 0; w-- {
+			b.WriteString(words[rng.Intn(len(words))])
+			if rng.Intn(4) > 0 {
+				b.WriteByte(' ')
+			}
+		}
+		text := b.String()
+		sep := benchSeparators[rng.Intn(len(benchSeparators))]
+		threshold := DefaultMatchSettings.maxEdits(len([]rune(PrepareForFuzzy(sep))))
+
+		got := SplitFuzzy(text, sep)
+		want := splitFuzzyQuadratic(text, sep, threshold)
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("SplitFuzzy(%q, %q) = %q, want %q", text, sep, got, want)
+		}
+	}
+}
+
+func BenchmarkLevenshtein(b *testing.B) {
+	s1, s2 := "the staves and sophie hunter", "sophie hunter and the staves"
+	b.Run("matrix", func(b *testing.B) {
+		for b.Loop() {
+			levenshteinMatrix(s1, s2)
+		}
+	})
+	b.Run("two-row", func(b *testing.B) {
+		for b.Loop() {
+			Levenshtein(s1, s2)
+		}
+	})
+	b.Run("bounded", func(b *testing.B) {
+		for b.Loop() {
+			BoundedLevenshtein(s1, s2, 2)
+		}
+	})
+}
+
+func BenchmarkSplitFuzzy(b *testing.B) {
+	b.Run("quadratic", func(b *testing.B) {
+		for b.Loop() {
+			for _, sep := range benchSeparators {
+				splitFuzzyQuadratic(benchPerformers, sep, DefaultMatchSettings.maxEdits(len([]rune(PrepareForFuzzy(sep)))))
+			}
+		}
+	})
+	b.Run("linear", func(b *testing.B) {
+		for b.Loop() {
+			for _, sep := range benchSeparators {
+				SplitFuzzy(benchPerformers, sep)
+			}
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package metadata
+
+import (
+	"fmt"
+	"strings"
+)
+
+// HistoryBoost configures how far the confidence of a candidate is raised when past events
+// connect it with the other entities matched for the same directory.
+type HistoryBoost struct {
+	Venue    int // Performer has played the matched venue before
+	Bill     int // Performer has shared a bill with another performer matched for the directory
+	Promoter int // Venue has hosted events by a promoter matched for the directory
+	Max      int // Limit on the total boost given to a single candidate
+}
+
+// DefaultHistoryBoost is the HistoryBoost used when no other is configured. The limit stays
+// below the smallest gap between the stages of DefaultMatchSettings, so history only decides
+// between candidates found at the same stage.
+var DefaultHistoryBoost = HistoryBoost{
+	Venue:    2,
+	Bill:     2,
+	Promoter: 2,
+	Max:      4,
+}
+
+// Validate checks the boosts are usable with settings, returning a *SettingError naming the
+// first that is not. No boost may reach across the smallest gap between the confidences of
+// adjacent stages, or history could lift a candidate over one found at a better stage.
+func (h HistoryBoost) Validate(settings MatchSettings) error {
+	boosts := []struct {
+		field string
+		value int
+	}{
+		{"history_venue", h.Venue},
+		{"history_bill", h.Bill},
+		{"history_promoter", h.Promoter},
+		{"history_max", h.Max},
+	}
+	gap := settings.StageGap()
+	for _, b := range boosts {
+		if b.value < 0 {
+			return &SettingError{Field: b.field, Message: "must not be negative"}
+		}
+		if b.value >= gap {
+			return &SettingError{Field: b.field, Message: fmt.Sprintf("must be below the smallest gap of %d between match stages", gap)}
+		}
+	}
+	return nil
+}
+
+// Enabled reports whether any boost is configured.
+func (h HistoryBoost) Enabled() bool {
+	return h.Max > 0 && (h.Venue > 0 || h.Bill > 0 || h.Promoter > 0)
+}
+
+// Boost records how much the confidence of a candidate was raised by past events, and why.
+type Boost struct {
+	Amount  int      `json:"amount"`
+	Reasons []string `json:"reasons"`
+}
+
+// String formats the boost for display on a single line.
+func (b *Boost) String() string {
+	if b == nil {
+		return "none"
+	}
+	return fmt.Sprintf("+%d (%s)", b.Amount, strings.Join(b.Reasons, "; "))
+}
+
+// AddBoost raises the confidence of c by amount for reason, keeping its total boost
+// within limit and its confidence below that of the next stage in settings, so a boost never
+// lifts it over a candidate found at a better stage. Exact matches are never boosted.
+func (c *Candidate) AddBoost(amount, limit int, settings MatchSettings, reason string) {
+	boosted := 0
+	if c.Boost != nil {
+		boosted = c.Boost.Amount
+	}
+	ceiling := settings.StageCeiling(c.Confidence - boosted)
+	amount = min(amount, limit-boosted, ceiling-1-c.Confidence)
+	if amount <= 0 {
+		return
+	}
+	if c.Boost == nil {
+		c.Boost = &Boost{}
+	}
+	c.Confidence += amount
+	c.Boost.Amount += amount
+	c.Boost.Reasons = append(c.Boost.Reasons, reason)
+}
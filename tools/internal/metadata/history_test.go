@@ -0,0 +1,110 @@
+package metadata
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCandidate_AddBoost(t *testing.T) {
+	tests := []struct {
+		name           string
+		modify         func(s *MatchSettings)
+		confidence     int
+		boosts         []int
+		limit          int
+		wantConfidence int
+		wantReasons    int
+	}{
+		{name: "Fuzzy name", confidence: FuzzyNameConfidence, boosts: []int{10}, limit: 20, wantConfidence: 60, wantReasons: 1},
+		{name: "Limited", confidence: FuzzyNameConfidence, boosts: []int{10, 10, 10}, limit: 20, wantConfidence: 70, wantReasons: 2},
+		{name: "Partly limited", confidence: FuzzyAliasConfidence, boosts: []int{15, 15}, limit: 20, wantConfidence: 45, wantReasons: 2},
+		{name: "Below exact", confidence: AliasConfidence, boosts: []int{30}, limit: 50, wantConfidence: 99, wantReasons: 1},
+		{name: "Exact is never boosted", confidence: ExactConfidence, boosts: []int{10}, limit: 20, wantConfidence: 100, wantReasons: 0},
+		{name: "Disabled", confidence: PhoneticConfidence, boosts: []int{10}, limit: 0, wantConfidence: 20, wantReasons: 0},
+		// Raised by 10 a phonetic match would rank above a fuzzy alias match at 25
+		{name: "Below the next stage", confidence: PhoneticConfidence, boosts: []int{10}, limit: 20, wantConfidence: 24, wantReasons: 1},
+		{name: "At the next stage", confidence: PhoneticConfidence, boosts: []int{4, 4}, limit: 20, wantConfidence: 24, wantReasons: 1},
+		{
+			name:   "Below a tuned stage",
+			modify: func(s *MatchSettings) { s.FuzzyNameConfidence = 30 },
+			// A fuzzy alias match is now only 5 below a fuzzy name match
+			confidence: FuzzyAliasConfidence, boosts: []int{10}, limit: 20, wantConfidence: 29, wantReasons: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := DefaultMatchSettings
+			if tt.modify != nil {
+				tt.modify(&settings)
+			}
+			c := Candidate{ID: 1, Match: "Alice", Confidence: tt.confidence}
+			for _, b := range tt.boosts {
+				c.AddBoost(b, tt.limit, settings, "reason")
+			}
+			if c.Confidence != tt.wantConfidence {
+				t.Errorf("Confidence = %d, want %d", c.Confidence, tt.wantConfidence)
+			}
+			reasons := 0
+			if c.Boost != nil {
+				reasons = len(c.Boost.Reasons)
+				if c.Boost.Amount != tt.wantConfidence-tt.confidence {
+					t.Errorf("Boost.Amount = %d, want %d", c.Boost.Amount, tt.wantConfidence-tt.confidence)
+				}
+			}
+			if reasons != tt.wantReasons {
+				t.Errorf("Boost = %v, want %d reasons", c.Boost, tt.wantReasons)
+			}
+		})
+	}
+}
+
+func TestHistoryBoost_Validate(t *testing.T) {
+	// The phonetic stage sits 5 below the fuzzy alias stage, the smallest gap by default
+	tests := []struct {
+		name      string
+		boost     HistoryBoost
+		modify    func(s *MatchSettings)
+		wantField string
+	}{
+		{name: "Default", boost: DefaultHistoryBoost},
+		{name: "Disabled", boost: HistoryBoost{}},
+		{name: "Negative venue", boost: HistoryBoost{Venue: -1, Bill: 2, Promoter: 2, Max: 4}, wantField: "history_venue"},
+		{name: "Negative bill", boost: HistoryBoost{Venue: 2, Bill: -1, Promoter: 2, Max: 4}, wantField: "history_bill"},
+		{name: "Negative promoter", boost: HistoryBoost{Venue: 2, Bill: 2, Promoter: -1, Max: 4}, wantField: "history_promoter"},
+		{name: "Negative limit", boost: HistoryBoost{Venue: 2, Bill: 2, Promoter: 2, Max: -1}, wantField: "history_max"},
+		{name: "Limit at the smallest gap", boost: HistoryBoost{Venue: 2, Bill: 2, Promoter: 2, Max: 5}, wantField: "history_max"},
+		{name: "Boost at the smallest gap", boost: HistoryBoost{Venue: 2, Bill: 5, Promoter: 2, Max: 4}, wantField: "history_bill"},
+		{
+			name:   "Limit within tuned gaps",
+			boost:  HistoryBoost{Venue: 10, Bill: 10, Promoter: 10, Max: 14},
+			modify: func(s *MatchSettings) { s.PhoneticConfidence = 10 },
+		},
+		{
+			name:      "Limit across a tuned gap",
+			boost:     DefaultHistoryBoost,
+			modify:    func(s *MatchSettings) { s.FuzzyAliasConfidence = 47 },
+			wantField: "history_max",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := DefaultMatchSettings
+			if tt.modify != nil {
+				tt.modify(&settings)
+			}
+			err := tt.boost.Validate(settings)
+			if tt.wantField == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			var settingErr *SettingError
+			if !errors.As(err, &settingErr) || settingErr.Field != tt.wantField {
+				t.Errorf("Validate() error = %v, want a SettingError for %s", err, tt.wantField)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package images
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/66james99/gig-calendar/internal/database"
+)
+
+// ErrEventExists is returned by WriteEvent for a directory whose venue already has an
+// event on its date, so scanning a location again does not duplicate its events.
+var ErrEventExists = errors.New("an event already exists at the venue on that date")
+
+// Lineup returns the event_performer rows recording the matched performers of the
+// directory for event, in running order and with the role each took. Performers that were
+// not matched, or that matched a performer already in the lineup, are left out.
+func (m MatchedResult) Lineup(event int32) []database.CreateEventPerformerParams {
+	var lineup []database.CreateEventPerformerParams
+	seen := make(map[int32]bool)
+	for _, group := range m.Performers {
+		for _, p := range group {
+			ep, ok := p.EventPerformer(event)
+			if !ok || seen[ep.Performer] {
+				continue
+			}
+			seen[ep.Performer] = true
+			lineup = append(lineup, ep)
+		}
+	}
+	return lineup
+}
+
+// writable reports why no event can be written for the directory, if none can.
+func (m MatchedResult) writable() error {
+	switch {
+	case !m.Consistent:
+		return errors.New("the directory is inconsistent or ambiguous")
+	case m.Year == 0 || m.Month == 0 || m.Day == 0:
+		return errors.New("the directory has no full date")
+	case m.Venue.Confidence == 0 || len(m.Venue.Candidates) == 0:
+		return errors.New("the venue was not matched")
+	}
+	return nil
+}
+
+// WriteEvent creates the event a matched directory records, of eventType, with its lineup
+// and its promoters, the first of which is marked primary. The directory must be
+// consistent, with a full date and a matched venue. Callers wanting the event written
+// all or nothing pass queries bound to a transaction.
+func WriteEvent(ctx context.Context, q *database.Queries, m MatchedResult, eventType int32) (database.Event, error) {
+	if err := m.writable(); err != nil {
+		return database.Event{}, err
+	}
+
+	venue := m.Venue.Candidates[0].ID
+	date := time.Date(m.Year, time.Month(m.Month), m.Day, 0, 0, 0, 0, time.UTC)
+	_, err := q.GetEventIDByVenueAndDate(ctx, database.GetEventIDByVenueAndDateParams{Venue: venue, Date: date})
+	if err == nil {
+		return database.Event{}, ErrEventExists
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return database.Event{}, err
+	}
+
+	event, err := q.CreateEvent(ctx, database.CreateEventParams{Venue: venue, EventType: eventType, Date: date})
+	if err != nil {
+		return database.Event{}, err
+	}
+	for _, ep := range m.Lineup(event.ID) {
+		if _, err := q.CreateEventPerformer(ctx, ep); err != nil {
+			return database.Event{}, err
+		}
+	}
+
+	seen := make(map[int32]bool)
+	for _, p := range m.Promoters {
+		if p.Confidence == 0 || len(p.Candidates) == 0 || !p.Candidates[0].Promoter || seen[p.Candidates[0].ID] {
+			continue
+		}
+		params := database.CreateEventPromoterParams{Event: event.ID, Promoter: p.Candidates[0].ID, Primary: len(seen) == 0}
+		if _, err := q.CreateEventPromoter(ctx, params); err != nil {
+			return database.Event{}, err
+		}
+		seen[p.Candidates[0].ID] = true
+	}
+	return event, nil
+}
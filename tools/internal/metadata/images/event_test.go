@@ -0,0 +1,88 @@
+package images
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/66james99/gig-calendar/internal/database"
+	"github.com/66james99/gig-calendar/internal/metadata"
+	"github.com/66james99/gig-calendar/internal/metadata/performers"
+	"github.com/66james99/gig-calendar/internal/metadata/promoters"
+	"github.com/66james99/gig-calendar/internal/metadata/venues"
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestWriteEvent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	exact := func(id int32, match string) []metadata.Candidate {
+		return []metadata.Candidate{{ID: id, Match: match, Confidence: metadata.ExactConfidence}}
+	}
+	performer := func(id int32, match string, role metadata.StageRoleKind) performers.PerformerMatchResult {
+		return performers.PerformerMatchResult{Name: match, Match: match, Confidence: metadata.ExactConfidence, Candidates: exact(id, match), Role: role}
+	}
+	m := MatchedResult{
+		Year: 2025, Month: 6, Day: 14,
+		Venue: venues.VenueMatchResult{Name: "Lexington", Match: "The Lexington", Confidence: metadata.ExactConfidence, Candidates: exact(10, "The Lexington")},
+		Performers: [][]performers.PerformerMatchResult{
+			{performer(1, "Alice", ""), performer(2, "Bob", metadata.StageRoleGuest)},
+			{performer(3, "Carol", "")},
+			{{Name: "Nobody", Confidence: 0}},
+		},
+		Promoters: []promoters.PromoterMatchResult{{
+			Name: "Eat Your Own Ears", Match: "Eat Your Own Ears", Confidence: metadata.ExactConfidence, Promoter: true,
+			Candidates: []promoters.PromoterCandidate{{Candidate: metadata.Candidate{ID: 20, Match: "Eat Your Own Ears"}, Promoter: true}},
+		}},
+		Consistent: true,
+	}
+	performers.AssignRunningOrder(m.Performers, false)
+	date := time.Date(2025, 6, 14, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`-- name: GetEventIDByVenueAndDate :one`).WithArgs(10, date).WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(`-- name: CreateEvent :one`).WithArgs(nil, 10, 4, date).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "uuid", "created", "updated", "name", "venue", "event_type", "date"}).
+			AddRow(7, "4c1d4d35-8a9a-4b7e-9d8c-2f3a1b0c9e11", date, date, nil, 10, 4, date))
+	performerRows := []string{"event", "performer", "created", "updated", "headliner", "slot", "role"}
+	// The headliner is named first, so is on last with Bob as their guest. The unmatched
+	// performer keeps the opening slot but is left out of the lineup.
+	mock.ExpectQuery(`-- name: CreateEventPerformer :one`).WithArgs(7, 1, true, 3, nil).
+		WillReturnRows(sqlmock.NewRows(performerRows).AddRow(7, 1, date, date, true, 3, nil))
+	mock.ExpectQuery(`-- name: CreateEventPerformer :one`).WithArgs(7, 2, false, 4, "guest").
+		WillReturnRows(sqlmock.NewRows(performerRows).AddRow(7, 2, date, date, false, 4, "guest"))
+	mock.ExpectQuery(`-- name: CreateEventPerformer :one`).WithArgs(7, 3, false, 2, nil).
+		WillReturnRows(sqlmock.NewRows(performerRows).AddRow(7, 3, date, date, false, 2, nil))
+	mock.ExpectQuery(`-- name: CreateEventPromoter :one`).WithArgs(7, 20, true).
+		WillReturnRows(sqlmock.NewRows([]string{"event", "promoter", "created", "updated", "primary"}).AddRow(7, 20, date, date, true))
+
+	event, err := WriteEvent(context.Background(), database.New(db), m, 4)
+	if err != nil {
+		t.Fatalf("WriteEvent() error = %v", err)
+	}
+	if event.ID != 7 {
+		t.Errorf("WriteEvent() ID = %d, want 7", event.ID)
+	}
+
+	// Writing the directory again finds the event already there
+	mock.ExpectQuery(`-- name: GetEventIDByVenueAndDate :one`).WithArgs(10, date).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	if _, err := WriteEvent(context.Background(), database.New(db), m, 4); !errors.Is(err, ErrEventExists) {
+		t.Errorf("WriteEvent() again error = %v, want ErrEventExists", err)
+	}
+
+	// An inconsistent directory is not written at all
+	m.Consistent = false
+	if _, err := WriteEvent(context.Background(), database.New(db), m, 4); err == nil {
+		t.Error("WriteEvent() of an inconsistent directory error = nil, want an error")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
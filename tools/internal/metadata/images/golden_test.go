@@ -0,0 +1,392 @@
+package images
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/66james99/gig-calendar/internal/dbcollection"
+	"github.com/66james99/gig-calendar/internal/metadata"
+	"github.com/66james99/gig-calendar/internal/metadata/performers"
+	"github.com/66james99/gig-calendar/internal/metadata/promoters"
+	"github.com/66james99/gig-calendar/internal/metadata/venues"
+)
+
+// goldenCorpusVersion is the version of testdata/golden_corpus.json this runner reads.
+// Bump both together when the format of the corpus changes.
+const goldenCorpusVersion = 1
+
+// goldenCorpus is a set of real directory names with the outcomes expected from parsing
+// and matching them against an in-memory set of entities.
+type goldenCorpus struct {
+	Version    int                        `json:"version"`
+	Entities   goldenEntities             `json:"entities"`
+	Splits     []goldenSplit              `json:"splits"`
+	Cases      []goldenCase               `json:"cases"`
+	Thresholds map[string]goldenThreshold `json:"thresholds"` // Minimum metrics, keyed by entity type
+}
+
+type goldenEntities struct {
+	Venues     []goldenEntity `json:"venues"`
+	Performers []goldenEntity `json:"performers"`
+	Promoters  []goldenEntity `json:"promoters"`
+	Festivals  []goldenEntity `json:"festivals"`
+	StageRoles []struct {
+		Pattern string                 `json:"pattern"`
+		Kind    metadata.StageRoleKind `json:"kind"`
+	} `json:"stage_roles"`
+}
+
+type goldenEntity struct {
+	ID      int32    `json:"id"`
+	Name    string   `json:"name"`
+	City    string   `json:"city"`
+	Aliases []string `json:"aliases"`
+}
+
+// goldenSplit is the parts SplitFuzzy is expected to find in Text when splitting on Sep.
+type goldenSplit struct {
+	Text string   `json:"text"`
+	Sep  string   `json:"sep"`
+	Want []string `json:"want"`
+}
+
+type goldenCase struct {
+	Pattern   string `json:"pattern"`
+	Directory string `json:"directory"`
+	City      string `json:"city"` // Used as ImagesConfig.DefaultCity
+	Expect    struct {
+		Year       int      `json:"year"`
+		Month      int      `json:"month"`
+		Day        int      `json:"day"`
+		Venue      string   `json:"venue"`      // Empty when no venue should be matched
+		VenueCity  string   `json:"venue_city"` // Chooses between venues with the same name
+		Performers []string `json:"performers"`
+		Promoters  []string `json:"promoters"`
+		Festivals  []string `json:"festivals"`
+	} `json:"expect"`
+}
+
+type goldenThreshold struct {
+	Precision float64 `json:"precision"`
+	Recall    float64 `json:"recall"`
+}
+
+// goldenLoaded is reported as the last modification of every collection built from the
+// corpus, so each is loaded once and never refreshed.
+var goldenLoaded = time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+func goldenLastModified(context.Context) (time.Time, error) {
+	return goldenLoaded, nil
+}
+
+// goldenEntityTypes lists the entity types metrics are reported for, in report order.
+var goldenEntityTypes = []string{"split", "venue", "performer", "promoter", "festival"}
+
+// goldenCounts counts the true positives, false positives and false negatives of one
+// entity type across the corpus.
+type goldenCounts struct {
+	tp, fp, fn int
+}
+
+func (c goldenCounts) precision() float64 {
+	if c.tp+c.fp == 0 {
+		return 1
+	}
+	return float64(c.tp) / float64(c.tp+c.fp)
+}
+
+func (c goldenCounts) recall() float64 {
+	if c.tp+c.fn == 0 {
+		return 1
+	}
+	return float64(c.tp) / float64(c.tp+c.fn)
+}
+
+// add scores the predicted against the expected keys, counting each key as often as it
+// appears, and returns a description of any difference.
+func (c *goldenCounts) add(predicted, expected []string) string {
+	remaining := slices.Clone(expected)
+	var extra []string
+	for _, p := range predicted {
+		if i := slices.Index(remaining, p); i >= 0 {
+			remaining = slices.Delete(remaining, i, i+1)
+			c.tp++
+		} else {
+			extra = append(extra, p)
+		}
+	}
+	c.fp += len(extra)
+	c.fn += len(remaining)
+	if len(extra) == 0 && len(remaining) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("got %q, missing %q", extra, remaining)
+}
+
+// goldenRun holds the matching configuration built from the entities of a corpus.
+type goldenRun struct {
+	corpus *goldenCorpus
+	cfg    metadata.ImagesConfig
+	ids    map[string]map[string][]int32 // Entity IDs by type and name
+	cities map[int32]string              // Venue cities by ID
+}
+
+func loadGoldenCorpus(tb testing.TB) *goldenCorpus {
+	tb.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "golden_corpus.json"))
+	if err != nil {
+		tb.Fatalf("failed to read golden corpus: %v", err)
+	}
+	var corpus goldenCorpus
+	if err := json.Unmarshal(data, &corpus); err != nil {
+		tb.Fatalf("failed to parse golden corpus: %v", err)
+	}
+	if corpus.Version != goldenCorpusVersion {
+		tb.Fatalf("golden corpus version = %d, runner reads version %d", corpus.Version, goldenCorpusVersion)
+	}
+	return &corpus
+}
+
+// newGoldenIndex builds a MatchIndex holding entities and their aliases. Venue entries
+// carry their city, and phonetic indexes hold phonetic keys as the performer index does.
+func newGoldenIndex(tb testing.TB, entities []goldenEntity, phonetic bool) *metadata.MatchIndex {
+	tb.Helper()
+	idx, err := metadata.NewMatchIndex(context.Background(), goldenLastModified,
+		func(context.Context) (metadata.IndexData, error) {
+			d := make(metadata.IndexData)
+			aliasID := int32(0)
+			for _, e := range entities {
+				if phonetic {
+					d.AddPhoneticName(e.ID, e.Name, "")
+				} else {
+					d.Add(metadata.IndexEntry{ID: e.ID, Name: e.Name, City: e.City})
+				}
+				for _, a := range e.Aliases {
+					aliasID++
+					if phonetic {
+						d.AddPhoneticAlias(e.ID, e.Name, aliasID, a, "")
+					} else {
+						d.Add(metadata.IndexEntry{ID: e.ID, Name: e.Name, AliasID: aliasID, Alias: a, City: e.City})
+					}
+				}
+			}
+			return d, nil
+		},
+	)
+	if err != nil {
+		tb.Fatalf("failed to create MatchIndex: %v", err)
+	}
+	if phonetic {
+		idx.EnablePhonetic()
+	}
+	return idx
+}
+
+func newGoldenRun(tb testing.TB, corpus *goldenCorpus) *goldenRun {
+	tb.Helper()
+	var patterns []string
+	kinds := make(map[string]metadata.StageRoleKind)
+	for _, r := range corpus.Entities.StageRoles {
+		patterns = append(patterns, r.Pattern)
+		kinds[r.Pattern] = r.Kind
+	}
+	patternsArray, err := dbcollection.NewDBArray(context.Background(), goldenLastModified,
+		func(context.Context) ([]string, error) { return patterns, nil })
+	if err != nil {
+		tb.Fatalf("failed to create DBArray: %v", err)
+	}
+	stageRoles, err := dbcollection.NewDBMap(context.Background(), goldenLastModified,
+		func(context.Context) (map[string]metadata.StageRoleKind, error) { return kinds, nil })
+	if err != nil {
+		tb.Fatalf("failed to create DBMap: %v", err)
+	}
+
+	run := &goldenRun{
+		corpus: corpus,
+		cfg: metadata.ImagesConfig{
+			Patterns:   patternsArray,
+			StageRoles: stageRoles,
+			Indexes: &metadata.MatchIndexes{
+				Venues:     newGoldenIndex(tb, corpus.Entities.Venues, false),
+				Performers: newGoldenIndex(tb, corpus.Entities.Performers, true),
+				Promoters:  newGoldenIndex(tb, corpus.Entities.Promoters, false),
+				Festivals:  newGoldenIndex(tb, corpus.Entities.Festivals, false),
+			},
+		},
+		ids:    make(map[string]map[string][]int32),
+		cities: make(map[int32]string),
+	}
+	for typ, entities := range map[string][]goldenEntity{
+		"venue":     corpus.Entities.Venues,
+		"performer": corpus.Entities.Performers,
+		"promoter":  corpus.Entities.Promoters,
+		"festival":  corpus.Entities.Festivals,
+	} {
+		run.ids[typ] = make(map[string][]int32)
+		for _, e := range entities {
+			run.ids[typ][e.Name] = append(run.ids[typ][e.Name], e.ID)
+		}
+	}
+	for _, v := range corpus.Entities.Venues {
+		run.cities[v.ID] = v.City
+	}
+	return run
+}
+
+// expected resolves the names expected for a case to the keys they are scored by.
+func (r *goldenRun) expected(tb testing.TB, typ string, names []string, city string) []string {
+	tb.Helper()
+	var keys []string
+	for _, name := range names {
+		ids := r.ids[typ][name]
+		if city != "" {
+			ids = slices.DeleteFunc(slices.Clone(ids), func(id int32) bool { return r.cities[id] != city })
+		}
+		if len(ids) != 1 {
+			tb.Fatalf("golden corpus names %d %ss called %q in %q", len(ids), typ, name, city)
+		}
+		keys = append(keys, fmt.Sprint(ids[0]))
+	}
+	return keys
+}
+
+// goldenOutcome holds the keys predicted and expected for each entity type of a case.
+type goldenOutcome struct {
+	predicted map[string][]string
+	expected  map[string][]string
+}
+
+// match parses and matches the directory of a case as ExecuteScan does, keeping only
+// unambiguous matches as predictions.
+func (r *goldenRun) match(tb testing.TB, gc goldenCase) (goldenOutcome, error) {
+	tb.Helper()
+	ctx := context.Background()
+	out := goldenOutcome{predicted: make(map[string][]string), expected: make(map[string][]string)}
+
+	data, err := ParseLocation(gc.Pattern, gc.Directory)
+	if err != nil {
+		return out, err
+	}
+	if data.Year != gc.Expect.Year || data.Month != gc.Expect.Month || data.Day != gc.Expect.Day {
+		return out, fmt.Errorf("parsed date %d-%d-%d, want %d-%d-%d",
+			data.Year, data.Month, data.Day, gc.Expect.Year, gc.Expect.Month, gc.Expect.Day)
+	}
+
+	cfg := r.cfg
+	cfg.DefaultCity = gc.City
+	if data.Venue != "" {
+		city := data.City
+		if city == "" {
+			city = cfg.DefaultCity
+		}
+		match, err := venues.VenueMatch(ctx, cfg.Indexes.Venues, data.Venue, city, cfg.Candidates)
+		if err != nil {
+			return out, err
+		}
+		if match.Confidence > 0 && !match.Ambiguous {
+			out.predicted["venue"] = append(out.predicted["venue"], fmt.Sprint(match.Candidates[0].ID))
+		}
+	}
+	for _, p := range data.Performers {
+		matches, err := performers.MultiPerformerMatch(ctx, cfg, p)
+		if err != nil {
+			return out, err
+		}
+		for _, match := range matches {
+			if match.Confidence > 0 && !match.Ambiguous {
+				out.predicted["performer"] = append(out.predicted["performer"], fmt.Sprint(match.Candidates[0].ID))
+			}
+		}
+	}
+	for _, p := range data.Promoters {
+		match, err := promoters.PromoterMatch(ctx, cfg.Indexes.Promoters, cfg.Indexes.Festivals, p, cfg.Candidates)
+		if err != nil {
+			return out, err
+		}
+		if match.Confidence > 0 && !match.Ambiguous {
+			typ := "promoter"
+			if match.Festival {
+				typ = "festival"
+			}
+			out.predicted[typ] = append(out.predicted[typ], fmt.Sprint(match.Candidates[0].ID))
+		}
+	}
+
+	if gc.Expect.Venue != "" {
+		out.expected["venue"] = r.expected(tb, "venue", []string{gc.Expect.Venue}, gc.Expect.VenueCity)
+	}
+	out.expected["performer"] = r.expected(tb, "performer", gc.Expect.Performers, "")
+	out.expected["promoter"] = r.expected(tb, "promoter", gc.Expect.Promoters, "")
+	out.expected["festival"] = r.expected(tb, "festival", gc.Expect.Festivals, "")
+	return out, nil
+}
+
+func TestGoldenCorpus(t *testing.T) {
+	corpus := loadGoldenCorpus(t)
+	run := newGoldenRun(t, corpus)
+	counts := make(map[string]*goldenCounts)
+	for _, typ := range goldenEntityTypes {
+		counts[typ] = &goldenCounts{}
+	}
+
+	for _, s := range corpus.Splits {
+		var got []string
+		for _, part := range metadata.SplitFuzzy(s.Text, s.Sep) {
+			got = append(got, strings.TrimSpace(part))
+		}
+		if diff := counts["split"].add(got, s.Want); diff != "" {
+			t.Logf("SplitFuzzy(%q, %q): %s", s.Text, s.Sep, diff)
+		}
+	}
+
+	for _, gc := range corpus.Cases {
+		out, err := run.match(t, gc)
+		if err != nil {
+			t.Errorf("%s: %v", gc.Directory, err)
+			continue
+		}
+		for _, typ := range goldenEntityTypes[1:] {
+			if diff := counts[typ].add(out.predicted[typ], out.expected[typ]); diff != "" {
+				t.Logf("%s: %s %s", gc.Directory, typ, diff)
+			}
+		}
+	}
+
+	t.Logf("golden corpus v%d: %d splits, %d directories", corpus.Version, len(corpus.Splits), len(corpus.Cases))
+	for _, typ := range goldenEntityTypes {
+		c := counts[typ]
+		t.Logf("%-9s precision %.3f recall %.3f (tp %d, fp %d, fn %d)", typ, c.precision(), c.recall(), c.tp, c.fp, c.fn)
+		want := corpus.Thresholds[typ]
+		if c.precision() < want.Precision {
+			t.Errorf("%s precision = %.3f, want at least %.3f", typ, c.precision(), want.Precision)
+		}
+		if c.recall() < want.Recall {
+			t.Errorf("%s recall = %.3f, want at least %.3f", typ, c.recall(), want.Recall)
+		}
+	}
+}
+
+func BenchmarkGoldenCorpus(b *testing.B) {
+	corpus := loadGoldenCorpus(b)
+	run := newGoldenRun(b, corpus)
+
+	b.ResetTimer()
+	for b.Loop() {
+		for _, s := range corpus.Splits {
+			metadata.SplitFuzzy(s.Text, s.Sep)
+		}
+		for _, gc := range corpus.Cases {
+			if _, err := run.match(b, gc); err != nil {
+				b.Fatalf("%s: %v", gc.Directory, err)
+			}
+		}
+	}
+	b.ReportMetric(float64(len(corpus.Cases)), "dirs/op")
+}
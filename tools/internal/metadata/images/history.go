@@ -0,0 +1,152 @@
+package images
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/66james99/gig-calendar/internal/database"
+	"github.com/66james99/gig-calendar/internal/metadata"
+	"github.com/66james99/gig-calendar/internal/metadata/performers"
+)
+
+// applyHistory raises the confidence of the venue and performer candidates of a directory
+// that past events connect with the other entities matched for it, then reranks them.
+// No candidate is raised to the next stage of the settings of the index that found it.
+// The venue is boosted first so performers can be boosted for having played it.
+func applyHistory(ctx context.Context, q *database.Queries, boost metadata.HistoryBoost, indexes *metadata.MatchIndexes, m *MatchedResult) error {
+	if err := boostVenue(ctx, q, boost, indexes.Venues.Settings(), m); err != nil {
+		return err
+	}
+	return boostPerformers(ctx, q, boost, indexes.Performers.Settings(), m)
+}
+
+// boostVenue boosts venue candidates that have hosted events by the matched promoters.
+func boostVenue(ctx context.Context, q *database.Queries, boost metadata.HistoryBoost, settings metadata.MatchSettings, m *MatchedResult) error {
+	if boost.Promoter <= 0 || len(m.Venue.Candidates) == 0 || m.Venue.Confidence == metadata.ExactConfidence {
+		return nil
+	}
+
+	promoterNames := make(map[int32]string)
+	for _, p := range m.Promoters {
+		if p.Confidence > 0 && !p.Ambiguous && len(p.Candidates) > 0 && p.Candidates[0].Promoter {
+			promoterNames[p.Candidates[0].ID] = p.Candidates[0].Match
+		}
+	}
+	if len(promoterNames) == 0 {
+		return nil
+	}
+
+	venueIDs := make([]int32, len(m.Venue.Candidates))
+	for i, c := range m.Venue.Candidates {
+		venueIDs[i] = c.ID
+	}
+	rows, err := q.CountVenueEventsWithPromoters(ctx, database.CountVenueEventsWithPromotersParams{
+		Venues:    venueIDs,
+		Promoters: slices.Collect(maps.Keys(promoterNames)),
+	})
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	names := slices.Sorted(maps.Values(promoterNames))
+	for _, r := range rows {
+		for i := range m.Venue.Candidates {
+			if m.Venue.Candidates[i].ID == r.Venue {
+				m.Venue.Candidates[i].AddBoost(boost.Promoter, boost.Max, settings,
+					fmt.Sprintf("hosted %d events by %s", r.Events, strings.Join(names, ", ")))
+			}
+		}
+	}
+	m.Venue.Rerank()
+	return nil
+}
+
+// boostPerformers boosts performer candidates that have played the matched venue, or have
+// shared a bill with the other performers matched for the directory.
+func boostPerformers(ctx context.Context, q *database.Queries, boost metadata.HistoryBoost, settings metadata.MatchSettings, m *MatchedResult) error {
+	var all, unsure []*performers.PerformerMatchResult
+	for i := range m.Performers {
+		for j := range m.Performers[i] {
+			p := &m.Performers[i][j]
+			all = append(all, p)
+			if len(p.Candidates) > 0 && p.Confidence < metadata.ExactConfidence {
+				unsure = append(unsure, p)
+			}
+		}
+	}
+	if len(unsure) == 0 {
+		return nil
+	}
+
+	var candidateIDs []int32
+	for _, p := range unsure {
+		for _, c := range p.Candidates {
+			candidateIDs = append(candidateIDs, c.ID)
+		}
+	}
+
+	played := make(map[int32]int32)
+	if boost.Venue > 0 && m.Venue.Confidence > 0 && !m.Venue.Ambiguous && len(m.Venue.Candidates) > 0 {
+		rows, err := q.CountPerformerEventsAtVenue(ctx, database.CountPerformerEventsAtVenueParams{
+			Venue:      m.Venue.Candidates[0].ID,
+			Performers: candidateIDs,
+		})
+		if err != nil {
+			return err
+		}
+		for _, r := range rows {
+			played[r.Performer] = r.Events
+		}
+	}
+
+	// Names are taken now, as reranking may change the match of a performer
+	matchedBy := make(map[int32]*performers.PerformerMatchResult)
+	matchedNames := make(map[int32]string)
+	for _, p := range all {
+		if p.Confidence > 0 && !p.Ambiguous && len(p.Candidates) > 0 {
+			matchedBy[p.Candidates[0].ID] = p
+			matchedNames[p.Candidates[0].ID] = p.Candidates[0].Match
+		}
+	}
+	shared := make(map[int32][]int32)
+	if boost.Bill > 0 && len(matchedBy) > 0 {
+		rows, err := q.ListSharedBills(ctx, database.ListSharedBillsParams{
+			Performers: candidateIDs,
+			Others:     slices.Collect(maps.Keys(matchedBy)),
+		})
+		if err != nil {
+			return err
+		}
+		for _, r := range rows {
+			shared[r.Performer] = append(shared[r.Performer], r.Other)
+		}
+	}
+
+	for _, p := range unsure {
+		for i := range p.Candidates {
+			c := &p.Candidates[i]
+			if n := played[c.ID]; n > 0 {
+				c.AddBoost(boost.Venue, boost.Max, settings, fmt.Sprintf("played %s %d times", m.Venue.Match, n))
+			}
+			var others []string
+			for _, id := range shared[c.ID] {
+				// Only performers matched for other names in the directory count
+				if matchedBy[id] != p {
+					others = append(others, matchedNames[id])
+				}
+			}
+			if len(others) > 0 {
+				slices.Sort(others)
+				c.AddBoost(boost.Bill, boost.Max, settings, "shared a bill with "+strings.Join(others, ", "))
+			}
+		}
+		p.Rerank()
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package images
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/66james99/gig-calendar/internal/database"
+	"github.com/66james99/gig-calendar/internal/metadata"
+	"github.com/66james99/gig-calendar/internal/metadata/performers"
+	"github.com/66james99/gig-calendar/internal/metadata/promoters"
+	"github.com/66james99/gig-calendar/internal/metadata/venues"
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestApplyHistory(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	fuzzy := func(id int32, match string) metadata.Candidate {
+		return metadata.Candidate{ID: id, Match: match, Confidence: metadata.FuzzyNameConfidence, Score: 0.8}
+	}
+	venueCandidates := []metadata.Candidate{fuzzy(10, "The Lexington"), fuzzy(11, "The Lexington Arms")}
+	performerCandidates := []metadata.Candidate{fuzzy(2, "Sophie Hunte"), fuzzy(3, "Sophie Hunter")}
+	m := MatchedResult{
+		Venue: venues.VenueMatchResult{
+			Name: "Lexington", Match: "The Lexington", Confidence: metadata.FuzzyNameConfidence,
+			Ambiguous: true, Contenders: venueCandidates, Candidates: venueCandidates,
+		},
+		Performers: [][]performers.PerformerMatchResult{
+			{{Name: "Alice", Match: "Alice", Confidence: metadata.ExactConfidence, Candidates: []metadata.Candidate{{ID: 1, Match: "Alice", Confidence: metadata.ExactConfidence}}}},
+			{{Name: "Sophie Huntr", Match: "Sophie Hunte", Confidence: metadata.FuzzyNameConfidence,
+				Ambiguous: true, Contenders: performerCandidates, Candidates: performerCandidates, Slot: 1}},
+		},
+		Promoters: []promoters.PromoterMatchResult{{
+			Name: "Eat Your Own Ears", Match: "Eat Your Own Ears", Confidence: metadata.ExactConfidence, Promoter: true,
+			Candidates: []promoters.PromoterCandidate{{Candidate: metadata.Candidate{ID: 20, Match: "Eat Your Own Ears"}, Promoter: true}},
+		}},
+	}
+
+	mock.ExpectQuery(`-- name: CountVenueEventsWithPromoters :many`).
+		WillReturnRows(sqlmock.NewRows([]string{"venue", "events"}).AddRow(11, 4))
+	mock.ExpectQuery(`-- name: CountPerformerEventsAtVenue :many`).
+		WillReturnRows(sqlmock.NewRows([]string{"performer", "events"}))
+	mock.ExpectQuery(`-- name: ListSharedBills :many`).
+		WillReturnRows(sqlmock.NewRows([]string{"performer", "other", "events"}).AddRow(3, 1, 2))
+
+	idx, err := metadata.NewMatchIndex(context.Background(),
+		func(context.Context) (time.Time, error) { return time.Now(), nil },
+		func(context.Context) (metadata.IndexData, error) { return metadata.IndexData{}, nil })
+	if err != nil {
+		t.Fatalf("failed to create MatchIndex: %v", err)
+	}
+	indexes := &metadata.MatchIndexes{Venues: idx, Performers: idx}
+	if err := applyHistory(context.Background(), database.New(db), metadata.DefaultHistoryBoost, indexes, &m); err != nil {
+		t.Fatalf("applyHistory() error = %v", err)
+	}
+
+	if m.Venue.Match != "The Lexington Arms" || m.Venue.Ambiguous || m.Venue.Confidence != 52 {
+		t.Errorf("Venue = %s (%d, ambiguous %v), want The Lexington Arms (52) unambiguous", m.Venue.Match, m.Venue.Confidence, m.Venue.Ambiguous)
+	}
+	if m.Venue.Boost == nil || m.Venue.Boost.Reasons[0] != "hosted 4 events by Eat Your Own Ears" {
+		t.Errorf("Venue.Boost = %v", m.Venue.Boost)
+	}
+
+	p := m.Performers[1][0]
+	if p.Match != "Sophie Hunter" || p.Ambiguous || p.Confidence != 52 {
+		t.Errorf("Performer = %s (%d, ambiguous %v), want Sophie Hunter (52) unambiguous", p.Match, p.Confidence, p.Ambiguous)
+	}
+	if p.Boost == nil || p.Boost.Reasons[0] != "shared a bill with Alice" {
+		t.Errorf("Performer.Boost = %v", p.Boost)
+	}
+	if p.Slot != 1 {
+		t.Errorf("Performer.Slot = %d, want the slot to be kept", p.Slot)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
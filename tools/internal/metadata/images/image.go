@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/66james99/gig-calendar/internal/database"
 	"github.com/66james99/gig-calendar/internal/metadata"
 	"github.com/66james99/gig-calendar/internal/metadata/performers"
 	"github.com/66james99/gig-calendar/internal/metadata/promoters"
@@ -53,14 +54,108 @@ type ScanResult struct {
 
 // MatchedResult holds the outcome of matching Performers, Venue and Promoter against those existing in the DB
 type MatchedResult struct {
-	Directory  string                              `json:"directory"`
-	Year       int                                 `json:"year,omitempty"`
-	Month      int                                 `json:"month,omitempty"`
-	Day        int                                 `json:"day,omitempty"`
-	Performers [][]performers.PerformerMatchResult `json:"performers,omitempty"`
-	Venue      venues.VenueMatchResult             `json:"venue,omitempty"`
-	Promoters  []promoters.PromoterMatchResult     `json:"promoters,omitempty"`
-	Consistent bool                                `json:"consistent"`
+	Directory   string                              `json:"directory"`
+	Year        int                                 `json:"year,omitempty"`
+	Month       int                                 `json:"month,omitempty"`
+	Day         int                                 `json:"day,omitempty"`
+	Performers  [][]performers.PerformerMatchResult `json:"performers,omitempty"`
+	Venue       venues.VenueMatchResult             `json:"venue,omitempty"`
+	Promoters   []promoters.PromoterMatchResult     `json:"promoters,omitempty"`
+	Ambiguities []Ambiguity                         `json:"ambiguities,omitempty"`
+	Consistent  bool                                `json:"consistent"`
+}
+
+// Ambiguity lists the entities that a raw venue, performer or promoter name matched equally well.
+type Ambiguity struct {
+	Type       string   `json:"type"`
+	Name       string   `json:"name"`
+	Contenders []string `json:"contenders"`
+}
+
+// ambiguities collects the ambiguous venue, performer and promoter matches of a directory.
+func (m MatchedResult) ambiguities() []Ambiguity {
+	var result []Ambiguity
+	add := func(typ, name string, contenders []metadata.Candidate) {
+		a := Ambiguity{Type: typ, Name: name}
+		for _, c := range contenders {
+			a.Contenders = append(a.Contenders, c.Match)
+		}
+		result = append(result, a)
+	}
+
+	if m.Venue.Ambiguous {
+		add("venue", m.Venue.Name, m.Venue.Contenders)
+	}
+	for _, group := range m.Performers {
+		for _, p := range group {
+			if p.Ambiguous {
+				add("performer", p.Name, p.Contenders)
+			}
+		}
+	}
+	for _, p := range m.Promoters {
+		if p.Ambiguous {
+			contenders := make([]metadata.Candidate, len(p.Contenders))
+			for i, c := range p.Contenders {
+				contenders[i] = c.Candidate
+			}
+			add("promoter", p.Name, contenders)
+		}
+	}
+	return result
+}
+
+// inferFestival reports whether a festival should be inferred for the directory: it has a
+// full date and an unambiguous venue match, but none of its promoters matched a festival.
+func (m MatchedResult) inferFestival() bool {
+	if m.Year == 0 || m.Month == 0 || m.Day == 0 {
+		return false
+	}
+	if m.Venue.Confidence == 0 || m.Venue.Ambiguous || len(m.Venue.Candidates) == 0 {
+		return false
+	}
+	for _, p := range m.Promoters {
+		if p.Festival {
+			return false
+		}
+	}
+	return true
+}
+
+// NewMatchIndexes loads the venue, performer, promoter and festival indexes used by
+// ExecuteScan, each matching with the settings held for its type of entity.
+func NewMatchIndexes(ctx context.Context, q *database.Queries) (*metadata.MatchIndexes, error) {
+	venueIdx, err := venues.NewVenueIndex(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("error loading venue index: %w", err)
+	}
+	performerIdx, err := performers.NewPerformerIndex(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("error loading performer index: %w", err)
+	}
+	promoterIdx, err := promoters.NewPromoterIndex(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("error loading promoter index: %w", err)
+	}
+	festivalIdx, err := promoters.NewFestivalIndex(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("error loading festival index: %w", err)
+	}
+	settings, err := metadata.NewMatchSettingsMap(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("error loading match settings: %w", err)
+	}
+	venueIdx.SetSettings(settings, metadata.EntityVenue)
+	performerIdx.SetSettings(settings, metadata.EntityPerformer)
+	promoterIdx.SetSettings(settings, metadata.EntityPromoter)
+	festivalIdx.SetSettings(settings, metadata.EntityPromoter)
+	return &metadata.MatchIndexes{
+		Venues:     venueIdx,
+		Performers: performerIdx,
+		Promoters:  promoterIdx,
+		Festivals:  festivalIdx,
+		Settings:   settings,
+	}, nil
 }
 
 // ExecuteScan performs the directory scanning and parsing based on the provided config.
@@ -87,6 +182,22 @@ func ExecuteScan(cfg metadata.ImagesConfig) (ScanResult, error) {
 	result.Directories = dirs
 	result.IgnoredCount = ignoredCount
 
+	if cfg.Indexes != nil {
+		// Pick up any changes made to the entity and alias tables since the indexes were loaded.
+		if err := cfg.Indexes.Refresh(context.Background()); err != nil {
+			return result, fmt.Errorf("error refreshing match indexes: %w", err)
+		}
+		// The settings may have been tuned since the boosts were checked
+		if cfg.History.Enabled() {
+			if err := cfg.History.Validate(cfg.Indexes.Venues.Settings()); err != nil {
+				return result, fmt.Errorf("history boost does not fit the venue match settings: %w", err)
+			}
+			if err := cfg.History.Validate(cfg.Indexes.Performers.Settings()); err != nil {
+				return result, fmt.Errorf("history boost does not fit the performer match settings: %w", err)
+			}
+		}
+	}
+
 	for _, dir := range dirs {
 		if cfg.Pattern != "" {
 			data, err := ParseLocation(cfg.Pattern, dir)
@@ -110,9 +221,13 @@ func ExecuteScan(cfg metadata.ImagesConfig) (ScanResult, error) {
 					Consistent: data.Consistent,
 				}
 
-				if cfg.Queries != nil {
+				if cfg.Indexes != nil {
 					if data.Venue != "" {
-						match, err := venues.VenueMatch(context.Background(), cfg.Queries, data.Venue)
+						city := data.City
+						if city == "" {
+							city = cfg.DefaultCity
+						}
+						match, err := venues.VenueMatch(context.Background(), cfg.Indexes.Venues, data.Venue, city, cfg.Candidates)
 						if err == nil {
 							matched.Venue = match
 						} else if cfg.Debug {
@@ -128,10 +243,11 @@ func ExecuteScan(cfg metadata.ImagesConfig) (ScanResult, error) {
 								result.ParseErrors = append(result.ParseErrors, fmt.Sprintf("Error matching performer '%s': %v", p, err))
 							}
 						}
+						performers.AssignRunningOrder(matched.Performers, cfg.HeadlinerLast)
 					}
 					if len(data.Promoters) > 0 {
 						for _, p := range data.Promoters {
-							match, err := promoters.PromoterMatch(context.Background(), cfg.Queries, p)
+							match, err := promoters.PromoterMatch(context.Background(), cfg.Indexes.Promoters, cfg.Indexes.Festivals, p, cfg.Candidates)
 							if err == nil {
 								matched.Promoters = append(matched.Promoters, match)
 							} else if cfg.Debug {
@@ -140,6 +256,32 @@ func ExecuteScan(cfg metadata.ImagesConfig) (ScanResult, error) {
 						}
 					}
 
+					// Past events connecting the candidates with each other can settle close matches.
+					if cfg.Queries != nil && cfg.History.Enabled() {
+						if err := applyHistory(context.Background(), cfg.Queries, cfg.History, cfg.Indexes, &matched); err != nil && cfg.Debug {
+							result.ParseErrors = append(result.ParseErrors, fmt.Sprintf("Error applying match history to '%s': %v", dir, err))
+						}
+					}
+
+					// A directory naming only a festival's stage or site is attached to the festival
+					// running there that day.
+					if cfg.Queries != nil && matched.inferFestival() {
+						eventDate := time.Date(matched.Year, time.Month(matched.Month), matched.Day, 0, 0, 0, 0, time.UTC)
+						festival, ok, err := promoters.InferFestival(context.Background(), cfg.Queries, matched.Venue.Candidates[0].ID, matched.Venue.Match, eventDate)
+						if err == nil && ok {
+							matched.Promoters = append(matched.Promoters, festival)
+						} else if err != nil && cfg.Debug {
+							result.ParseErrors = append(result.ParseErrors, fmt.Sprintf("Error inferring festival at '%s': %v", matched.Venue.Match, err))
+						}
+					}
+
+					// A name that matched several entities equally well needs resolving by hand.
+					matched.Ambiguities = matched.ambiguities()
+					if len(matched.Ambiguities) > 0 && matched.Consistent {
+						matched.Consistent = false
+						result.InconsistentCount++
+					}
+
 					festivalCount := 0
 					var festival promoters.PromoterMatchResult
 					for _, p := range matched.Promoters {
@@ -151,7 +293,7 @@ func ExecuteScan(cfg metadata.ImagesConfig) (ScanResult, error) {
 
 					if festivalCount > 1 {
 						matched.Consistent = false
-					} else if festivalCount == 1 {
+					} else if festivalCount == 1 && cfg.Queries != nil {
 						// If there is exactly one festival, check if the event date is within the festival's date range.
 						foundFestival, err := cfg.Queries.GetFestivalByName(context.Background(), festival.Match)
 						if err == nil {
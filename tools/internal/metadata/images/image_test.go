@@ -0,0 +1,99 @@
+package images
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/66james99/gig-calendar/internal/metadata"
+	"github.com/66james99/gig-calendar/internal/metadata/performers"
+	"github.com/66james99/gig-calendar/internal/metadata/promoters"
+	"github.com/66james99/gig-calendar/internal/metadata/venues"
+)
+
+func TestMatchedResult_Ambiguities(t *testing.T) {
+	m := MatchedResult{
+		Venue: venues.VenueMatchResult{
+			Name:       "Union Chapel",
+			Ambiguous:  true,
+			Contenders: []metadata.Candidate{{ID: 1, Match: "Union Chapel"}, {ID: 2, Match: "Union Chapel, Islington"}},
+		},
+		Performers: [][]performers.PerformerMatchResult{{
+			{Name: "Alice Cooper", Match: "Alice Cooper"},
+			{Name: "Sophie Huntr", Ambiguous: true, Contenders: []metadata.Candidate{{ID: 3, Match: "Sophie Hunter"}, {ID: 4, Match: "Sophie Hunte"}}},
+		}},
+		Promoters: []promoters.PromoterMatchResult{{
+			Name:      "Glastonbury",
+			Ambiguous: true,
+			Contenders: []promoters.PromoterCandidate{
+				{Candidate: metadata.Candidate{ID: 5, Match: "Glastonbury"}, Promoter: true},
+				{Candidate: metadata.Candidate{ID: 6, Match: "Glastonbury"}, Festival: true},
+			},
+		}},
+	}
+
+	want := []Ambiguity{
+		{Type: "venue", Name: "Union Chapel", Contenders: []string{"Union Chapel", "Union Chapel, Islington"}},
+		{Type: "performer", Name: "Sophie Huntr", Contenders: []string{"Sophie Hunter", "Sophie Hunte"}},
+		{Type: "promoter", Name: "Glastonbury", Contenders: []string{"Glastonbury", "Glastonbury"}},
+	}
+	if got := m.ambiguities(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ambiguities() = %+v, want %+v", got, want)
+	}
+
+	if got := (MatchedResult{}).ambiguities(); got != nil {
+		t.Errorf("ambiguities() of unambiguous result = %+v, want nil", got)
+	}
+}
+
+func TestMatchedResult_InferFestival(t *testing.T) {
+	venue := venues.VenueMatchResult{
+		Name:       "Pyramid Stage",
+		Match:      "Pyramid Stage",
+		Confidence: metadata.ExactConfidence,
+		Candidates: []metadata.Candidate{{ID: 1, Match: "Pyramid Stage"}},
+	}
+
+	tests := []struct {
+		name string
+		m    MatchedResult
+		want bool
+	}{
+		{
+			name: "Festival venue with a full date",
+			m:    MatchedResult{Year: 2024, Month: 6, Day: 28, Venue: venue},
+			want: true,
+		},
+		{
+			name: "Missing day",
+			m:    MatchedResult{Year: 2024, Month: 6, Venue: venue},
+			want: false,
+		},
+		{
+			name: "Unmatched venue",
+			m:    MatchedResult{Year: 2024, Month: 6, Day: 28, Venue: venues.VenueMatchResult{Name: "Somewhere"}},
+			want: false,
+		},
+		{
+			name: "Festival already named",
+			m: MatchedResult{Year: 2024, Month: 6, Day: 28, Venue: venue, Promoters: []promoters.PromoterMatchResult{
+				{Name: "Glastonbury", Match: "Glastonbury", Confidence: metadata.ExactConfidence, Festival: true},
+			}},
+			want: false,
+		},
+		{
+			name: "Promoter is not a festival",
+			m: MatchedResult{Year: 2024, Month: 6, Day: 28, Venue: venue, Promoters: []promoters.PromoterMatchResult{
+				{Name: "Live Nation", Match: "Live Nation", Confidence: metadata.ExactConfidence, Promoter: true},
+			}},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.m.inferFestival(); got != tt.want {
+				t.Errorf("inferFestival() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -14,12 +14,13 @@ type LocationData struct {
 	MonthName  string
 	Performers []string
 	Venue      string
+	City       string
 	Promoters  []string
 	Consistent bool
 }
 
 var knownPlaceholders = map[string]struct{}{
-	"%y": {}, "%m": {}, "%d": {}, "%M": {}, "%P": {}, "%V": {}, "%p": {},
+	"%y": {}, "%m": {}, "%d": {}, "%M": {}, "%P": {}, "%V": {}, "%C": {}, "%p": {},
 }
 
 var monthNames = map[string]int{
@@ -67,6 +68,7 @@ func ValidatePattern(pattern string) error {
 // %M: names of the months of the year
 // %P: comma separated list of performers names
 // %V: venue name (cannot contain '(' or ')')
+// %C: city of the venue
 // %p: comma separated list of promoters names, allowed to be empty
 func ParseLocation(pattern, location string) (LocationData, error) {
 	type token struct {
@@ -227,6 +229,8 @@ func ParseLocation(pattern, location string) (LocationData, error) {
 			data.MonthName = val
 		case "%V":
 			data.Venue = strings.TrimSpace(val)
+		case "%C":
+			data.City = strings.TrimSpace(val)
 		case "%P":
 			data.Performers = nil
 			if val == "" {
@@ -84,6 +84,22 @@ func TestParseLocation(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name:     "With city",
+			pattern:  "%y/%m - %M %y/%d - %P (%V, %C)",
+			location: "2024/01 - January 2024/24 - Performer1 (Venue Name, Bristol)",
+			want: LocationData{
+				Year:       2024,
+				Month:      1,
+				Day:        24,
+				MonthName:  "January",
+				Performers: []string{"Performer1"},
+				Venue:      "Venue Name",
+				City:       "Bristol",
+				Consistent: true,
+			},
+			wantErr: false,
+		},
 		{
 			name:     "Missing promoter (optional)",
 			pattern:  "%y/%m - %M %y/%d - %P (%V) %p",
@@ -0,0 +1,357 @@
+package metadata
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/66james99/gig-calendar/internal/dbcollection"
+)
+
+// IndexEntry is a single name held by a MatchIndex, either the canonical name of an
+// entity or one of its aliases.
+type IndexEntry struct {
+	ID      int32  // ID of the entity the name resolves to
+	Name    string // Canonical name of the entity
+	AliasID int32  // ID of the alias row, 0 when the entry is the entity's own name
+	Alias   string // Alias text, empty when the entry is the entity's own name
+
+	Phonetic string // PhoneticKey of the name or alias, empty unless the index is phonetic
+	City     string // City of the entity, used to choose between venues with the same name
+}
+
+// IsAlias reports whether the entry came from an alias table.
+func (e IndexEntry) IsAlias() bool {
+	return e.AliasID != 0
+}
+
+// MatchKey returns the key the entry is indexed under: the MatchKey of its alias when the
+// entry is an alias and of its name otherwise.
+func (e IndexEntry) MatchKey() string {
+	if e.IsAlias() {
+		return MatchKey(e.Alias)
+	}
+	return MatchKey(e.Name)
+}
+
+// IndexData is the map backing a MatchIndex, keyed by the MatchKey of each name.
+type IndexData map[string][]IndexEntry
+
+// Add adds an entry to the index data under its MatchKey.
+func (d IndexData) Add(e IndexEntry) {
+	key := e.MatchKey()
+	d[key] = append(d[key], e)
+}
+
+// AddName adds the canonical name of an entity to the index data.
+func (d IndexData) AddName(id int32, name string) {
+	d.Add(IndexEntry{ID: id, Name: name})
+}
+
+// AddAlias adds an alias of an entity to the index data.
+func (d IndexData) AddAlias(id int32, name string, aliasID int32, alias string) {
+	d.Add(IndexEntry{ID: id, Name: name, AliasID: aliasID, Alias: alias})
+}
+
+// AddPhoneticName adds the canonical name of an entity with its phonetic key. An empty
+// phonetic key, as held by rows written before keys were stored, is calculated here.
+func (d IndexData) AddPhoneticName(id int32, name string, phonetic string) {
+	if phonetic == "" {
+		phonetic = PhoneticKey(name)
+	}
+	d.Add(IndexEntry{ID: id, Name: name, Phonetic: phonetic})
+}
+
+// AddPhoneticAlias adds an alias of an entity with its phonetic key, calculating the key
+// if it is empty.
+func (d IndexData) AddPhoneticAlias(id int32, name string, aliasID int32, alias string, phonetic string) {
+	if phonetic == "" {
+		phonetic = PhoneticKey(alias)
+	}
+	d.Add(IndexEntry{ID: id, Name: name, AliasID: aliasID, Alias: alias, Phonetic: phonetic})
+}
+
+// MatchIndex is an in-memory index of the names and aliases of one type of entity.
+// It is backed by a dbcollection.DBMap so it is only reloaded from the database when
+// the dbcollections_meta timestamp for the underlying tables moves on.
+type MatchIndex struct {
+	entries         *dbcollection.DBMap[string, []IndexEntry]
+	search          SearchFunc
+	searchThreshold int
+	phonetic        bool
+	phonetics       entryKeys
+	normalizer      *Normalizer // Normalizer names are compared by, nil for DefaultNormalizer
+	keys            entryKeys
+	settings        *dbcollection.DBMap[string, MatchSettings]
+	entityType      string
+}
+
+// entryKeys maps keys other than the MatchKey of the names in an index to their entries,
+// such as their phonetic keys. A SearchFunc finds names by their spelling, so cannot find
+// those that only sound the same, and the phonetic stage looks them up here instead once
+// the index searches.
+type entryKeys struct {
+	mu      sync.Mutex
+	loaded  int // GetDBQueried of the entries the map was built from
+	entries map[string][]IndexEntry
+}
+
+// get returns the map from the keys of entries to the entries, building it again with key
+// when entries has been reloaded since it was last built. Entries without a key are left out.
+func (k *entryKeys) get(entries *dbcollection.DBMap[string, []IndexEntry], key func(IndexEntry) string) map[string][]IndexEntry {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if loaded := entries.GetDBQueried(); k.entries == nil || k.loaded != loaded {
+		keys := make(map[string][]IndexEntry)
+		entries.Range(func(_ string, entries []IndexEntry) bool {
+			for _, e := range entries {
+				if key := key(e); key != "" {
+					keys[key] = append(keys[key], e)
+				}
+			}
+			return true
+		})
+		k.entries, k.loaded = keys, loaded
+	}
+	return k.entries
+}
+
+// SearchFunc returns up to limit entries whose names or aliases are near to name,
+// typically using a trigram search in the database.
+type SearchFunc func(ctx context.Context, name string, limit int32) ([]IndexEntry, error)
+
+// SearchThreshold is the number of names an index must hold before its fuzzy stage
+// switches from scoring every name in memory to a database search.
+const SearchThreshold = 10000
+
+// searchLimit is the number of nearest entries fetched by a SearchFunc before they are
+// rescored with Similarity.
+const searchLimit = 25
+
+// MatchIndexes groups the indexes used by the venue, performer and promoter matchers.
+type MatchIndexes struct {
+	Venues     *MatchIndex
+	Performers *MatchIndex
+	Promoters  *MatchIndex
+	Festivals  *MatchIndex
+	Settings   *dbcollection.DBMap[string, MatchSettings] // Settings used by the indexes, refreshed with them
+}
+
+// NewMatchIndex creates a MatchIndex using lastModifiedFunc to detect changes and
+// dataFunc to load the names and aliases.
+func NewMatchIndex(ctx context.Context, lastModifiedFunc func(context.Context) (time.Time, error), dataFunc func(context.Context) (IndexData, error)) (*MatchIndex, error) {
+	entries, err := dbcollection.NewDBMap(ctx, lastModifiedFunc, func(ctx context.Context) (map[string][]IndexEntry, error) {
+		return dataFunc(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &MatchIndex{entries: entries}, nil
+}
+
+// SetSearch configures the index to find fuzzy candidates with search instead of scoring
+// every name in memory, once the index holds at least threshold names.
+func (idx *MatchIndex) SetSearch(search SearchFunc, threshold int) {
+	idx.search = search
+	idx.searchThreshold = threshold
+}
+
+// EnablePhonetic adds a phonetic stage to Candidates, matching names whose phonetic key
+// equals that of the name being matched with PhoneticConfidence. The index data should be
+// built with AddPhoneticName and AddPhoneticAlias.
+func (idx *MatchIndex) EnablePhonetic() {
+	idx.phonetic = true
+}
+
+// phoneticEntries returns the entries whose phonetic key is key.
+func (idx *MatchIndex) phoneticEntries(key string) []IndexEntry {
+	return idx.phonetics.get(idx.entries, func(e IndexEntry) string { return e.Phonetic })[key]
+}
+
+// SetNormalizer configures the index to compare names by their Key under n rather than
+// their MatchKey, for entity types whose names need other normalization steps.
+func (idx *MatchIndex) SetNormalizer(n Normalizer) {
+	idx.normalizer = &n
+}
+
+// Key reduces s to the key the index compares names by.
+func (idx *MatchIndex) Key(s string) string {
+	if idx.normalizer != nil {
+		return idx.normalizer.Key(s)
+	}
+	return MatchKey(s)
+}
+
+// entryKey returns the key the index compares e by: the Key of its alias when the entry is
+// an alias and of its name otherwise.
+func (idx *MatchIndex) entryKey(e IndexEntry) string {
+	if e.IsAlias() {
+		return idx.Key(e.Alias)
+	}
+	return idx.Key(e.Name)
+}
+
+// keyedEntries returns the entries compared by key. IndexData holds entries by their
+// MatchKey, so they are only keyed again when a Normalizer is set.
+func (idx *MatchIndex) keyedEntries(key string) []IndexEntry {
+	if idx.normalizer == nil {
+		entries, _ := idx.entries.GetValue(key)
+		return entries
+	}
+	return idx.keys.get(idx.entries, idx.entryKey)[key]
+}
+
+// rangeKeyedEntries calls f for each key the index compares names by and the entries
+// compared by it.
+func (idx *MatchIndex) rangeKeyedEntries(f func(key string, entries []IndexEntry)) {
+	if idx.normalizer == nil {
+		idx.entries.Range(func(key string, entries []IndexEntry) bool {
+			f(key, entries)
+			return true
+		})
+		return
+	}
+	for key, entries := range idx.keys.get(idx.entries, idx.entryKey) {
+		f(key, entries)
+	}
+}
+
+// SetSettings configures the index to take its confidences and fuzzy matching thresholds
+// from the entityType entry of settings, so they can be tuned without recompiling.
+// Without settings, or when settings holds no entry for entityType, the index uses
+// DefaultMatchSettings.
+func (idx *MatchIndex) SetSettings(settings *dbcollection.DBMap[string, MatchSettings], entityType string) {
+	idx.settings = settings
+	idx.entityType = entityType
+}
+
+// Settings returns the MatchSettings the index currently matches with.
+func (idx *MatchIndex) Settings() MatchSettings {
+	if idx.settings != nil {
+		if s, ok := idx.settings.GetValue(idx.entityType); ok {
+			return s
+		}
+	}
+	return DefaultMatchSettings
+}
+
+// Refresh reloads the index if the underlying tables have changed since it was last loaded.
+func (idx *MatchIndex) Refresh(ctx context.Context) error {
+	return idx.entries.UpdateMapValues(ctx)
+}
+
+// Candidates returns up to n entities ranked by how well their names or aliases match
+// name, keeping only the best scoring name or alias for each entity. Exact matches on the
+// canonical name rank first, then exact alias matches, fuzzy name matches and finally fuzzy
+// alias matches, each ordered by Similarity score. Phonetic indexes finally add names that
+// sound the same. A non-positive n returns DefaultCandidates, and more than n are returned
+// when more than n entities were found at the best stage.
+// Confidences other than ExactConfidence, and the lowest Similarity accepted as a fuzzy
+// match, come from the index's MatchSettings.
+// Both name and the indexed names are compared by their Key.
+// Exact and phonetic matches are always resolved from memory; fuzzy matches come from the
+// SearchFunc when one is set and the index is large enough, otherwise from scoring every name.
+func (idx *MatchIndex) Candidates(ctx context.Context, name string, n int) ([]Candidate, error) {
+	if n <= 0 {
+		n = DefaultCandidates
+	}
+	normalized := idx.Key(name)
+	settings := idx.Settings()
+
+	best := make(map[int32]Candidate)
+	consider := func(key string, e IndexEntry, stage Stage, confidence int, score float64) {
+		c := Candidate{ID: e.ID, Match: e.Name, Alias: e.Alias, City: e.City, Confidence: confidence, Score: score}
+		if current, ok := best[e.ID]; !ok || candidateLess(c, current) {
+			c.Explanation = &Explanation{
+				Stage:    stage,
+				AliasID:  e.AliasID,
+				Alias:    e.Alias,
+				Distance: Levenshtein(normalized, key),
+				Input:    normalized,
+				Compared: key,
+			}
+			best[e.ID] = c
+		}
+	}
+	var phonetic string
+	if idx.phonetic {
+		phonetic = PhoneticKey(name)
+	}
+	considerFuzzy := func(key string, e IndexEntry) {
+		score := Similarity(key, normalized)
+		switch {
+		case score >= settings.MinSimilarity && e.IsAlias():
+			consider(key, e, StageFuzzyAlias, settings.FuzzyAliasConfidence, score)
+		case score >= settings.MinSimilarity:
+			consider(key, e, StageFuzzyName, settings.FuzzyNameConfidence, score)
+		case phonetic != "" && e.Phonetic == phonetic:
+			consider(key, e, StagePhonetic, settings.PhoneticConfidence, score)
+		}
+	}
+
+	// Exact matches on names and aliases
+	for _, e := range idx.keyedEntries(normalized) {
+		if e.IsAlias() {
+			consider(normalized, e, StageAlias, settings.AliasConfidence, 1)
+		} else {
+			consider(normalized, e, StageExact, ExactConfidence, 1)
+		}
+	}
+
+	// Fuzzy and phonetic matches on names and aliases
+	if idx.search != nil && idx.entries.Len() >= idx.searchThreshold {
+		// The database holds names as entered, so search with the raw name rather than its key
+		entries, err := idx.search(ctx, Normalize(name), searchLimit)
+		if err != nil {
+			return nil, err
+		}
+		if phonetic != "" {
+			entries = append(entries, idx.phoneticEntries(phonetic)...)
+		}
+		for _, e := range entries {
+			key := idx.entryKey(e)
+			if key != normalized {
+				considerFuzzy(key, e)
+			}
+		}
+	} else {
+		idx.rangeKeyedEntries(func(key string, entries []IndexEntry) {
+			if key == normalized {
+				return
+			}
+			for _, e := range entries {
+				considerFuzzy(key, e)
+			}
+		})
+	}
+
+	candidates := make([]Candidate, 0, len(best))
+	for _, c := range best {
+		candidates = append(candidates, c)
+	}
+	SortCandidates(candidates)
+	if len(candidates) > n {
+		// Never drop a candidate found at the best stage, so ambiguity can be detected
+		candidates = candidates[:max(n, bestStageCount(candidates))]
+	}
+	return candidates, nil
+}
+
+// Refresh reloads any of the indexes whose underlying tables have changed, and the
+// settings they use.
+func (m *MatchIndexes) Refresh(ctx context.Context) error {
+	if m.Settings != nil {
+		if err := m.Settings.UpdateMapValues(ctx); err != nil {
+			return err
+		}
+	}
+	for _, idx := range []*MatchIndex{m.Venues, m.Performers, m.Promoters, m.Festivals} {
+		if idx == nil {
+			continue
+		}
+		if err := idx.Refresh(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -27,6 +27,13 @@ type ImagesConfig struct {
 	Pattern       string // The pattern of tokens to be matching in the directory path
 	IncludeParent bool
 	IgnoreDirs    []string
+	DefaultCity   string // The city of venues in directories whose pattern has no %C token
+	HeadlinerLast bool   // Whether the %P list names the headliner last rather than first
 	Queries       *database.Queries
-	Patterns      *dbcollection.DBArray[string] // An array of patterns to be used to seperate performers when there are more than one in a single slot
+	Patterns      *dbcollection.DBArray[string]              // An array of patterns to be used to seperate performers when there are more than one in a single slot
+	StageRoles    *dbcollection.DBMap[string, StageRoleKind] // The kind of stage role each of the Patterns records, keyed by pattern
+	Indexes       *MatchIndexes                              // In-memory indexes of venue, performer, promoter and festival names used by the matchers
+	Candidates    int                                        // The number of ranked candidates returned by the matchers, DefaultCandidates when 0
+	History       HistoryBoost                               // Boosts given to candidates connected by past events, none when zero
+	EventType     int32                                      // The event type of the events written for matched directories, none written when 0
 }
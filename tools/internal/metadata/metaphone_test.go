@@ -0,0 +1,56 @@
+package metadata
+
+import "testing"
+
+func TestDoubleMetaphone(t *testing.T) {
+	tests := []struct {
+		word          string
+		wantPrimary   string
+		wantAlternate string
+	}{
+		{word: "Sophie", wantPrimary: "SF", wantAlternate: "SF"},
+		{word: "Sofie", wantPrimary: "SF", wantAlternate: "SF"},
+		{word: "Katherine", wantPrimary: "K0RN", wantAlternate: "KTRN"},
+		{word: "Kathryn", wantPrimary: "K0RN", wantAlternate: "KTRN"},
+		{word: "Smith", wantPrimary: "SM0", wantAlternate: "XMT"},
+		{word: "Schmidt", wantPrimary: "XMT", wantAlternate: "SMT"},
+		{word: "Knight", wantPrimary: "NT", wantAlternate: "NT"},
+		{word: "Caesar", wantPrimary: "SSR", wantAlternate: "SSR"},
+		{word: "Jose", wantPrimary: "HS", wantAlternate: "HS"},
+		{word: "Xavier", wantPrimary: "SF", wantAlternate: "SFR"},
+		{word: "Laugh", wantPrimary: "LF", wantAlternate: "LF"},
+		{word: "Michael", wantPrimary: "MKL", wantAlternate: "MXL"},
+		{word: "", wantPrimary: "", wantAlternate: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			primary, alternate := DoubleMetaphone(tt.word)
+			if primary != tt.wantPrimary || alternate != tt.wantAlternate {
+				t.Errorf("DoubleMetaphone(%q) = (%q, %q), want (%q, %q)", tt.word, primary, alternate, tt.wantPrimary, tt.wantAlternate)
+			}
+		})
+	}
+}
+
+func TestPhoneticKey(t *testing.T) {
+	tests := []struct {
+		a, b string
+		same bool
+	}{
+		{a: "Katherine Phillips", b: "Kathryn Filips", same: true},
+		{a: "Stephen Matthews", b: "Stefan Mathews", same: true},
+		{a: "The Sophie Hunter Band", b: "Sofie Hunter Band", same: true},
+		{a: "Maroon Five", b: "Maroon 5", same: true},
+		{a: "Sophie Hunter", b: "Sophie Hansen", same: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a, func(t *testing.T) {
+			ka, kb := PhoneticKey(tt.a), PhoneticKey(tt.b)
+			if (ka == kb) != tt.same {
+				t.Errorf("PhoneticKey(%q) = %q, PhoneticKey(%q) = %q, want same = %v", tt.a, ka, tt.b, kb, tt.same)
+			}
+		})
+	}
+}
@@ -1,8 +1,114 @@
 package metadata
 
-import "strings"
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
 
 // Normalize removes leading/trailing whitespace and replaces multiple spaces with a single space.
 func Normalize(s string) string {
 	return strings.Join(strings.Fields(s), " ")
 }
+
+// Normalizer is a configurable pipeline that reduces a name to the key used to compare it
+// with other names, so that trivial differences in spelling do not defeat an exact match.
+// Each enabled step is applied in the order the fields are declared, after lowercasing.
+type Normalizer struct {
+	FoldDiacritics   bool     // Decompose with NFKD and drop combining marks, e.g. "Beyoncé" -> "beyonce"
+	StripPunctuation bool     // Drop punctuation, e.g. "AC/DC" -> "acdc"; dashes separate words
+	Articles         []string // Leading (or ", The" style trailing) articles to drop
+	NumberWords      bool     // Replace number words with digits, e.g. "Maroon Five" -> "maroon 5"
+}
+
+// DefaultNormalizer is the pipeline used to build MatchIndex keys and to key raw input,
+// unless the index is given its own with SetNormalizer.
+var DefaultNormalizer = Normalizer{
+	FoldDiacritics:   true,
+	StripPunctuation: true,
+	Articles:         []string{"the", "a", "an"},
+	NumberWords:      true,
+}
+
+// MatchKey reduces s to its match key using DefaultNormalizer.
+func MatchKey(s string) string {
+	return DefaultNormalizer.Key(s)
+}
+
+// numberWords maps spelled out numbers to their digits.
+var numberWords = map[string]string{
+	"zero": "0", "one": "1", "two": "2", "three": "3", "four": "4", "five": "5",
+	"six": "6", "seven": "7", "eight": "8", "nine": "9", "ten": "10",
+	"eleven": "11", "twelve": "12", "thirteen": "13", "fourteen": "14", "fifteen": "15",
+	"sixteen": "16", "seventeen": "17", "eighteen": "18", "nineteen": "19", "twenty": "20",
+	"thirty": "30", "forty": "40", "fifty": "50", "sixty": "60", "seventy": "70",
+	"eighty": "80", "ninety": "90", "hundred": "100",
+}
+
+// Key reduces s to a lowercase, whitespace normalized key using the enabled steps.
+func (n Normalizer) Key(s string) string {
+	s = strings.ToLower(s)
+	s = strings.ReplaceAll(s, "&", " and ")
+	s = strings.ReplaceAll(s, "@", " at ")
+
+	if n.FoldDiacritics {
+		t := transform.Chain(norm.NFKD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+		if folded, _, err := transform.String(t, s); err == nil {
+			s = folded
+		}
+	}
+
+	if len(n.Articles) > 0 {
+		s = n.moveTrailingArticle(s)
+	}
+
+	if n.StripPunctuation {
+		s = strings.Map(func(r rune) rune {
+			switch {
+			case unicode.Is(unicode.Pd, r):
+				return ' '
+			case unicode.IsPunct(r), unicode.IsSymbol(r):
+				return -1
+			}
+			return r
+		}, s)
+	}
+
+	words := strings.Fields(s)
+	if len(words) > 1 && n.isArticle(words[0]) {
+		words = words[1:]
+	}
+	if n.NumberWords {
+		for i, w := range words {
+			if d, ok := numberWords[w]; ok {
+				words[i] = d
+			}
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// moveTrailingArticle rewrites catalogue style names such as "Staves, The" as "the Staves"
+// so the leading article is then dropped like any other.
+func (n Normalizer) moveTrailingArticle(s string) string {
+	i := strings.LastIndex(s, ",")
+	if i < 0 {
+		return s
+	}
+	if article := strings.TrimSpace(s[i+1:]); n.isArticle(article) {
+		return article + " " + s[:i]
+	}
+	return s
+}
+
+func (n Normalizer) isArticle(word string) bool {
+	for _, a := range n.Articles {
+		if word == a {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package metadata
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestNormalize(t *testing.T) {
+	if got := Normalize("  Alice   Cooper "); got != "Alice Cooper" {
+		t.Errorf("Normalize() = %q, want %q", got, "Alice Cooper")
+	}
+}
+
+func TestMatchKey(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+	}{
+		{name: "Diacritics", a: "Beyoncé", b: "Beyonce"},
+		{name: "Leading article", a: "The Staves", b: "Staves"},
+		{name: "Trailing article", a: "Staves, The", b: "The Staves"},
+		{name: "Punctuation", a: "AC/DC", b: "ACDC"},
+		{name: "Curly quotes", a: "Guns N’ Roses", b: "Guns N' Roses"},
+		{name: "Dashes separate words", a: "Jay-Z", b: "Jay Z"},
+		{name: "Ampersand", a: "Earth, Wind & Fire", b: "Earth Wind and Fire"},
+		{name: "Number words", a: "Maroon Five", b: "Maroon 5"},
+		{name: "Case and whitespace", a: "  ALICE   cooper ", b: "Alice Cooper"},
+		{name: "Compatibility characters", a: "Ｍｏｇｗａｉ", b: "Mogwai"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if ka, kb := MatchKey(tt.a), MatchKey(tt.b); ka != kb {
+				t.Errorf("MatchKey(%q) = %q, MatchKey(%q) = %q, want equal", tt.a, ka, tt.b, kb)
+			}
+		})
+	}
+}
+
+func TestNormalizer_Key(t *testing.T) {
+	tests := []struct {
+		name string
+		n    Normalizer
+		in   string
+		want string
+	}{
+		{name: "No steps only lowercases", n: Normalizer{}, in: "The Beyoncé Show", want: "the beyoncé show"},
+		{name: "Lone article is kept", n: DefaultNormalizer, in: "The", want: "the"},
+		{name: "Article inside name is kept", n: DefaultNormalizer, in: "Bring Me The Horizon", want: "bring me the horizon"},
+		{name: "Number words inside words are kept", n: DefaultNormalizer, in: "Someone Else", want: "someone else"},
+		{name: "Custom articles", n: Normalizer{Articles: []string{"les"}}, in: "Les Négresses Vertes", want: "négresses vertes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.n.Key(tt.in); got != tt.want {
+				t.Errorf("Key(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchIndex_SetNormalizer(t *testing.T) {
+	ctx := context.Background()
+	idx, err := NewMatchIndex(ctx, func(context.Context) (time.Time, error) { return time.Now(), nil }, func(context.Context) (IndexData, error) {
+		d := make(IndexData)
+		d.AddName(1, "The Staves")
+		d.AddName(2, "Les Négresses Vertes")
+		return d, nil
+	})
+	if err != nil {
+		t.Fatalf("failed to create MatchIndex: %v", err)
+	}
+
+	exact := func(name string) int32 {
+		t.Helper()
+		c, err := idx.Candidates(ctx, name, 1)
+		if err != nil {
+			t.Fatalf("Candidates(%q) error = %v", name, err)
+		}
+		if len(c) == 0 || c[0].Confidence != ExactConfidence {
+			return 0
+		}
+		return c[0].ID
+	}
+
+	// DefaultNormalizer drops English articles only
+	if got := exact("Staves"); got != 1 {
+		t.Errorf("Candidates(Staves) exact match = %d, want 1", got)
+	}
+	if got := exact("Negresses Vertes"); got != 0 {
+		t.Errorf("Candidates(Negresses Vertes) exact match = %d, want none", got)
+	}
+
+	idx.SetNormalizer(Normalizer{FoldDiacritics: true, Articles: []string{"les"}})
+	if got := exact("Staves"); got != 0 {
+		t.Errorf("Candidates(Staves) exact match = %d, want none", got)
+	}
+	if got := exact("Negresses Vertes"); got != 2 {
+		t.Errorf("Candidates(Negresses Vertes) exact match = %d, want 2", got)
+	}
+	if got := idx.Key("Les Négresses Vertes"); got != "negresses vertes" {
+		t.Errorf("Key() = %q, want %q", got, "negresses vertes")
+	}
+}
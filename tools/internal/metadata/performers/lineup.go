@@ -0,0 +1,63 @@
+package performers
+
+import (
+	"database/sql"
+
+	"github.com/66james99/gig-calendar/internal/database"
+	"github.com/66james99/gig-calendar/internal/metadata"
+)
+
+// AssignRunningOrder sets the Slot and Headliner of each performer in a matched %P list.
+// groups holds the results of MultiPerformerMatch for each entry of the list, in the
+// order the list names them, which puts the headliner first unless headlinerLast is set.
+// Slots count up from 1 in running order, so the headliner, who is on stage last, takes
+// the highest slot. Performers who joined the headliner through a b2b or in-conversation
+// stage role share top billing and are marked as headliners too.
+func AssignRunningOrder(groups [][]PerformerMatchResult, headlinerLast bool) {
+	if len(groups) == 0 {
+		return
+	}
+
+	slot := 1
+	assign := func(group []PerformerMatchResult) {
+		for i := range group {
+			group[i].Slot = slot
+			slot++
+		}
+	}
+	if headlinerLast {
+		for _, group := range groups {
+			assign(group)
+		}
+	} else {
+		for i := len(groups) - 1; i >= 0; i-- {
+			assign(groups[i])
+		}
+	}
+
+	headliners := groups[0]
+	if headlinerLast {
+		headliners = groups[len(groups)-1]
+	}
+	for i := range headliners {
+		role := headliners[i].Role
+		if i == 0 || role == metadata.StageRoleB2B || role == metadata.StageRoleInConversation {
+			headliners[i].Headliner = true
+		}
+	}
+}
+
+// EventPerformer returns the event_performer row recording the matched performer's place
+// in the lineup of event. It returns false when the performer was not matched.
+func (r PerformerMatchResult) EventPerformer(event int32) (database.CreateEventPerformerParams, bool) {
+	if r.Confidence == 0 || len(r.Candidates) == 0 {
+		return database.CreateEventPerformerParams{}, false
+	}
+	return database.CreateEventPerformerParams{
+		Event:     event,
+		Performer: r.Candidates[0].ID,
+		Headliner: r.Headliner,
+		Slot:      int32(r.Slot),
+		Role:      sql.NullString{String: string(r.Role), Valid: r.Role != ""},
+	}, true
+}
@@ -0,0 +1,106 @@
+package performers
+
+import (
+	"testing"
+
+	"github.com/66james99/gig-calendar/internal/metadata"
+)
+
+func TestAssignRunningOrder(t *testing.T) {
+	tests := []struct {
+		name          string
+		groups        [][]PerformerMatchResult
+		headlinerLast bool
+		wantSlots     map[string]int
+		wantHeadliner []string
+	}{
+		{
+			name: "Headliner first",
+			groups: [][]PerformerMatchResult{
+				{{Name: "Headliner"}},
+				{{Name: "Main Support"}},
+				{{Name: "Opener"}},
+			},
+			wantSlots:     map[string]int{"Opener": 1, "Main Support": 2, "Headliner": 3},
+			wantHeadliner: []string{"Headliner"},
+		},
+		{
+			name: "Headliner last",
+			groups: [][]PerformerMatchResult{
+				{{Name: "Opener"}},
+				{{Name: "Main Support"}},
+				{{Name: "Headliner"}},
+			},
+			headlinerLast: true,
+			wantSlots:     map[string]int{"Opener": 1, "Main Support": 2, "Headliner": 3},
+			wantHeadliner: []string{"Headliner"},
+		},
+		{
+			name: "Shared top billing",
+			groups: [][]PerformerMatchResult{
+				{{Name: "Host"}, {Name: "Guest", Role: metadata.StageRoleInConversation}},
+				{{Name: "Support"}, {Name: "Friend", Role: metadata.StageRoleGuest}},
+			},
+			wantSlots:     map[string]int{"Support": 1, "Friend": 2, "Host": 3, "Guest": 4},
+			wantHeadliner: []string{"Host", "Guest"},
+		},
+		{
+			name: "Featured performer is not a headliner",
+			groups: [][]PerformerMatchResult{
+				{{Name: "Band"}, {Name: "Singer", Role: metadata.StageRoleFeaturing}},
+			},
+			wantSlots:     map[string]int{"Band": 1, "Singer": 2},
+			wantHeadliner: []string{"Band"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			AssignRunningOrder(tt.groups, tt.headlinerLast)
+
+			var headliners []string
+			for _, group := range tt.groups {
+				for _, p := range group {
+					if p.Slot != tt.wantSlots[p.Name] {
+						t.Errorf("%s Slot = %d, want %d", p.Name, p.Slot, tt.wantSlots[p.Name])
+					}
+					if p.Headliner {
+						headliners = append(headliners, p.Name)
+					}
+				}
+			}
+			if len(headliners) != len(tt.wantHeadliner) {
+				t.Fatalf("headliners = %v, want %v", headliners, tt.wantHeadliner)
+			}
+			for i := range headliners {
+				if headliners[i] != tt.wantHeadliner[i] {
+					t.Errorf("headliners = %v, want %v", headliners, tt.wantHeadliner)
+				}
+			}
+		})
+	}
+}
+
+func TestPerformerMatchResult_EventPerformer(t *testing.T) {
+	matched := PerformerMatchResult{
+		Name:       "Guest",
+		Confidence: metadata.ExactConfidence,
+		Candidates: []metadata.Candidate{{ID: 7, Match: "Guest"}},
+		Role:       metadata.StageRoleGuest,
+		Slot:       2,
+	}
+	got, ok := matched.EventPerformer(3)
+	if !ok {
+		t.Fatal("EventPerformer() ok = false, want true")
+	}
+	if got.Event != 3 || got.Performer != 7 || got.Slot != 2 || got.Headliner {
+		t.Errorf("EventPerformer() = %+v", got)
+	}
+	if !got.Role.Valid || got.Role.String != "guest" {
+		t.Errorf("EventPerformer() Role = %+v, want guest", got.Role)
+	}
+
+	if _, ok := (PerformerMatchResult{Name: "Unknown"}).EventPerformer(3); ok {
+		t.Error("EventPerformer() ok = true for an unmatched performer, want false")
+	}
+}
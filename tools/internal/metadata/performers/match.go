@@ -2,7 +2,6 @@ package performers
 
 import (
 	"context"
-	"database/sql"
 	"sort"
 	"strings"
 
@@ -14,80 +13,132 @@ import (
 
 // PerformerMatchResult holds the result of a performer matching operation.
 type PerformerMatchResult struct {
-	Name       string `json:"name"`
-	Match      string `json:"match,omitempty"`
-	Confidence int    `json:"confidence"`
-	Pattern    string `json:"pattern,omitempty"`
+	Name        string                 `json:"name"`
+	Match       string                 `json:"match,omitempty"`
+	Confidence  int                    `json:"confidence"`
+	Score       float64                `json:"score,omitempty"`
+	Explanation *metadata.Explanation  `json:"explanation,omitempty"`
+	Boost       *metadata.Boost        `json:"boost,omitempty"`
+	Ambiguous   bool                   `json:"ambiguous,omitempty"`
+	Contenders  []metadata.Candidate   `json:"contenders,omitempty"`
+	Candidates  []metadata.Candidate   `json:"candidates,omitempty"`
+	Pattern     string                 `json:"pattern,omitempty"`
+	Role        metadata.StageRoleKind `json:"role,omitempty"` // The kind of stage role Pattern records
+	Slot        int                    `json:"slot,omitempty"` // Position in the running order, set by AssignRunningOrder
+	Headliner   bool                   `json:"headliner,omitempty"`
 }
 
-// PerformerMatch checks for the existence of a performer in the database.
-// It returns a confidence score:
+// NewPerformerIndex loads the performer and performer_alias tables into a MatchIndex for use by PerformerMatch.
+func NewPerformerIndex(ctx context.Context, q *database.Queries) (*metadata.MatchIndex, error) {
+	idx, err := metadata.NewMatchIndex(ctx, q.LastModifiedPerformers, func(ctx context.Context) (metadata.IndexData, error) {
+		performers, err := q.ListPerformers(ctx)
+		if err != nil {
+			return nil, err
+		}
+		aliases, err := q.ListPerformerAliases(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		data := make(metadata.IndexData)
+		performerMap := make(map[int32]string)
+		for _, p := range performers {
+			data.AddPhoneticName(p.ID, p.Name, p.Phonetic)
+			performerMap[p.ID] = p.Name
+		}
+		for _, a := range aliases {
+			if name, ok := performerMap[a.Performer]; ok {
+				data.AddPhoneticAlias(a.Performer, name, a.ID, a.Alias, a.Phonetic)
+			}
+		}
+		return data, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Large tables use the pg_trgm candidate search for the fuzzy stage
+	idx.SetSearch(func(ctx context.Context, name string, limit int32) ([]metadata.IndexEntry, error) {
+		rows, err := q.SearchPerformerCandidates(ctx, database.SearchPerformerCandidatesParams{Query: name, MaxResults: limit})
+		if err != nil {
+			return nil, err
+		}
+		entries := make([]metadata.IndexEntry, len(rows))
+		for i, r := range rows {
+			entries[i] = metadata.IndexEntry{ID: r.ID, Name: r.Name, AliasID: r.AliasID, Alias: r.Alias}
+		}
+		return entries, nil
+	}, metadata.SearchThreshold)
+	idx.EnablePhonetic()
+	return idx, nil
+}
+
+// PerformerMatch checks for the existence of a performer in the index.
+// It returns the best of up to n ranked candidates, with a confidence score of, unless
+// tuned in the performer row of match_setting:
 // 100: Exact match in performer table
 // 75:  Match in performer_alias table
 // 50:  Fuzzy match against performer table
 // 25:  Fuzzy match against performer_alias table
+// 20:  Performer or performer_alias that sounds the same
 // 0:   No match
-func PerformerMatch(ctx context.Context, q *database.Queries, rawPerformer string) (PerformerMatchResult, error) {
-	normalized := metadata.Normalize(rawPerformer)
-	if normalized == "" {
+// Score grades how similar the performer name was to the best candidate.
+// The result is Ambiguous when several performers were found at the best stage with scores
+// within metadata.ContenderMargin of each other, and these are listed as Contenders.
+func PerformerMatch(ctx context.Context, idx *metadata.MatchIndex, rawPerformer string, n int) (PerformerMatchResult, error) {
+	if idx.Key(rawPerformer) == "" {
 		return PerformerMatchResult{Confidence: 0}, nil
 	}
 
-	// 1. Exact match in Performer table
-	p, err := q.GetPerformerByName(ctx, normalized)
-	if err == nil {
-		return PerformerMatchResult{Name: p.Name, Match: p.Name, Confidence: 100}, nil
-	} else if err != sql.ErrNoRows {
-		return PerformerMatchResult{}, err
-	}
-
-	// 2. Match in Performer Alias table
-	a, err := q.GetPerformerAliasByName(ctx, normalized)
-	if err == nil {
-		performer, err := q.GetPerformer(ctx, a.Performer)
-		if err != nil {
-			return PerformerMatchResult{}, err
-		}
-		return PerformerMatchResult{Name: rawPerformer, Match: performer.Name, Confidence: 75}, nil
-	} else if err != sql.ErrNoRows {
-		return PerformerMatchResult{}, err
-	}
-
-	// 3. Fuzzy match against Performers
-	performers, err := q.ListPerformers(ctx)
+	candidates, err := idx.Candidates(ctx, rawPerformer, n)
 	if err != nil {
 		return PerformerMatchResult{}, err
 	}
-	for _, p := range performers {
-		if metadata.IsFuzzyMatch(p.Name, normalized) {
-			return PerformerMatchResult{Name: rawPerformer, Match: p.Name, Confidence: 50}, nil
-		}
+	if len(candidates) == 0 {
+		return PerformerMatchResult{Name: rawPerformer, Match: "", Confidence: 0}, nil
 	}
+	return performerResult(rawPerformer, candidates), nil
+}
 
-	// 4. Fuzzy match against Performer Aliases
-	aliases, err := q.ListPerformerAliases(ctx)
-	if err != nil {
-		return PerformerMatchResult{}, err
+// Rerank re-sorts the candidates after their confidence has been changed, for example by
+// AddBoost, and updates the result to describe the new best candidate. The stage role,
+// slot and headliner of the performer are kept.
+func (r *PerformerMatchResult) Rerank() {
+	if len(r.Candidates) == 0 {
+		return
 	}
-	performerMap := make(map[int32]string)
-	for _, p := range performers {
-		performerMap[p.ID] = p.Name
+	metadata.SortCandidates(r.Candidates)
+	reranked := performerResult(r.Name, r.Candidates)
+	reranked.Pattern, reranked.Role = r.Pattern, r.Role
+	reranked.Slot, reranked.Headliner = r.Slot, r.Headliner
+	*r = reranked
+}
+
+// performerResult builds the result for rawPerformer from its sorted candidates.
+func performerResult(rawPerformer string, candidates []metadata.Candidate) PerformerMatchResult {
+	best := candidates[0]
+	contenders := metadata.Contenders(candidates)
+	result := PerformerMatchResult{
+		Name:        rawPerformer,
+		Match:       best.Match,
+		Confidence:  best.Confidence,
+		Score:       best.Score,
+		Explanation: best.Explanation,
+		Boost:       best.Boost,
+		Ambiguous:   contenders != nil,
+		Contenders:  contenders,
+		Candidates:  candidates,
 	}
-	for _, a := range aliases {
-		if metadata.IsFuzzyMatch(a.Alias, normalized) {
-			if name, ok := performerMap[a.Performer]; ok {
-				return PerformerMatchResult{Name: rawPerformer, Match: name, Confidence: 25}, nil
-			}
-		}
+	if best.Confidence == metadata.ExactConfidence {
+		result.Name = best.Match
 	}
-
-	return PerformerMatchResult{Name: rawPerformer, Match: "", Confidence: 0}, nil
+	return result
 }
 
 func MultiPerformerMatch(ctx context.Context, c metadata.ImagesConfig, rawPerformers string) ([]PerformerMatchResult, error) {
 	var results []PerformerMatchResult
 
-	match, err := PerformerMatch(ctx, c.Queries, rawPerformers)
+	match, err := PerformerMatch(ctx, c.Indexes.Performers, rawPerformers, c.Candidates)
 	if err != nil {
 		return nil, err
 	}
@@ -107,20 +158,25 @@ func MultiPerformerMatch(ctx context.Context, c metadata.ImagesConfig, rawPerfor
 		return len(patterns[i]) > len(patterns[j])
 	})
 
+	settings := c.Indexes.Performers.Settings()
+
 	for _, pattern := range patterns {
-		parts := metadata.SplitFuzzy(rawPerformers, pattern)
+		parts := settings.SplitFuzzy(rawPerformers, pattern)
 		if len(parts) > 1 {
 			for _, part := range parts {
 				part = strings.TrimSpace(part)
 				if part == "" {
 					continue
 				}
-				match, err := PerformerMatch(ctx, c.Queries, part)
+				match, err := PerformerMatch(ctx, c.Indexes.Performers, part, c.Candidates)
 				if err != nil {
 					return nil, err
 				}
 				if len(results) > 0 {
 					match.Pattern = pattern
+					if c.StageRoles != nil {
+						match.Role, _ = c.StageRoles.GetValue(pattern)
+					}
 				}
 				results = append(results, match)
 			}
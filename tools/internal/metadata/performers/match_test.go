@@ -2,7 +2,7 @@ package performers
 
 import (
 	"context"
-	"database/sql"
+	"slices"
 	"testing"
 	"time"
 
@@ -12,69 +12,330 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 )
 
+// newTestIndex builds a performer MatchIndex from in-memory names and aliases.
+func newTestIndex(t *testing.T, build func(d metadata.IndexData)) *metadata.MatchIndex {
+	t.Helper()
+	idx, err := metadata.NewMatchIndex(context.Background(),
+		func(context.Context) (time.Time, error) { return time.Now(), nil },
+		func(context.Context) (metadata.IndexData, error) {
+			d := make(metadata.IndexData)
+			build(d)
+			return d, nil
+		},
+	)
+	if err != nil {
+		t.Fatalf("failed to create MatchIndex: %v", err)
+	}
+	return idx
+}
+
+func TestNewPerformerIndex(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(`-- name: LastModifiedPerformers :one`).
+		WillReturnRows(sqlmock.NewRows([]string{"last_modified"}).AddRow(time.Now()))
+	mock.ExpectQuery(`-- name: ListPerformers :many`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "uuid", "created", "updated", "name", "phonetic"}).
+			AddRow(1, "00000000-0000-0000-0000-000000000001", time.Now(), time.Now(), "Alice", "ALS"))
+	mock.ExpectQuery(`-- name: ListPerformerAliases :many`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "uuid", "performer", "created", "updated", "alias", "phonetic"}).
+			AddRow(7, "00000000-0000-0000-0000-000000000007", 1, time.Now(), time.Now(), "Ally", ""))
+
+	idx, err := NewPerformerIndex(context.Background(), database.New(db))
+	if err != nil {
+		t.Fatalf("NewPerformerIndex() error = %v", err)
+	}
+
+	if c, _ := idx.Candidates(context.Background(), "Alice", 1); len(c) != 1 || c[0].ID != 1 || c[0].Confidence != metadata.ExactConfidence {
+		t.Errorf("Candidates(Alice) = %+v, want exact match on performer 1", c)
+	}
+	if c, _ := idx.Candidates(context.Background(), "Ally", 1); len(c) != 1 || c[0].Match != "Alice" || c[0].Confidence != metadata.AliasConfidence {
+		t.Errorf("Candidates(Ally) = %+v, want alias match on Alice", c)
+	}
+	// The alias has no stored phonetic key, so it is calculated when the index loads
+	if c, _ := idx.Candidates(context.Background(), "Alee", 1); len(c) != 1 || c[0].Match != "Alice" || c[0].Confidence != metadata.PhoneticConfidence {
+		t.Errorf("Candidates(Alee) = %+v, want phonetic match on Alice", c)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPerformerMatch(t *testing.T) {
+	idx := newTestIndex(t, func(d metadata.IndexData) {
+		d.AddName(1, "Alice Cooper")
+		d.AddName(2, "Bob Dylan")
+		d.AddAlias(2, "Bob Dylan", 10, "Robert Zimmerman")
+	})
+
+	tests := []struct {
+		name           string
+		rawPerformer   string
+		wantMatch      string
+		wantConfidence int
+		wantStage      metadata.Stage
+	}{
+		{name: "Exact", rawPerformer: "Alice Cooper", wantMatch: "Alice Cooper", wantConfidence: 100, wantStage: metadata.StageExact},
+		{name: "Exact with extra whitespace", rawPerformer: "  Alice   Cooper ", wantMatch: "Alice Cooper", wantConfidence: 100, wantStage: metadata.StageExact},
+		{name: "Exact after normalisation", rawPerformer: "ALICE COOPER!", wantMatch: "Alice Cooper", wantConfidence: 100, wantStage: metadata.StageExact},
+		{name: "Alias", rawPerformer: "Robert Zimmerman", wantMatch: "Bob Dylan", wantConfidence: 75, wantStage: metadata.StageAlias},
+		{name: "Fuzzy name", rawPerformer: "Alice Coper", wantMatch: "Alice Cooper", wantConfidence: 50, wantStage: metadata.StageFuzzyName},
+		{name: "Fuzzy alias", rawPerformer: "Robert Zimerman", wantMatch: "Bob Dylan", wantConfidence: 25, wantStage: metadata.StageFuzzyAlias},
+		{name: "No match", rawPerformer: "Charlie Parker", wantMatch: "", wantConfidence: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PerformerMatch(context.Background(), idx, tt.rawPerformer, 0)
+			if err != nil {
+				t.Fatalf("PerformerMatch() error = %v", err)
+			}
+			if got.Match != tt.wantMatch || got.Confidence != tt.wantConfidence {
+				t.Errorf("PerformerMatch(%q) = %q (%d), want %q (%d)", tt.rawPerformer, got.Match, got.Confidence, tt.wantMatch, tt.wantConfidence)
+			}
+			if tt.wantStage == "" {
+				if got.Explanation != nil {
+					t.Errorf("Explanation = %v, want none", got.Explanation)
+				}
+			} else if got.Explanation == nil || got.Explanation.Stage != tt.wantStage {
+				t.Errorf("Explanation = %v, want stage %s", got.Explanation, tt.wantStage)
+			}
+		})
+	}
+}
+
+func TestPerformerMatch_Explanation(t *testing.T) {
+	idx := newTestIndex(t, func(d metadata.IndexData) {
+		d.AddName(2, "Bob Dylan")
+		d.AddAlias(2, "Bob Dylan", 10, "Robert Zimmerman")
+	})
+
+	got, err := PerformerMatch(context.Background(), idx, "Robert  Zimerman", 0)
+	if err != nil {
+		t.Fatalf("PerformerMatch() error = %v", err)
+	}
+
+	want := metadata.Explanation{
+		Stage:    metadata.StageFuzzyAlias,
+		AliasID:  10,
+		Alias:    "Robert Zimmerman",
+		Distance: 1,
+		Input:    "robert zimerman",
+		Compared: "robert zimmerman",
+	}
+	if got.Explanation == nil || *got.Explanation != want {
+		t.Errorf("Explanation = %+v, want %+v", got.Explanation, want)
+	}
+	if s := got.Explanation.String(); s != `fuzzy-alias: "robert zimerman" vs "robert zimmerman", distance 1, alias 10 "Robert Zimmerman"` {
+		t.Errorf("Explanation.String() = %s", s)
+	}
+}
+
+func TestPerformerMatch_Phonetic(t *testing.T) {
+	idx := newTestIndex(t, func(d metadata.IndexData) {
+		d.AddPhoneticName(1, "Katherine Phillips", "")
+		d.AddPhoneticName(2, "Stephen Matthews", "")
+		d.AddPhoneticAlias(2, "Stephen Matthews", 10, "Steve Matthews", "")
+	})
+	idx.EnablePhonetic()
+
+	tests := []struct {
+		name           string
+		rawPerformer   string
+		wantMatch      string
+		wantConfidence int
+	}{
+		{name: "Sounds like name", rawPerformer: "Kathryn Filips", wantMatch: "Katherine Phillips", wantConfidence: metadata.PhoneticConfidence},
+		{name: "Sounds like alias", rawPerformer: "Steev Mathews", wantMatch: "Stephen Matthews", wantConfidence: metadata.PhoneticConfidence},
+		{name: "Fuzzy beats phonetic", rawPerformer: "Stephen Mathews", wantMatch: "Stephen Matthews", wantConfidence: metadata.FuzzyNameConfidence},
+		{name: "Different sound", rawPerformer: "Kathryn Hansen", wantMatch: "", wantConfidence: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PerformerMatch(context.Background(), idx, tt.rawPerformer, 0)
+			if err != nil {
+				t.Fatalf("PerformerMatch() error = %v", err)
+			}
+			if got.Match != tt.wantMatch || got.Confidence != tt.wantConfidence {
+				t.Errorf("PerformerMatch(%q) = %q (%d), want %q (%d)", tt.rawPerformer, got.Match, got.Confidence, tt.wantMatch, tt.wantConfidence)
+			}
+		})
+	}
+
+	// A large index searches for fuzzy matches by spelling, which finds none of these names,
+	// but still finds those that sound the same
+	idx.SetSearch(func(_ context.Context, name string, limit int32) ([]metadata.IndexEntry, error) {
+		return nil, nil
+	}, 0)
+	for _, tt := range tests {
+		if tt.wantConfidence != metadata.PhoneticConfidence {
+			continue
+		}
+		t.Run(tt.name+" when searching", func(t *testing.T) {
+			got, err := PerformerMatch(context.Background(), idx, tt.rawPerformer, 0)
+			if err != nil {
+				t.Fatalf("PerformerMatch() error = %v", err)
+			}
+			if got.Match != tt.wantMatch || got.Confidence != tt.wantConfidence {
+				t.Errorf("PerformerMatch(%q) = %q (%d), want %q (%d)", tt.rawPerformer, got.Match, got.Confidence, tt.wantMatch, tt.wantConfidence)
+			}
+		})
+	}
+}
+
+func TestPerformerMatch_RankedCandidates(t *testing.T) {
+	idx := newTestIndex(t, func(d metadata.IndexData) {
+		d.AddName(1, "Sophie Hunter")
+		d.AddName(2, "Sophie Hunte")
+		d.AddName(3, "Sophia Smith")
+		d.AddAlias(3, "Sophia Smith", 10, "Sophia Hunter")
+		d.AddName(4, "Bob Dylan")
+	})
+
+	// The alias match ranks below the two name matches so is cut by the limit
+	got, err := PerformerMatch(context.Background(), idx, "Sophie Huntr", 2)
+	if err != nil {
+		t.Fatalf("PerformerMatch() error = %v", err)
+	}
+
+	if len(got.Candidates) != 2 {
+		t.Fatalf("got %d candidates, want 2: %+v", len(got.Candidates), got.Candidates)
+	}
+	if got.Candidates[0].Score < got.Candidates[1].Score {
+		t.Errorf("candidates not ranked by score: %+v", got.Candidates)
+	}
+	if got.Match != got.Candidates[0].Match || got.Score != got.Candidates[0].Score {
+		t.Errorf("result %q (%.3f) does not reflect best candidate %+v", got.Match, got.Score, got.Candidates[0])
+	}
+	for _, c := range got.Candidates {
+		if c.Match == "Bob Dylan" {
+			t.Errorf("unrelated performer returned as candidate: %+v", c)
+		}
+	}
+}
+
+func TestPerformerMatch_SearchFunc(t *testing.T) {
+	idx := newTestIndex(t, func(d metadata.IndexData) {
+		d.AddName(1, "Alice Cooper")
+	})
+
+	// The search stands in for the pg_trgm query, returning a name missing from memory
+	var searched string
+	idx.SetSearch(func(_ context.Context, name string, limit int32) ([]metadata.IndexEntry, error) {
+		searched = name
+		return []metadata.IndexEntry{
+			{ID: 2, Name: "Sophie Hunter"},
+			{ID: 3, Name: "Bob Dylan"},
+		}, nil
+	}, 0)
+
+	got, err := PerformerMatch(context.Background(), idx, "Sofie Hunter", 0)
+	if err != nil {
+		t.Fatalf("PerformerMatch() error = %v", err)
+	}
+	if searched != metadata.Normalize("Sofie Hunter") {
+		t.Errorf("search called with %q, want normalized name", searched)
+	}
+	if got.Match != "Sophie Hunter" || got.Confidence != metadata.FuzzyNameConfidence || len(got.Candidates) != 1 {
+		t.Errorf("PerformerMatch() = %+v, want single fuzzy match on Sophie Hunter", got)
+	}
+
+	// Exact matches are still resolved from memory
+	got, err = PerformerMatch(context.Background(), idx, "Alice Cooper", 0)
+	if err != nil {
+		t.Fatalf("PerformerMatch() error = %v", err)
+	}
+	if got.Match != "Alice Cooper" || got.Confidence != metadata.ExactConfidence {
+		t.Errorf("PerformerMatch() = %+v, want exact match on Alice Cooper", got)
+	}
+}
+
+func TestPerformerMatch_Ambiguous(t *testing.T) {
+	idx := newTestIndex(t, func(d metadata.IndexData) {
+		d.AddName(1, "Sophie Hunter")
+		d.AddName(2, "Sophie Hunte")
+		d.AddName(3, "Bob Dylan")
+		d.AddName(4, "Bob Dylan")
+		d.AddName(5, "Alice Cooper")
+	})
+
+	tests := []struct {
+		name           string
+		rawPerformer   string
+		n              int
+		wantAmbiguous  bool
+		wantContenders []int32
+	}{
+		{name: "Two fuzzy matches", rawPerformer: "Sophie Huntr", n: 1, wantAmbiguous: true, wantContenders: []int32{1, 2}},
+		{name: "Two exact matches", rawPerformer: "Bob Dylan", n: 0, wantAmbiguous: true, wantContenders: []int32{3, 4}},
+		{name: "Single match", rawPerformer: "Alice Cooper", n: 0, wantAmbiguous: false},
+		{name: "No match", rawPerformer: "Charlie Parker", n: 0, wantAmbiguous: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PerformerMatch(context.Background(), idx, tt.rawPerformer, tt.n)
+			if err != nil {
+				t.Fatalf("PerformerMatch() error = %v", err)
+			}
+			if got.Ambiguous != tt.wantAmbiguous {
+				t.Errorf("Ambiguous = %v, want %v", got.Ambiguous, tt.wantAmbiguous)
+			}
+			var ids []int32
+			for _, c := range got.Contenders {
+				ids = append(ids, c.ID)
+			}
+			if len(ids) != len(tt.wantContenders) {
+				t.Fatalf("Contenders = %v, want %v", ids, tt.wantContenders)
+			}
+			for _, id := range tt.wantContenders {
+				if !slices.Contains(ids, id) {
+					t.Errorf("Contenders = %v, want %v", ids, tt.wantContenders)
+				}
+			}
+		})
+	}
+}
+
 func TestMultiPerformerMatch(t *testing.T) {
 	tests := []struct {
 		name          string
 		rawPerformers string
-		setupMock     func(mock sqlmock.Sqlmock)
 		wantCount     int
 		wantFirst     string
 		wantSecond    string
+		wantRole      metadata.StageRoleKind
 	}{
 		{
 			name:          "Splits using 'and'",
 			rawPerformers: "Alice and Bob",
-			setupMock: func(mock sqlmock.Sqlmock) {
-				// 1. Check full string "Alice and Bob" -> No match
-				// Helpers inlined or passed mock
-				mock.ExpectQuery(`-- name: GetPerformerByName :one`).WithArgs("Alice and Bob").WillReturnError(sql.ErrNoRows)
-				mock.ExpectQuery(`-- name: GetPerformerAliasByName :one`).WithArgs("Alice and Bob").WillReturnError(sql.ErrNoRows)
-				mock.ExpectQuery(`-- name: ListPerformers :many`).WillReturnRows(sqlmock.NewRows([]string{"id", "uuid", "created", "updated", "name"}))
-				mock.ExpectQuery(`-- name: ListPerformerAliases :many`).WillReturnRows(sqlmock.NewRows([]string{"id", "uuid", "created", "updated", "performer", "alias"}))
-
-				// 2. MultiPerformerMatch splits using " and " -> "Alice", "Bob"
-				// Check "Alice"
-				mock.ExpectQuery(`-- name: GetPerformerByName :one`).WithArgs("Alice").
-					WillReturnRows(sqlmock.NewRows([]string{"id", "uuid", "created", "updated", "name"}).AddRow(1, "00000000-0000-0000-0000-000000000001", time.Now(), time.Now(), "Alice"))
-				// Check "Bob"
-				mock.ExpectQuery(`-- name: GetPerformerByName :one`).WithArgs("Bob").
-					WillReturnRows(sqlmock.NewRows([]string{"id", "uuid", "created", "updated", "name"}).AddRow(2, "00000000-0000-0000-0000-000000000002", time.Now(), time.Now(), "Bob"))
-			},
-			wantCount:  2,
-			wantFirst:  "Alice",
-			wantSecond: "Bob",
+			wantCount:     2,
+			wantFirst:     "Alice",
+			wantSecond:    "Bob",
+			wantRole:      metadata.StageRoleSupport,
 		},
 		{
 			name:          "Splits using fuzzy 'wth'",
 			rawPerformers: "Alice wth Bob",
-			setupMock: func(mock sqlmock.Sqlmock) {
-				// 1. Check full string -> No match
-				mock.ExpectQuery(`-- name: GetPerformerByName :one`).WithArgs("Alice wth Bob").WillReturnError(sql.ErrNoRows)
-				mock.ExpectQuery(`-- name: GetPerformerAliasByName :one`).WithArgs("Alice wth Bob").WillReturnError(sql.ErrNoRows)
-				mock.ExpectQuery(`-- name: ListPerformers :many`).WillReturnRows(sqlmock.NewRows([]string{"id", "uuid", "created", "updated", "name"}))
-				mock.ExpectQuery(`-- name: ListPerformerAliases :many`).WillReturnRows(sqlmock.NewRows([]string{"id", "uuid", "created", "updated", "performer", "alias"}))
-
-				// 2. Splits using " with " (matched against " wth ") -> "Alice", "Bob"
-				mock.ExpectQuery(`-- name: GetPerformerByName :one`).WithArgs("Alice").
-					WillReturnRows(sqlmock.NewRows([]string{"id", "uuid", "created", "updated", "name"}).AddRow(1, "00000000-0000-0000-0000-000000000001", time.Now(), time.Now(), "Alice"))
-				mock.ExpectQuery(`-- name: GetPerformerByName :one`).WithArgs("Bob").
-					WillReturnRows(sqlmock.NewRows([]string{"id", "uuid", "created", "updated", "name"}).AddRow(2, "00000000-0000-0000-0000-000000000002", time.Now(), time.Now(), "Bob"))
-			},
-			wantCount:  2,
-			wantFirst:  "Alice",
-			wantSecond: "Bob",
+			wantCount:     2,
+			wantFirst:     "Alice",
+			wantSecond:    "Bob",
+			wantRole:      metadata.StageRoleGuest,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			db, mock, err := sqlmock.New()
-			if err != nil {
-				t.Fatalf("failed to open sqlmock: %v", err)
-			}
-			defer db.Close()
-
-			queries := database.New(db)
+			idx := newTestIndex(t, func(d metadata.IndexData) {
+				d.AddName(1, "Alice")
+				d.AddName(2, "Bob")
+			})
 
 			// Setup DBArray
 			patternsArray, err := dbcollection.NewDBArray(context.Background(),
@@ -87,12 +348,26 @@ func TestMultiPerformerMatch(t *testing.T) {
 				t.Fatalf("failed to create DBArray: %v", err)
 			}
 
-			cfg := metadata.ImagesConfig{
-				Queries:  queries,
-				Patterns: patternsArray,
+			stageRoles, err := dbcollection.NewDBMap(context.Background(),
+				func(context.Context) (time.Time, error) { return time.Now(), nil },
+				func(context.Context) (map[string]metadata.StageRoleKind, error) {
+					return map[string]metadata.StageRoleKind{
+						" and ":  metadata.StageRoleSupport,
+						" vs ":   metadata.StageRoleB2B,
+						" & ":    metadata.StageRoleSupport,
+						" with ": metadata.StageRoleGuest,
+					}, nil
+				},
+			)
+			if err != nil {
+				t.Fatalf("failed to create DBMap: %v", err)
 			}
 
-			tt.setupMock(mock)
+			cfg := metadata.ImagesConfig{
+				Patterns:   patternsArray,
+				StageRoles: stageRoles,
+				Indexes:    &metadata.MatchIndexes{Performers: idx},
+			}
 
 			results, err := MultiPerformerMatch(context.Background(), cfg, tt.rawPerformers)
 			if err != nil {
@@ -109,9 +384,8 @@ func TestMultiPerformerMatch(t *testing.T) {
 			if len(results) >= 2 && results[1].Name != tt.wantSecond {
 				t.Errorf("Result[1] = %s, want %s", results[1].Name, tt.wantSecond)
 			}
-
-			if err := mock.ExpectationsWereMet(); err != nil {
-				t.Errorf("there were unfulfilled expectations: %s", err)
+			if len(results) >= 2 && results[1].Role != tt.wantRole {
+				t.Errorf("Result[1].Role = %s, want %s", results[1].Role, tt.wantRole)
 			}
 		})
 	}
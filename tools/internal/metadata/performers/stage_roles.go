@@ -4,10 +4,13 @@ import (
 	"context"
 
 	"github.com/66james99/gig-calendar/internal/database"
+	"github.com/66james99/gig-calendar/internal/dbcollection"
+	"github.com/66james99/gig-calendar/internal/metadata"
 )
 
 type StageRole struct {
-	Pattern string `json:"pattern"`
+	Pattern string                 `json:"pattern"`
+	Kind    metadata.StageRoleKind `json:"kind"`
 }
 
 func GetStageRoles(ctx context.Context, q *database.Queries) ([]StageRole, error) {
@@ -18,8 +21,24 @@ func GetStageRoles(ctx context.Context, q *database.Queries) ([]StageRole, error
 
 	var roles []StageRole
 	for _, dbRole := range dbRoles {
-		roles = append(roles, StageRole{Pattern: dbRole.Pattern})
+		roles = append(roles, StageRole{Pattern: dbRole.Pattern, Kind: metadata.StageRoleKind(dbRole.Kind)})
 	}
 
 	return roles, nil
 }
+
+// NewStageRoleMap loads the kind of each stage role pattern into a DBMap for use as
+// ImagesConfig.StageRoles. It is refreshed along with the patterns themselves.
+func NewStageRoleMap(ctx context.Context, q *database.Queries) (*dbcollection.DBMap[string, metadata.StageRoleKind], error) {
+	return dbcollection.NewDBMap(ctx, q.LastModifiedPatternConsts, func(ctx context.Context) (map[string]metadata.StageRoleKind, error) {
+		rows, err := q.GetStageRoleKinds(ctx)
+		if err != nil {
+			return nil, err
+		}
+		kinds := make(map[string]metadata.StageRoleKind, len(rows))
+		for _, r := range rows {
+			kinds[r.Pattern] = metadata.StageRoleKind(r.Kind)
+		}
+		return kinds, nil
+	})
+}
@@ -0,0 +1,61 @@
+package promoters
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/66james99/gig-calendar/internal/database"
+	"github.com/66james99/gig-calendar/internal/metadata"
+)
+
+// InferFestival attaches an event to a festival when no promoter in its directory named
+// one, by looking for festivals that list venueID in festival_venue and were running on
+// date. This covers directories that only name the festival's stage or site.
+// The result is Inferred, with InferredConfidence, and is Ambiguous when several festivals
+// were running at the venue that day. ok is false when there was no such festival.
+func InferFestival(ctx context.Context, q *database.Queries, venueID int32, venueName string, date time.Time) (result PromoterMatchResult, ok bool, err error) {
+	festivals, err := q.ListFestivalsAtVenueOnDate(ctx, database.ListFestivalsAtVenueOnDateParams{Venue: venueID, Date: date})
+	if err != nil {
+		return PromoterMatchResult{}, false, err
+	}
+	if len(festivals) == 0 {
+		return PromoterMatchResult{}, false, nil
+	}
+
+	input := fmt.Sprintf("%s on %s", venueName, date.Format(time.DateOnly))
+	candidates := make([]PromoterCandidate, len(festivals))
+	for i, f := range festivals {
+		candidates[i] = PromoterCandidate{
+			Candidate: metadata.Candidate{
+				ID:         f.ID,
+				Match:      f.Name,
+				Confidence: metadata.InferredConfidence,
+				Score:      1,
+				Explanation: &metadata.Explanation{
+					Stage:    metadata.StageInferred,
+					Input:    input,
+					Compared: f.Name,
+				},
+			},
+			Festival: true,
+		}
+	}
+
+	best := candidates[0]
+	result = PromoterMatchResult{
+		Name:        venueName,
+		Match:       best.Match,
+		Confidence:  best.Confidence,
+		Score:       best.Score,
+		Festival:    true,
+		Inferred:    true,
+		Explanation: best.Explanation,
+		Candidates:  candidates,
+	}
+	if len(candidates) > 1 {
+		result.Ambiguous = true
+		result.Contenders = candidates
+	}
+	return result, true, nil
+}
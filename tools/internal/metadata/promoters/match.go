@@ -0,0 +1,194 @@
+package promoters
+
+import (
+	"context"
+	"sort"
+
+	"github.com/66james99/gig-calendar/internal/database"
+	"github.com/66james99/gig-calendar/internal/metadata"
+)
+
+// MatchResult holds the result of a promoter matching operation.
+type PromoterMatchResult struct {
+	Name        string                `json:"name"`
+	Match       string                `json:"match"`
+	Confidence  int                   `json:"confidence"`
+	Score       float64               `json:"score"`
+	Promoter    bool                  `json:"promoter"`
+	Festival    bool                  `json:"festival"`
+	Inferred    bool                  `json:"inferred,omitempty"` // Festival found from the venue and date by InferFestival
+	Explanation *metadata.Explanation `json:"explanation,omitempty"`
+	Ambiguous   bool                  `json:"ambiguous,omitempty"`
+	Contenders  []PromoterCandidate   `json:"contenders,omitempty"`
+	Candidates  []PromoterCandidate   `json:"candidates,omitempty"`
+}
+
+// PromoterCandidate is a possible promoter or festival match for a raw promoter name.
+type PromoterCandidate struct {
+	metadata.Candidate
+	Promoter bool `json:"promoter"`
+	Festival bool `json:"festival"`
+}
+
+// NewPromoterIndex loads the promoter and promoter_alias tables into a MatchIndex for use by PromoterMatch.
+func NewPromoterIndex(ctx context.Context, q *database.Queries) (*metadata.MatchIndex, error) {
+	idx, err := metadata.NewMatchIndex(ctx, q.LastModifiedPromoters, func(ctx context.Context) (metadata.IndexData, error) {
+		promoters, err := q.ListPromoters(ctx)
+		if err != nil {
+			return nil, err
+		}
+		aliases, err := q.ListPromoterAliases(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		data := make(metadata.IndexData)
+		promoterMap := make(map[int32]string)
+		for _, p := range promoters {
+			data.AddName(p.ID, p.Name)
+			promoterMap[p.ID] = p.Name
+		}
+		for _, a := range aliases {
+			if name, ok := promoterMap[a.Promoter]; ok {
+				data.AddAlias(a.Promoter, name, a.ID, a.Alias)
+			}
+		}
+		return data, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Large tables use the pg_trgm candidate search for the fuzzy stage
+	idx.SetSearch(func(ctx context.Context, name string, limit int32) ([]metadata.IndexEntry, error) {
+		rows, err := q.SearchPromoterCandidates(ctx, database.SearchPromoterCandidatesParams{Query: name, MaxResults: limit})
+		if err != nil {
+			return nil, err
+		}
+		entries := make([]metadata.IndexEntry, len(rows))
+		for i, r := range rows {
+			entries[i] = metadata.IndexEntry{ID: r.ID, Name: r.Name, AliasID: r.AliasID, Alias: r.Alias}
+		}
+		return entries, nil
+	}, metadata.SearchThreshold)
+	return idx, nil
+}
+
+// NewFestivalIndex loads the festival and festival_alias tables into a MatchIndex for use by PromoterMatch.
+func NewFestivalIndex(ctx context.Context, q *database.Queries) (*metadata.MatchIndex, error) {
+	idx, err := metadata.NewMatchIndex(ctx, q.LastModifiedFestivals, func(ctx context.Context) (metadata.IndexData, error) {
+		festivals, err := q.ListFestivals(ctx)
+		if err != nil {
+			return nil, err
+		}
+		aliases, err := q.ListFestivalAliases(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		data := make(metadata.IndexData)
+		festivalMap := make(map[int32]string)
+		for _, f := range festivals {
+			data.AddName(f.ID, f.Name)
+			festivalMap[f.ID] = f.Name
+		}
+		for _, a := range aliases {
+			if name, ok := festivalMap[a.Festival]; ok {
+				data.AddAlias(a.Festival, name, a.ID, a.Alias)
+			}
+		}
+		return data, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Large tables use the pg_trgm candidate search for the fuzzy stage
+	idx.SetSearch(func(ctx context.Context, name string, limit int32) ([]metadata.IndexEntry, error) {
+		rows, err := q.SearchFestivalCandidates(ctx, database.SearchFestivalCandidatesParams{Query: name, MaxResults: limit})
+		if err != nil {
+			return nil, err
+		}
+		entries := make([]metadata.IndexEntry, len(rows))
+		for i, r := range rows {
+			entries[i] = metadata.IndexEntry{ID: r.ID, Name: r.Name, AliasID: r.AliasID, Alias: r.Alias}
+		}
+		return entries, nil
+	}, metadata.SearchThreshold)
+	return idx, nil
+}
+
+// Match checks for the existence of a promoter or festival in the indexes.
+// It returns the best of up to n ranked candidates, with a confidence score of, unless
+// tuned in the promoter row of match_setting:
+// 100: Exact match in promoter table
+// 75:  Match in promoter_alias table
+// 50:  Fuzzy match against promoter table
+// 25:  Fuzzy match against promoter_alias table
+// 0:   No match
+// Setting Promoter or Festival to true depending on which the match was with.
+// Where a promoter and a festival rank equally the promoter is preferred.
+// The result is Ambiguous when several promoters or festivals were found at the best stage
+// with scores within metadata.ContenderMargin of each other, and these are listed as Contenders.
+func PromoterMatch(ctx context.Context, promoterIdx, festivalIdx *metadata.MatchIndex, rawpromoter string, n int) (PromoterMatchResult, error) {
+	if promoterIdx.Key(rawpromoter) == "" {
+		return PromoterMatchResult{Confidence: 0, Promoter: false, Festival: false}, nil
+	}
+	if n <= 0 {
+		n = metadata.DefaultCandidates
+	}
+
+	promoterCandidates, err := promoterIdx.Candidates(ctx, rawpromoter, n)
+	if err != nil {
+		return PromoterMatchResult{}, err
+	}
+	festivalCandidates, err := festivalIdx.Candidates(ctx, rawpromoter, n)
+	if err != nil {
+		return PromoterMatchResult{}, err
+	}
+
+	var candidates []PromoterCandidate
+	for _, c := range promoterCandidates {
+		candidates = append(candidates, PromoterCandidate{Candidate: c, Promoter: true})
+	}
+	for _, c := range festivalCandidates {
+		candidates = append(candidates, PromoterCandidate{Candidate: c, Festival: true})
+	}
+	if len(candidates) == 0 {
+		return PromoterMatchResult{Name: rawpromoter, Match: "", Confidence: 0, Promoter: false, Festival: false}, nil
+	}
+
+	// Each list is already ranked, so a stable sort on confidence and score keeps
+	// promoters ahead of festivals that rank equally.
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Confidence != candidates[j].Confidence {
+			return candidates[i].Confidence > candidates[j].Confidence
+		}
+		return candidates[i].Score > candidates[j].Score
+	})
+	ranked := make([]metadata.Candidate, len(candidates))
+	for i, c := range candidates {
+		ranked[i] = c.Candidate
+	}
+	contenders := max(1, len(metadata.Contenders(ranked)))
+	if len(candidates) > n {
+		candidates = candidates[:max(n, contenders)]
+	}
+
+	best := candidates[0]
+	result := PromoterMatchResult{
+		Name:        rawpromoter,
+		Match:       best.Match,
+		Confidence:  best.Confidence,
+		Score:       best.Score,
+		Promoter:    best.Promoter,
+		Festival:    best.Festival,
+		Explanation: best.Explanation,
+		Candidates:  candidates,
+	}
+	if contenders > 1 {
+		result.Ambiguous = true
+		result.Contenders = candidates[:contenders]
+	}
+	return result, nil
+}
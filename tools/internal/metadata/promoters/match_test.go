@@ -0,0 +1,74 @@
+package promoters
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/66james99/gig-calendar/internal/metadata"
+)
+
+// newTestIndex builds a MatchIndex from in-memory names and aliases.
+func newTestIndex(t *testing.T, build func(d metadata.IndexData)) *metadata.MatchIndex {
+	t.Helper()
+	idx, err := metadata.NewMatchIndex(context.Background(),
+		func(context.Context) (time.Time, error) { return time.Now(), nil },
+		func(context.Context) (metadata.IndexData, error) {
+			d := make(metadata.IndexData)
+			build(d)
+			return d, nil
+		},
+	)
+	if err != nil {
+		t.Fatalf("failed to create MatchIndex: %v", err)
+	}
+	return idx
+}
+
+func TestPromoterMatch_Contenders(t *testing.T) {
+	festivalIdx := newTestIndex(t, func(d metadata.IndexData) {
+		d.AddName(10, "Field Day")
+	})
+
+	tests := []struct {
+		name           string
+		promoters      []string
+		rawPromoter    string
+		wantAmbiguous  bool
+		wantContenders []int32
+	}{
+		{name: "Exact promoter", promoters: []string{"Eat Your Own Ears", "Eat Your Own Earz"}, rawPromoter: "Eat Your Own Ears"},
+		{
+			name:      "Close fuzzy promoters",
+			promoters: []string{"Eat Your Own Ears", "Eat Your Own Earz", "Heat Your Own Bears"}, rawPromoter: "Eat Your Own Earx",
+			wantAmbiguous: true, wantContenders: []int32{1, 2},
+		},
+		// Both are fuzzy name matches, but scoring 0.95 and 0.87 the first is clearly better
+		{name: "Clear fuzzy winner", promoters: []string{"Eat Your Own Ears", "Heat Your Own Bears"}, rawPromoter: "Eat Your Own Earx"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			promoterIdx := newTestIndex(t, func(d metadata.IndexData) {
+				for i, name := range tt.promoters {
+					d.AddName(int32(i+1), name)
+				}
+			})
+			got, err := PromoterMatch(context.Background(), promoterIdx, festivalIdx, tt.rawPromoter, 0)
+			if err != nil {
+				t.Fatalf("PromoterMatch() error = %v", err)
+			}
+			if got.Ambiguous != tt.wantAmbiguous {
+				t.Errorf("Ambiguous = %v, want %v", got.Ambiguous, tt.wantAmbiguous)
+			}
+			if len(got.Contenders) != len(tt.wantContenders) {
+				t.Fatalf("Contenders = %v, want IDs %v", got.Contenders, tt.wantContenders)
+			}
+			for i, c := range got.Contenders {
+				if c.ID != tt.wantContenders[i] {
+					t.Errorf("Contenders[%d].ID = %d, want %d", i, c.ID, tt.wantContenders[i])
+				}
+			}
+		})
+	}
+}
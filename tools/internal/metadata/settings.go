@@ -0,0 +1,159 @@
+package metadata
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/66james99/gig-calendar/internal/database"
+	"github.com/66james99/gig-calendar/internal/dbcollection"
+)
+
+// Entity types that MatchSettings are held for, as keyed in the match_setting table.
+// The festival index uses the promoter settings.
+const (
+	EntityPerformer = "performer"
+	EntityVenue     = "venue"
+	EntityPromoter  = "promoter"
+)
+
+// EntityTypes lists the entity types that MatchSettings are held for.
+var EntityTypes = []string{EntityPerformer, EntityVenue, EntityPromoter}
+
+// MatchSettings tunes how strictly names are matched for one type of entity. An exact
+// match on an entity's own name always has ExactConfidence; the other confidences must
+// rank below it in the order of the stages that find them.
+type MatchSettings struct {
+	AliasConfidence      int     `json:"alias_confidence"`
+	FuzzyNameConfidence  int     `json:"fuzzy_name_confidence"`
+	FuzzyAliasConfidence int     `json:"fuzzy_alias_confidence"`
+	PhoneticConfidence   int     `json:"phonetic_confidence"`
+	MinSimilarity        float64 `json:"min_similarity"`  // Lowest Similarity score accepted as a fuzzy match
+	ShortLength          int     `json:"short_length"`    // Longest separator split on within ShortMaxEdits
+	ShortMaxEdits        int     `json:"short_max_edits"` // Edits allowed when splitting on short separators
+	MaxEdits             int     `json:"max_edits"`       // Edits allowed when splitting on longer separators
+}
+
+// DefaultMatchSettings are the MatchSettings used for entity types with no stored settings.
+var DefaultMatchSettings = MatchSettings{
+	AliasConfidence:      AliasConfidence,
+	FuzzyNameConfidence:  FuzzyNameConfidence,
+	FuzzyAliasConfidence: FuzzyAliasConfidence,
+	PhoneticConfidence:   PhoneticConfidence,
+	MinSimilarity:        MinSimilarity,
+	ShortLength:          5,
+	ShortMaxEdits:        1,
+	MaxEdits:             2,
+}
+
+// SettingError reports a MatchSettings field that breaks a constraint of the
+// match_setting table, or a HistoryBoost amount that is out of range.
+type SettingError struct {
+	Field   string // JSON name of the field, or flag name of the boost
+	Message string
+}
+
+func (e *SettingError) Error() string {
+	return e.Field + " " + e.Message
+}
+
+// Validate checks the settings against the constraints of the match_setting table,
+// returning a *SettingError for the first field that breaks one.
+func (s MatchSettings) Validate() error {
+	confidences := []struct {
+		field string
+		value int
+	}{
+		{"alias_confidence", s.AliasConfidence},
+		{"fuzzy_name_confidence", s.FuzzyNameConfidence},
+		{"fuzzy_alias_confidence", s.FuzzyAliasConfidence},
+		{"phonetic_confidence", s.PhoneticConfidence},
+	}
+	above, aboveName := ExactConfidence, "the exact match confidence"
+	for _, c := range confidences {
+		if c.value >= above {
+			return &SettingError{Field: c.field, Message: fmt.Sprintf("must be below %s of %d", aboveName, above)}
+		}
+		above, aboveName = c.value, c.field
+	}
+	if s.PhoneticConfidence <= 0 {
+		return &SettingError{Field: "phonetic_confidence", Message: "must be above 0"}
+	}
+	if s.MinSimilarity <= 0 || s.MinSimilarity > 1 {
+		return &SettingError{Field: "min_similarity", Message: "must be above 0 and at most 1"}
+	}
+	if s.ShortLength < 0 {
+		return &SettingError{Field: "short_length", Message: "must not be negative"}
+	}
+	if s.ShortMaxEdits < 0 {
+		return &SettingError{Field: "short_max_edits", Message: "must not be negative"}
+	}
+	if s.MaxEdits < s.ShortMaxEdits {
+		return &SettingError{Field: "max_edits", Message: "must be at least short_max_edits"}
+	}
+	return nil
+}
+
+// stageConfidences returns the confidences of the stages that match names, best first.
+func (s MatchSettings) stageConfidences() []int {
+	return []int{ExactConfidence, s.AliasConfidence, s.FuzzyNameConfidence, s.FuzzyAliasConfidence, s.PhoneticConfidence}
+}
+
+// StageGap returns the smallest gap between the confidences of adjacent stages.
+func (s MatchSettings) StageGap() int {
+	confidences := s.stageConfidences()
+	gap := ExactConfidence
+	for i := 1; i < len(confidences); i++ {
+		gap = min(gap, confidences[i-1]-confidences[i])
+	}
+	return gap
+}
+
+// StageCeiling returns the confidence of the stage ranking next above one with confidence,
+// or ExactConfidence when there is none.
+func (s MatchSettings) StageCeiling(confidence int) int {
+	ceiling := ExactConfidence
+	for _, c := range s.stageConfidences() {
+		if c > confidence {
+			ceiling = min(ceiling, c)
+		}
+	}
+	return ceiling
+}
+
+// maxEdits returns the number of edits allowed when splitting on a separator of n characters.
+func (s MatchSettings) maxEdits(n int) int {
+	if n <= s.ShortLength {
+		return s.ShortMaxEdits
+	}
+	return s.MaxEdits
+}
+
+// MatchSettingsFromDB converts a match_setting row to MatchSettings.
+func MatchSettingsFromDB(row database.MatchSetting) MatchSettings {
+	return MatchSettings{
+		AliasConfidence:      int(row.AliasConfidence),
+		FuzzyNameConfidence:  int(row.FuzzyNameConfidence),
+		FuzzyAliasConfidence: int(row.FuzzyAliasConfidence),
+		PhoneticConfidence:   int(row.PhoneticConfidence),
+		MinSimilarity:        row.MinSimilarity,
+		ShortLength:          int(row.ShortLength),
+		ShortMaxEdits:        int(row.ShortMaxEdits),
+		MaxEdits:             int(row.MaxEdits),
+	}
+}
+
+// NewMatchSettingsMap loads the match_setting table into a DBMap keyed by entity type, for
+// use with MatchIndex.SetSettings. It is reloaded when the table changes.
+func NewMatchSettingsMap(ctx context.Context, q *database.Queries) (*dbcollection.DBMap[string, MatchSettings], error) {
+	return dbcollection.NewDBMap(ctx, q.LastModifiedMatchSettings, func(ctx context.Context) (map[string]MatchSettings, error) {
+		rows, err := q.ListMatchSettings(ctx)
+		if err != nil {
+			return nil, err
+		}
+		settings := make(map[string]MatchSettings, len(rows))
+		for _, r := range rows {
+			settings[r.EntityType] = MatchSettingsFromDB(r)
+		}
+		return settings, nil
+	})
+}
@@ -0,0 +1,116 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/66james99/gig-calendar/internal/dbcollection"
+)
+
+func TestMatchSettings_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		modify    func(s *MatchSettings)
+		wantField string // field of the expected *SettingError, empty for none
+	}{
+		{name: "Defaults", modify: func(s *MatchSettings) {}},
+		{name: "Stricter", modify: func(s *MatchSettings) { s.MinSimilarity = 0.95; s.MaxEdits = 1 }},
+		{name: "Alias as exact", modify: func(s *MatchSettings) { s.AliasConfidence = ExactConfidence }, wantField: "alias_confidence"},
+		{name: "Fuzzy above alias", modify: func(s *MatchSettings) { s.FuzzyNameConfidence = 80 }, wantField: "fuzzy_name_confidence"},
+		{name: "Phonetic zero", modify: func(s *MatchSettings) { s.PhoneticConfidence = 0 }, wantField: "phonetic_confidence"},
+		{name: "No similarity", modify: func(s *MatchSettings) { s.MinSimilarity = 0 }, wantField: "min_similarity"},
+		{name: "Negative short length", modify: func(s *MatchSettings) { s.ShortLength = -1 }, wantField: "short_length"},
+		{name: "Fewer long edits", modify: func(s *MatchSettings) { s.ShortMaxEdits = 2; s.MaxEdits = 1 }, wantField: "max_edits"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := DefaultMatchSettings
+			tt.modify(&s)
+			err := s.Validate()
+			if tt.wantField == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			var settingErr *SettingError
+			if !errors.As(err, &settingErr) || settingErr.Field != tt.wantField {
+				t.Errorf("Validate() error = %v, want a SettingError for %s", err, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestMatchSettings_SplitFuzzy(t *testing.T) {
+	strict := DefaultMatchSettings
+	strict.ShortMaxEdits, strict.MaxEdits = 0, 0
+	long := DefaultMatchSettings
+	long.ShortLength = 6
+
+	tests := []struct {
+		name     string
+		settings MatchSettings
+		s        string
+		sep      string
+		want     []string
+	}{
+		{name: "Default allows a typo", settings: DefaultMatchSettings, s: "Alice wth Bob", sep: " with ", want: []string{"Alice", "Bob"}},
+		{name: "Strict needs the separator", settings: strict, s: "Alice wth Bob", sep: " with ", want: []string{"Alice wth Bob"}},
+		{name: "Longer short separators", settings: long, s: "Alice wh Bob", sep: " with ", want: []string{"Alice wh Bob"}},
+		{name: "Default allows two edits", settings: DefaultMatchSettings, s: "Alice wh Bob", sep: " with ", want: []string{"Alice", "Bob"}},
+		{name: "Strict still splits", settings: strict, s: "Alice with Bob", sep: " with ", want: []string{"Alice", "Bob"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.settings.SplitFuzzy(tt.s, tt.sep); !slices.Equal(got, tt.want) {
+				t.Errorf("SplitFuzzy(%q, %q) = %q, want %q", tt.s, tt.sep, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchIndex_SetSettings(t *testing.T) {
+	ctx := context.Background()
+	lastModified := func(context.Context) (time.Time, error) { return time.Now(), nil }
+	idx, err := NewMatchIndex(ctx, lastModified, func(context.Context) (IndexData, error) {
+		d := make(IndexData)
+		d.AddName(1, "Alice Cooper")
+		d.AddAlias(1, "Alice Cooper", 1, "Vincent Furnier")
+		return d, nil
+	})
+	if err != nil {
+		t.Fatalf("failed to create MatchIndex: %v", err)
+	}
+
+	tuned := DefaultMatchSettings
+	tuned.AliasConfidence = 90
+	tuned.MinSimilarity = 0.99
+	settings, err := dbcollection.NewDBMap(ctx, lastModified, func(context.Context) (map[string]MatchSettings, error) {
+		return map[string]MatchSettings{EntityPerformer: tuned}, nil
+	})
+	if err != nil {
+		t.Fatalf("failed to create DBMap: %v", err)
+	}
+
+	// Settings for another entity type leave the index on the defaults
+	idx.SetSettings(settings, EntityVenue)
+	if got := idx.Settings(); got != DefaultMatchSettings {
+		t.Errorf("Settings() = %+v, want DefaultMatchSettings", got)
+	}
+	if c, _ := idx.Candidates(ctx, "Alice Coopr", 1); len(c) != 1 || c[0].Confidence != FuzzyNameConfidence {
+		t.Errorf("Candidates(Alice Coopr) = %+v, want a fuzzy name match", c)
+	}
+
+	idx.SetSettings(settings, EntityPerformer)
+	if c, _ := idx.Candidates(ctx, "Vincent Furnier", 1); len(c) != 1 || c[0].Confidence != 90 {
+		t.Errorf("Candidates(Vincent Furnier) = %+v, want alias confidence 90", c)
+	}
+	if c, _ := idx.Candidates(ctx, "Alice Coopr", 1); len(c) != 0 {
+		t.Errorf("Candidates(Alice Coopr) = %+v, want none above MinSimilarity 0.99", c)
+	}
+}
@@ -0,0 +1,32 @@
+package metadata
+
+// StageRoleKind describes how a performer joined the one named before them on stage,
+// as recorded by the stage role pattern that separated their names.
+type StageRoleKind string
+
+const (
+	StageRoleSupport        StageRoleKind = "support"
+	StageRoleGuest          StageRoleKind = "guest"
+	StageRoleB2B            StageRoleKind = "b2b"
+	StageRoleInConversation StageRoleKind = "in-conversation"
+	StageRoleFeaturing      StageRoleKind = "featuring"
+)
+
+// StageRoleKinds lists every kind accepted by the stage_role and event_performer tables.
+var StageRoleKinds = []StageRoleKind{
+	StageRoleSupport,
+	StageRoleGuest,
+	StageRoleB2B,
+	StageRoleInConversation,
+	StageRoleFeaturing,
+}
+
+// ValidStageRoleKind reports whether kind is one of StageRoleKinds.
+func ValidStageRoleKind(kind string) bool {
+	for _, k := range StageRoleKinds {
+		if string(k) == kind {
+			return true
+		}
+	}
+	return false
+}
@@ -2,6 +2,7 @@ package venues
 
 import (
 	"context"
+	"sort"
 
 	"github.com/66james99/gig-calendar/internal/database"
 	"github.com/66james99/gig-calendar/internal/metadata"
@@ -9,71 +10,149 @@ import (
 
 // MatchResult holds the result of a venue matching operation.
 type VenueMatchResult struct {
-	Name string `json:"name"`
-	Match string `json:"match"`
-	Confidence int `json:"confidence"`
+	Name        string                `json:"name"`
+	City        string                `json:"city,omitempty"` // City used to choose between venues with the same name
+	Match       string                `json:"match"`
+	Confidence  int                   `json:"confidence"`
+	Score       float64               `json:"score"`
+	Explanation *metadata.Explanation `json:"explanation,omitempty"`
+	Boost       *metadata.Boost       `json:"boost,omitempty"`
+	Ambiguous   bool                  `json:"ambiguous,omitempty"`
+	Contenders  []metadata.Candidate  `json:"contenders,omitempty"`
+	Candidates  []metadata.Candidate  `json:"candidates,omitempty"`
 }
 
-// Match checks for the existence of a venue in the database.
-// It returns a confidence score:
+// NewVenueIndex loads the venue and venue_alias tables into a MatchIndex for use by VenueMatch.
+func NewVenueIndex(ctx context.Context, q *database.Queries) (*metadata.MatchIndex, error) {
+	idx, err := metadata.NewMatchIndex(ctx, q.LastModifiedVenues, func(ctx context.Context) (metadata.IndexData, error) {
+		venues, err := q.ListVenues(ctx)
+		if err != nil {
+			return nil, err
+		}
+		aliases, err := q.ListVenueAliases(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		data := make(metadata.IndexData)
+		venueMap := make(map[int32]database.Venue)
+		for _, v := range venues {
+			data.Add(metadata.IndexEntry{ID: v.ID, Name: v.Name, City: v.City})
+			venueMap[v.ID] = v
+		}
+		for _, a := range aliases {
+			if v, ok := venueMap[a.Venue]; ok {
+				data.Add(metadata.IndexEntry{ID: v.ID, Name: v.Name, AliasID: a.ID, Alias: a.Alias, City: v.City})
+			}
+		}
+		return data, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Large tables use the pg_trgm candidate search for the fuzzy stage
+	idx.SetSearch(func(ctx context.Context, name string, limit int32) ([]metadata.IndexEntry, error) {
+		rows, err := q.SearchVenueCandidates(ctx, database.SearchVenueCandidatesParams{Query: name, MaxResults: limit})
+		if err != nil {
+			return nil, err
+		}
+		entries := make([]metadata.IndexEntry, len(rows))
+		for i, r := range rows {
+			entries[i] = metadata.IndexEntry{ID: r.ID, Name: r.Name, AliasID: r.AliasID, Alias: r.Alias, City: r.City}
+		}
+		return entries, nil
+	}, metadata.SearchThreshold)
+	return idx, nil
+}
+
+// Match checks for the existence of a venue in the index.
+// It returns the best of up to n ranked candidates, with a confidence score of, unless
+// tuned in the venue row of match_setting:
 // 100: Exact match in venue table
 // 75:  Match in venue_alias table
 // 50:  Fuzzy match against venue table
 // 25:  Fuzzy match against venue_alias table
 // 0:   No match
-func VenueMatch(ctx context.Context, q *database.Queries, rawVenue string) (VenueMatchResult, error) {
-	normalized := metadata.Normalize(rawVenue)
-	if normalized == "" {
+// Score grades how similar the venue name was to the best candidate.
+// When city is given, venues in that city have CityBonus added to their score when ranked
+// against others found at the same stage.
+// The result is Ambiguous when several venues were found at the best stage with scores
+// within metadata.ContenderMargin of each other, and these are listed as Contenders; only
+// venues in the given city contend when the best one is there.
+func VenueMatch(ctx context.Context, idx *metadata.MatchIndex, rawVenue string, city string, n int) (VenueMatchResult, error) {
+	if idx.Key(rawVenue) == "" {
 		return VenueMatchResult{Confidence: 0}, nil
 	}
 
-	// 1. Exact match in Venue table
-	venues, err := q.ListVenues(ctx)
+	candidates, err := idx.Candidates(ctx, rawVenue, n)
 	if err != nil {
 		return VenueMatchResult{}, err
 	}
-
-	for _, v := range venues {
-		if v.Name == normalized {
-			return VenueMatchResult{Name: rawVenue, Match: v.Name, Confidence: 100}, nil
-		}
+	if len(candidates) == 0 {
+		return VenueMatchResult{Name: rawVenue, City: city, Match: "", Confidence: 0}, nil
 	}
+	return venueResult(rawVenue, city, candidates), nil
+}
 
-	// 2. Match in Venue Alias table
-	aliases, err := q.ListVenueAliases(ctx)
-	if err != nil {
-		return VenueMatchResult{}, err
+// Rerank re-sorts the candidates after their confidence has been changed, for example by
+// AddBoost, and updates the result to describe the new best candidate.
+func (r *VenueMatchResult) Rerank() {
+	if len(r.Candidates) == 0 {
+		return
 	}
+	metadata.SortCandidates(r.Candidates)
+	*r = venueResult(r.Name, r.City, r.Candidates)
+}
 
-	venueMap := make(map[int32]string)
-	for _, v := range venues {
-		venueMap[v.ID] = v.Name
-	}
+// CityBonus is added to the Similarity score of venues in the given city when ranking
+// candidates found at the same stage, so a local venue wins over one elsewhere that is a
+// contender for the name but not over one that matched it clearly better.
+const CityBonus = metadata.ContenderMargin
 
-	for _, a := range aliases {
-		if a.Alias == normalized {
-			if name, ok := venueMap[a.Venue]; ok {
-				return VenueMatchResult{Name: rawVenue, Match: name, Confidence: 75}, nil
+// venueResult builds the result for rawVenue from its sorted candidates.
+func venueResult(rawVenue string, city string, candidates []metadata.Candidate) VenueMatchResult {
+	contenders := metadata.Contenders(candidates)
+	if cityKey := metadata.MatchKey(city); cityKey != "" {
+		inCity := func(c metadata.Candidate) bool {
+			return metadata.MatchKey(c.City) == cityKey
+		}
+		score := func(c metadata.Candidate) float64 {
+			if inCity(c) {
+				return c.Score + CityBonus
 			}
+			return c.Score
 		}
-	}
+		sort.SliceStable(candidates, func(i, j int) bool {
+			if candidates[i].Confidence != candidates[j].Confidence {
+				return candidates[i].Confidence > candidates[j].Confidence
+			}
+			return score(candidates[i]) > score(candidates[j])
+		})
 
-	// 3. Fuzzy match
-	// We check against Venues using Levenshtein distance
-	for _, v := range venues {
-		if metadata.IsFuzzyMatch(v.Name, normalized) {
-			return VenueMatchResult{Name: rawVenue, Match: v.Name, Confidence: 50}, nil
+		// A local best venue only contends with other local venues
+		best, n := candidates[0], 0
+		for n < len(candidates) && candidates[n].Confidence == best.Confidence &&
+			best.Score-candidates[n].Score <= metadata.ContenderMargin && inCity(candidates[n]) == inCity(best) {
+			n++
 		}
-	}
-
-	// 4. Fuzzy match against Venue Aliases
-	for _, a := range aliases {
-		if metadata.IsFuzzyMatch(a.Alias, normalized) {
-			if name, ok := venueMap[a.Venue]; ok {
-				return VenueMatchResult{Name: rawVenue, Match: name, Confidence: 25}, nil
-			}
+		contenders = nil
+		if n > 1 {
+			contenders = candidates[:n]
 		}
 	}
 
-	return VenueMatchResult{Name: rawVenue, Match: "", Confidence: 0}, nil
+	best := candidates[0]
+	return VenueMatchResult{
+		Name:        rawVenue,
+		City:        city,
+		Match:       best.Match,
+		Confidence:  best.Confidence,
+		Score:       best.Score,
+		Explanation: best.Explanation,
+		Boost:       best.Boost,
+		Ambiguous:   contenders != nil,
+		Contenders:  contenders,
+		Candidates:  candidates,
+	}
 }
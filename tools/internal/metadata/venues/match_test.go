@@ -0,0 +1,93 @@
+package venues
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/66james99/gig-calendar/internal/metadata"
+)
+
+// newTestIndex builds a MatchIndex from in-memory venues.
+func newTestIndex(t *testing.T, build func(d metadata.IndexData)) *metadata.MatchIndex {
+	t.Helper()
+	idx, err := metadata.NewMatchIndex(context.Background(),
+		func(context.Context) (time.Time, error) { return time.Now(), nil },
+		func(context.Context) (metadata.IndexData, error) {
+			d := make(metadata.IndexData)
+			build(d)
+			return d, nil
+		},
+	)
+	if err != nil {
+		t.Fatalf("failed to create MatchIndex: %v", err)
+	}
+	return idx
+}
+
+func TestVenueMatch_City(t *testing.T) {
+	type venue struct{ name, city string }
+	garages := []venue{{"The Garage", "London"}, {"The Garage", "Glasgow"}}
+
+	tests := []struct {
+		name           string
+		venues         []venue
+		rawVenue       string
+		city           string
+		wantID         int32
+		wantAmbiguous  bool
+		wantContenders []int32
+	}{
+		{name: "Same name, no city", venues: garages, rawVenue: "The Garage", wantID: 1, wantAmbiguous: true, wantContenders: []int32{1, 2}},
+		{name: "Same name, city given", venues: garages, rawVenue: "The Garage", city: "Glasgow", wantID: 2},
+		{name: "Same name, other city", venues: garages, rawVenue: "The Garage", city: "Leeds", wantID: 1, wantAmbiguous: true, wantContenders: []int32{1, 2}},
+		// Scoring 0.949 and 0.907 the two are contenders, so the local venue wins
+		{
+			name:   "Contending fuzzy names",
+			venues: []venue{{"Brudenell Social Club", "Leeds"}, {"Bruden Social Club", "London"}}, rawVenue: "Brudenel Social Club", city: "London",
+			wantID: 2,
+		},
+		// Scoring 0.949 and 0.858 the venue elsewhere matched clearly better
+		{
+			name:   "Out-of-city venue scores much higher",
+			venues: []venue{{"Brudenell Social Club", "Leeds"}, {"Bridewell Social Club", "London"}}, rawVenue: "Brudenel Social Club", city: "London",
+			wantID: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idx := newTestIndex(t, func(d metadata.IndexData) {
+				for i, v := range tt.venues {
+					d.Add(metadata.IndexEntry{ID: int32(i + 1), Name: v.name, City: v.city})
+				}
+			})
+			got, err := VenueMatch(context.Background(), idx, tt.rawVenue, tt.city, 0)
+			if err != nil {
+				t.Fatalf("VenueMatch() error = %v", err)
+			}
+			if len(got.Candidates) == 0 || got.Candidates[0].ID != tt.wantID {
+				t.Fatalf("VenueMatch() Candidates = %v, want %d first", got.Candidates, tt.wantID)
+			}
+			if got.Match != tt.venues[tt.wantID-1].name {
+				t.Errorf("VenueMatch() Match = %q, want %q", got.Match, tt.venues[tt.wantID-1].name)
+			}
+			if got.Ambiguous != tt.wantAmbiguous {
+				t.Errorf("VenueMatch() Ambiguous = %v, want %v", got.Ambiguous, tt.wantAmbiguous)
+			}
+			var ids []int32
+			for _, c := range got.Contenders {
+				ids = append(ids, c.ID)
+			}
+			if len(ids) != len(tt.wantContenders) {
+				t.Fatalf("VenueMatch() Contenders = %v, want %v", ids, tt.wantContenders)
+			}
+			for i := range ids {
+				if ids[i] != tt.wantContenders[i] {
+					t.Errorf("VenueMatch() Contenders = %v, want %v", ids, tt.wantContenders)
+					break
+				}
+			}
+		})
+	}
+}
@@ -2,7 +2,32 @@
 SELECT pattern FROM stage_role
 ORDER BY pattern;
 
+-- name: GetStageRoleKinds :many
+SELECT pattern, kind FROM stage_role
+ORDER BY pattern;
+
 -- name: LastModifiedPatternConsts :one
 SELECT last_modified FROM dbcollections_meta 
 WHERE table_name = 'stage_role'
-LIMIT 1;
\ No newline at end of file
+LIMIT 1;
+
+-- name: LastModifiedVenues :one
+SELECT MAX(last_modified)::timestamptz AS last_modified FROM dbcollections_meta
+WHERE table_name IN ('venue', 'venue_alias');
+
+-- name: LastModifiedPerformers :one
+SELECT MAX(last_modified)::timestamptz AS last_modified FROM dbcollections_meta
+WHERE table_name IN ('performer', 'performer_alias');
+
+-- name: LastModifiedPromoters :one
+SELECT MAX(last_modified)::timestamptz AS last_modified FROM dbcollections_meta
+WHERE table_name IN ('promoter', 'promoter_alias');
+
+-- name: LastModifiedFestivals :one
+SELECT MAX(last_modified)::timestamptz AS last_modified FROM dbcollections_meta
+WHERE table_name IN ('festival', 'festival_alias');
+
+-- name: LastModifiedMatchSettings :one
+SELECT last_modified FROM dbcollections_meta
+WHERE table_name = 'match_setting'
+LIMIT 1;
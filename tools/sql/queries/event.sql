@@ -0,0 +1,82 @@
+-- name: CreateEvent :one
+INSERT INTO event (name, venue, event_type, date)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetEvent :one
+SELECT e.id, e.uuid, e.created, e.updated, e.name, e.venue, e.event_type, e.date,
+    v.name AS venue_name, v.city AS venue_city, v.uuid AS venue_uuid,
+    t.name AS event_type_name, t.uuid AS event_type_uuid
+FROM event e
+JOIN venue v ON v.id = e.venue
+JOIN event_type t ON t.id = e.event_type
+WHERE e.id = $1 LIMIT 1;
+
+-- name: GetEventIDByUUID :one
+SELECT id FROM event
+WHERE uuid = $1;
+
+-- name: GetEventIDByVenueAndDate :one
+SELECT id FROM event
+WHERE venue = $1 AND date = $2
+LIMIT 1;
+
+-- name: LockEvent :one
+SELECT updated FROM event
+WHERE id = $1
+FOR UPDATE;
+
+-- name: ListEvents :many
+SELECT e.id, e.uuid, e.created, e.updated, e.name, e.venue, e.event_type, e.date,
+    v.name AS venue_name, v.city AS venue_city, v.uuid AS venue_uuid,
+    t.name AS event_type_name, t.uuid AS event_type_uuid
+FROM event e
+JOIN venue v ON v.id = e.venue
+JOIN event_type t ON t.id = e.event_type
+ORDER BY e.date DESC, e.id;
+
+-- name: TouchEvent :one
+UPDATE event
+SET updated = NOW()
+WHERE id = $1
+RETURNING updated;
+
+-- name: UpdateEvent :one
+UPDATE event
+SET name = $2, venue = $3, event_type = $4, date = $5, updated = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteEvent :exec
+DELETE FROM event
+WHERE id = $1;
+
+-- name: ListEventsPage :many
+SELECT e.id, e.uuid, e.created, e.updated, e.name, e.venue, e.event_type, e.date,
+    v.name AS venue_name, v.city AS venue_city, v.uuid AS venue_uuid,
+    t.name AS event_type_name, t.uuid AS event_type_uuid
+FROM event e
+JOIN venue v ON v.id = e.venue
+JOIN event_type t ON t.id = e.event_type
+WHERE (COALESCE(e.name, '') ILIKE '%' || sqlc.arg(query)::text || '%'
+    OR v.name ILIKE '%' || sqlc.arg(query)::text || '%')
+ORDER BY
+    CASE WHEN sqlc.arg(sort)::text = 'date' THEN e.date END,
+    CASE WHEN sqlc.arg(sort)::text = '-date' THEN e.date END DESC,
+    CASE WHEN sqlc.arg(sort)::text = 'name' THEN e.name END,
+    CASE WHEN sqlc.arg(sort)::text = '-name' THEN e.name END DESC,
+    CASE WHEN sqlc.arg(sort)::text = 'venue' THEN v.name END,
+    CASE WHEN sqlc.arg(sort)::text = '-venue' THEN v.name END DESC,
+    CASE WHEN sqlc.arg(sort)::text = 'created' THEN e.created END,
+    CASE WHEN sqlc.arg(sort)::text = '-created' THEN e.created END DESC,
+    CASE WHEN sqlc.arg(sort)::text = 'updated' THEN e.updated END,
+    CASE WHEN sqlc.arg(sort)::text = '-updated' THEN e.updated END DESC,
+    e.id
+LIMIT sqlc.narg(max_rows) OFFSET sqlc.arg(skip);
+
+-- name: CountEvents :one
+SELECT COUNT(*)::integer AS total
+FROM event e
+JOIN venue v ON v.id = e.venue
+WHERE (COALESCE(e.name, '') ILIKE '%' || sqlc.arg(query)::text || '%'
+    OR v.name ILIKE '%' || sqlc.arg(query)::text || '%');
@@ -0,0 +1,47 @@
+-- name: CreateEventPerformer :one
+INSERT INTO event_performer (event, performer, headliner, slot, role)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetEventPerformer :one
+SELECT * FROM event_performer
+WHERE event = $1 AND performer = $2 LIMIT 1;
+
+-- name: ListEventPerformers :many
+SELECT ep.event, ep.performer, ep.headliner, ep.slot, ep.role, p.name, p.uuid
+FROM event_performer ep
+JOIN performer p ON p.id = ep.performer
+WHERE ep.event = $1
+ORDER BY ep.slot;
+
+-- name: NextEventPerformerSlot :one
+SELECT (COALESCE(MAX(slot), 0) + 1)::integer AS slot FROM event_performer
+WHERE event = $1;
+
+-- name: UpdateEventPerformer :one
+UPDATE event_performer
+SET headliner = $3, role = $4, updated = NOW()
+WHERE event = $1 AND performer = $2
+RETURNING *;
+
+-- Slots are unique within an event, so a new running order is written in two phases:
+-- every slot of the event is first parked at a distinct negative number, clear of the
+-- positive slots that are then set one performer at a time.
+
+-- name: ParkEventPerformerSlots :exec
+UPDATE event_performer
+SET slot = -slot - 1
+WHERE event = $1;
+
+-- name: SetEventPerformerSlot :execrows
+UPDATE event_performer
+SET slot = $3, updated = NOW()
+WHERE event = $1 AND performer = $2;
+
+-- name: DeleteEventPerformer :execrows
+DELETE FROM event_performer
+WHERE event = $1 AND performer = $2;
+
+-- name: DeleteEventPerformers :exec
+DELETE FROM event_performer
+WHERE event = $1;
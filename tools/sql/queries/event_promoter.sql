@@ -0,0 +1,15 @@
+-- name: CreateEventPromoter :one
+INSERT INTO event_promoter (event, promoter, "primary")
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: ListEventPromoters :many
+SELECT ep.event, ep.promoter, ep."primary", p.name, p.uuid
+FROM event_promoter ep
+JOIN promoter p ON p.id = ep.promoter
+WHERE ep.event = $1
+ORDER BY ep."primary" DESC, p.name;
+
+-- name: DeleteEventPromoters :exec
+DELETE FROM event_promoter
+WHERE event = $1;
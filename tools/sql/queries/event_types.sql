@@ -1,24 +1,48 @@
 -- name: CreateEventType :one
 INSERT INTO event_type (name)
 VALUES ($1)
-RETURNING id, uuid, name;
+RETURNING id, uuid, name, created, updated;
 
 -- name: GetEventType :one
-SELECT id, uuid, name
+SELECT id, uuid, name, created, updated
 FROM event_type
 WHERE id = $1;
 
+-- name: GetEventTypeIDByUUID :one
+SELECT id FROM event_type
+WHERE uuid = $1;
+
+-- name: LockEventType :one
+SELECT updated FROM event_type
+WHERE id = $1
+FOR UPDATE;
+
 -- name: ListEventTypes :many
-SELECT id, uuid, name
+SELECT id, uuid, name, created, updated
 FROM event_type
 ORDER BY name;
 
 -- name: UpdateEventType :one
 UPDATE event_type
-SET name = $2
+SET name = $2, updated = NOW()
 WHERE id = $1
-RETURNING id, uuid, name;
+RETURNING id, uuid, name, created, updated;
 
 -- name: DeleteEventType :exec
 DELETE FROM event_type
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
+
+-- name: ListEventTypesPage :many
+SELECT id, uuid, name, created, updated
+FROM event_type
+WHERE name ILIKE '%' || sqlc.arg(query)::text || '%'
+ORDER BY
+    CASE WHEN sqlc.arg(sort)::text = 'name' THEN name END,
+    CASE WHEN sqlc.arg(sort)::text = '-name' THEN name END DESC,
+    id
+LIMIT sqlc.narg(max_rows) OFFSET sqlc.arg(skip);
+
+-- name: CountEventTypes :one
+SELECT COUNT(*)::integer AS total
+FROM event_type
+WHERE name ILIKE '%' || sqlc.arg(query)::text || '%';
@@ -14,6 +14,15 @@ RETURNING *;
 SELECT * FROM festival
 WHERE id = $1 LIMIT 1;
 
+-- name: GetFestivalIDByUUID :one
+SELECT id FROM festival
+WHERE uuid = $1;
+
+-- name: LockFestival :one
+SELECT updated FROM festival
+WHERE id = $1
+FOR UPDATE;
+
 -- name: GetFestivalByName :one
 SELECT * FROM festival
 WHERE name = $1 LIMIT 1;
@@ -22,6 +31,20 @@ WHERE name = $1 LIMIT 1;
 SELECT * FROM festival
 ORDER BY start_date DESC;
 
+-- name: ListFestivalsAtVenueOnDate :many
+SELECT f.id, f.uuid, f.name, f.promoter, f.start_date, f.end_date, f.description, f.created, f.updated
+FROM festival f
+JOIN festival_venue fv ON fv.festival = f.id
+WHERE fv.venue = sqlc.arg(venue)
+  AND sqlc.arg(date)::date BETWEEN f.start_date AND f.end_date
+ORDER BY f.start_date DESC, f.name;
+
+-- name: TouchFestival :one
+UPDATE festival
+SET updated = NOW()
+WHERE id = $1
+RETURNING updated;
+
 -- name: UpdateFestival :one
 UPDATE festival
 SET
@@ -29,7 +52,8 @@ SET
     promoter = $3,
     start_date = $4,
     end_date = $5,
-    description = $6
+    description = $6,
+    updated = NOW()
 WHERE id = $1
 RETURNING *;
 
@@ -50,6 +74,15 @@ RETURNING *;
 SELECT * FROM festival_alias
 WHERE id = $1 LIMIT 1;
 
+-- name: GetFestivalAliasIDByUUID :one
+SELECT id FROM festival_alias
+WHERE uuid = $1;
+
+-- name: LockFestivalAlias :one
+SELECT updated FROM festival_alias
+WHERE id = $1
+FOR UPDATE;
+
 -- name: ListFestivalAliases :many
 SELECT * FROM festival_alias
 ORDER BY alias;
@@ -58,10 +91,65 @@ ORDER BY alias;
 UPDATE festival_alias
 SET
     festival = $2,
-    alias = $3
+    alias = $3,
+    updated = NOW()
 WHERE id = $1
 RETURNING *;
 
 -- name: DeleteFestivalAlias :exec
 DELETE FROM festival_alias
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
+
+-- name: SearchFestivalCandidates :many
+SELECT id, name, 0::integer AS alias_id, ''::text AS alias,
+    similarity(name, sqlc.arg(query)::text)::real AS score
+FROM festival
+WHERE name % sqlc.arg(query)::text
+UNION ALL
+SELECT e.id, e.name, a.id AS alias_id, a.alias,
+    similarity(a.alias, sqlc.arg(query)::text)::real AS score
+FROM festival_alias a
+JOIN festival e ON e.id = a.festival
+WHERE a.alias % sqlc.arg(query)::text
+ORDER BY score DESC, name
+LIMIT sqlc.arg(max_results);
+
+-- name: ListFestivalsPage :many
+SELECT id, uuid, name, promoter, start_date, end_date, description, created, updated FROM festival
+WHERE name ILIKE '%' || sqlc.arg(query)::text || '%'
+ORDER BY
+    CASE WHEN sqlc.arg(sort)::text = 'start_date' THEN start_date END,
+    CASE WHEN sqlc.arg(sort)::text = '-start_date' THEN start_date END DESC,
+    CASE WHEN sqlc.arg(sort)::text = 'end_date' THEN end_date END,
+    CASE WHEN sqlc.arg(sort)::text = '-end_date' THEN end_date END DESC,
+    CASE WHEN sqlc.arg(sort)::text = 'name' THEN name END,
+    CASE WHEN sqlc.arg(sort)::text = '-name' THEN name END DESC,
+    CASE WHEN sqlc.arg(sort)::text = 'created' THEN created END,
+    CASE WHEN sqlc.arg(sort)::text = '-created' THEN created END DESC,
+    CASE WHEN sqlc.arg(sort)::text = 'updated' THEN updated END,
+    CASE WHEN sqlc.arg(sort)::text = '-updated' THEN updated END DESC,
+    id
+LIMIT sqlc.narg(max_rows) OFFSET sqlc.arg(skip);
+
+-- name: CountFestivals :one
+SELECT COUNT(*)::integer AS total
+FROM festival
+WHERE name ILIKE '%' || sqlc.arg(query)::text || '%';
+
+-- name: ListFestivalAliasesPage :many
+SELECT id, uuid, festival, alias, created, updated FROM festival_alias
+WHERE alias ILIKE '%' || sqlc.arg(query)::text || '%'
+ORDER BY
+    CASE WHEN sqlc.arg(sort)::text = 'alias' THEN alias END,
+    CASE WHEN sqlc.arg(sort)::text = '-alias' THEN alias END DESC,
+    CASE WHEN sqlc.arg(sort)::text = 'created' THEN created END,
+    CASE WHEN sqlc.arg(sort)::text = '-created' THEN created END DESC,
+    CASE WHEN sqlc.arg(sort)::text = 'updated' THEN updated END,
+    CASE WHEN sqlc.arg(sort)::text = '-updated' THEN updated END DESC,
+    id
+LIMIT sqlc.narg(max_rows) OFFSET sqlc.arg(skip);
+
+-- name: CountFestivalAliases :one
+SELECT COUNT(*)::integer AS total
+FROM festival_alias
+WHERE alias ILIKE '%' || sqlc.arg(query)::text || '%';
@@ -0,0 +1,21 @@
+-- name: CreateFestivalPromoter :one
+INSERT INTO festival_promoter (festival, promoter, role)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: ListFestivalPromoters :many
+SELECT fp.festival, fp.promoter, fp.role, p.name, p.uuid
+FROM festival_promoter fp
+JOIN promoter p ON p.id = fp.promoter
+WHERE fp.festival = $1
+ORDER BY p.name;
+
+-- name: UpdateFestivalPromoter :one
+UPDATE festival_promoter
+SET role = $3
+WHERE festival = $1 AND promoter = $2
+RETURNING *;
+
+-- name: DeleteFestivalPromoter :execrows
+DELETE FROM festival_promoter
+WHERE festival = $1 AND promoter = $2;
@@ -0,0 +1,26 @@
+-- name: CreateFestivalVenue :one
+INSERT INTO festival_venue (festival, venue, stage_order, is_primary)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: ListFestivalVenues :many
+SELECT fv.festival, fv.venue, fv.stage_order, fv.is_primary, v.name, v.city, v.uuid
+FROM festival_venue fv
+JOIN venue v ON v.id = fv.venue
+WHERE fv.festival = $1
+ORDER BY fv.stage_order NULLS LAST, v.name;
+
+-- name: UpdateFestivalVenue :one
+UPDATE festival_venue
+SET stage_order = $3, is_primary = $4
+WHERE festival = $1 AND venue = $2
+RETURNING *;
+
+-- name: ClearFestivalVenuePrimary :exec
+UPDATE festival_venue
+SET is_primary = false
+WHERE festival = $1 AND venue <> $2;
+
+-- name: DeleteFestivalVenue :execrows
+DELETE FROM festival_venue
+WHERE festival = $1 AND venue = $2;
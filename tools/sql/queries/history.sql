@@ -0,0 +1,23 @@
+-- name: CountPerformerEventsAtVenue :many
+SELECT ep.performer, COUNT(*)::integer AS events
+FROM event_performer ep
+JOIN event e ON e.id = ep.event
+WHERE e.venue = sqlc.arg(venue)
+  AND ep.performer = ANY(sqlc.arg(performers)::integer[])
+GROUP BY ep.performer;
+
+-- name: ListSharedBills :many
+SELECT a.performer, b.performer AS other, COUNT(*)::integer AS events
+FROM event_performer a
+JOIN event_performer b ON b.event = a.event AND b.performer <> a.performer
+WHERE a.performer = ANY(sqlc.arg(performers)::integer[])
+  AND b.performer = ANY(sqlc.arg(others)::integer[])
+GROUP BY a.performer, b.performer;
+
+-- name: CountVenueEventsWithPromoters :many
+SELECT e.venue, COUNT(DISTINCT e.id)::integer AS events
+FROM event e
+JOIN event_promoter ep ON ep.event = e.id
+WHERE e.venue = ANY(sqlc.arg(venues)::integer[])
+  AND ep.promoter = ANY(sqlc.arg(promoters)::integer[])
+GROUP BY e.venue;
@@ -5,15 +5,26 @@ INSERT INTO image_location (
     date_from_exif,
     include_parent,
     ignore_dirs,
-    active
+    active,
+    default_city,
+    headliner_last
 ) VALUES (
-    $1, $2, $3, $4, $5, $6
+    $1, $2, $3, $4, $5, $6, $7, $8
 ) RETURNING *;
 
 -- name: GetImageLocation :one
 SELECT * FROM image_location
 WHERE id = $1 LIMIT 1;
 
+-- name: GetImageLocationIDByUUID :one
+SELECT id FROM image_location
+WHERE uuid = $1;
+
+-- name: LockImageLocation :one
+SELECT updated FROM image_location
+WHERE id = $1
+FOR UPDATE;
+
 -- name: ListImageLocations :many
 SELECT * FROM image_location
 ORDER BY root;
@@ -27,6 +38,8 @@ SET
     include_parent = $5,
     ignore_dirs = $6,
     active = $7,
+    default_city = $8,
+    headliner_last = $9,
     updated = now()
 WHERE id = $1
 RETURNING *;
@@ -34,3 +47,21 @@ RETURNING *;
 -- name: DeleteImageLocation :exec
 DELETE FROM image_location
 WHERE id = $1;
+
+-- name: ListImageLocationsPage :many
+SELECT id, root, created, updated, pattern, date_from_exif, include_parent, ignore_dirs, active, default_city, headliner_last, uuid FROM image_location
+WHERE root ILIKE '%' || sqlc.arg(query)::text || '%'
+ORDER BY
+    CASE WHEN sqlc.arg(sort)::text = 'root' THEN root END,
+    CASE WHEN sqlc.arg(sort)::text = '-root' THEN root END DESC,
+    CASE WHEN sqlc.arg(sort)::text = 'created' THEN created END,
+    CASE WHEN sqlc.arg(sort)::text = '-created' THEN created END DESC,
+    CASE WHEN sqlc.arg(sort)::text = 'updated' THEN updated END,
+    CASE WHEN sqlc.arg(sort)::text = '-updated' THEN updated END DESC,
+    id
+LIMIT sqlc.narg(max_rows) OFFSET sqlc.arg(skip);
+
+-- name: CountImageLocations :one
+SELECT COUNT(*)::integer AS total
+FROM image_location
+WHERE root ILIKE '%' || sqlc.arg(query)::text || '%';
@@ -0,0 +1,14 @@
+-- name: ListMatchSettings :many
+SELECT entity_type, alias_confidence, fuzzy_name_confidence, fuzzy_alias_confidence, phonetic_confidence, min_similarity, short_length, short_max_edits, max_edits, created, updated FROM match_setting
+ORDER BY entity_type;
+
+-- name: GetMatchSetting :one
+SELECT entity_type, alias_confidence, fuzzy_name_confidence, fuzzy_alias_confidence, phonetic_confidence, min_similarity, short_length, short_max_edits, max_edits, created, updated FROM match_setting
+WHERE entity_type = $1;
+
+-- name: UpdateMatchSetting :one
+UPDATE match_setting
+SET alias_confidence = $1, fuzzy_name_confidence = $2, fuzzy_alias_confidence = $3, phonetic_confidence = $4,
+    min_similarity = $5, short_length = $6, short_max_edits = $7, max_edits = $8, updated = NOW()
+WHERE entity_type = $9
+RETURNING entity_type, alias_confidence, fuzzy_name_confidence, fuzzy_alias_confidence, phonetic_confidence, min_similarity, short_length, short_max_edits, max_edits, created, updated;
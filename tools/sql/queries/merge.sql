@@ -0,0 +1,162 @@
+-- Queries for merging a duplicate row (source) into the row that survives it (target).
+-- Link rows the target already has are deleted rather than moved, as moving them would
+-- break the link table's primary key. They are counted first where they differ from the
+-- target's row, as the merge would lose what they record.
+
+-- name: CountDifferingEventPerformers :one
+SELECT COUNT(*)::integer AS differing
+FROM event_performer a
+JOIN event_performer b ON b.event = a.event AND b.performer = sqlc.arg(target)
+WHERE a.performer = sqlc.arg(source)
+  AND (a.headliner <> b.headliner OR a.role IS DISTINCT FROM b.role);
+
+-- name: DeleteOverlappingEventPerformers :execrows
+DELETE FROM event_performer a
+WHERE a.performer = sqlc.arg(source)
+  AND EXISTS (SELECT 1 FROM event_performer b WHERE b.event = a.event AND b.performer = sqlc.arg(target));
+
+-- name: MoveEventPerformers :execrows
+UPDATE event_performer
+SET performer = sqlc.arg(target), updated = NOW()
+WHERE performer = sqlc.arg(source);
+
+-- name: MovePerformerAliases :execrows
+UPDATE performer_alias
+SET performer = sqlc.arg(target), updated = NOW()
+WHERE performer = sqlc.arg(source);
+
+-- name: CreateMergedPerformerAlias :execrows
+INSERT INTO performer_alias (performer, alias, phonetic)
+VALUES ($1, $2, $3)
+ON CONFLICT (alias) DO NOTHING;
+
+-- name: CountClashingVenueEvents :one
+SELECT COUNT(*)::integer AS events
+FROM event a
+JOIN event b ON b.venue = sqlc.arg(target) AND b.date = a.date AND b.name = a.name
+WHERE a.venue = sqlc.arg(source);
+
+-- name: MoveVenueEvents :execrows
+UPDATE event
+SET venue = sqlc.arg(target), updated = NOW()
+WHERE venue = sqlc.arg(source);
+
+-- name: CountDifferingVenueFestivals :one
+SELECT COUNT(*)::integer AS differing
+FROM festival_venue a
+JOIN festival_venue b ON b.festival = a.festival AND b.venue = sqlc.arg(target)
+WHERE a.venue = sqlc.arg(source)
+  AND (a.stage_order IS DISTINCT FROM b.stage_order OR a.is_primary IS DISTINCT FROM b.is_primary);
+
+-- name: DeleteOverlappingVenueFestivals :execrows
+DELETE FROM festival_venue a
+WHERE a.venue = sqlc.arg(source)
+  AND EXISTS (SELECT 1 FROM festival_venue b WHERE b.festival = a.festival AND b.venue = sqlc.arg(target));
+
+-- name: MoveVenueFestivals :execrows
+UPDATE festival_venue
+SET venue = sqlc.arg(target)
+WHERE venue = sqlc.arg(source);
+
+-- name: MoveVenueAliases :execrows
+UPDATE venue_alias
+SET venue = sqlc.arg(target), updated = NOW()
+WHERE venue = sqlc.arg(source);
+
+-- name: CreateMergedVenueAlias :execrows
+INSERT INTO venue_alias (venue, alias)
+VALUES ($1, $2)
+ON CONFLICT (alias) DO NOTHING;
+
+-- name: CountDifferingEventPromoters :one
+SELECT COUNT(*)::integer AS differing
+FROM event_promoter a
+JOIN event_promoter b ON b.event = a.event AND b.promoter = sqlc.arg(target)
+WHERE a.promoter = sqlc.arg(source)
+  AND (a."primary" <> b."primary");
+
+-- name: DeleteOverlappingEventPromoters :execrows
+DELETE FROM event_promoter a
+WHERE a.promoter = sqlc.arg(source)
+  AND EXISTS (SELECT 1 FROM event_promoter b WHERE b.event = a.event AND b.promoter = sqlc.arg(target));
+
+-- name: MoveEventPromoters :execrows
+UPDATE event_promoter
+SET promoter = sqlc.arg(target), updated = NOW()
+WHERE promoter = sqlc.arg(source);
+
+-- name: CountDifferingPromoterFestivals :one
+SELECT COUNT(*)::integer AS differing
+FROM festival_promoter a
+JOIN festival_promoter b ON b.festival = a.festival AND b.promoter = sqlc.arg(target)
+WHERE a.promoter = sqlc.arg(source)
+  AND (a.role IS DISTINCT FROM b.role);
+
+-- name: DeleteOverlappingPromoterFestivals :execrows
+DELETE FROM festival_promoter a
+WHERE a.promoter = sqlc.arg(source)
+  AND EXISTS (SELECT 1 FROM festival_promoter b WHERE b.festival = a.festival AND b.promoter = sqlc.arg(target));
+
+-- name: MovePromoterFestivals :execrows
+UPDATE festival_promoter
+SET promoter = sqlc.arg(target)
+WHERE promoter = sqlc.arg(source);
+
+-- name: MovePromotedFestivals :execrows
+UPDATE festival
+SET promoter = sqlc.arg(target), updated = NOW()
+WHERE promoter = sqlc.arg(source);
+
+-- name: MovePromoterAliases :execrows
+UPDATE promoter_alias
+SET promoter = sqlc.arg(target), updated = NOW()
+WHERE promoter = sqlc.arg(source);
+
+-- name: CreateMergedPromoterAlias :execrows
+INSERT INTO promoter_alias (promoter, alias)
+VALUES ($1, $2)
+ON CONFLICT (alias) DO NOTHING;
+
+-- name: CountDifferingFestivalVenues :one
+SELECT COUNT(*)::integer AS differing
+FROM festival_venue a
+JOIN festival_venue b ON b.venue = a.venue AND b.festival = sqlc.arg(target)
+WHERE a.festival = sqlc.arg(source)
+  AND (a.stage_order IS DISTINCT FROM b.stage_order OR a.is_primary IS DISTINCT FROM b.is_primary);
+
+-- name: DeleteOverlappingFestivalVenues :execrows
+DELETE FROM festival_venue a
+WHERE a.festival = sqlc.arg(source)
+  AND EXISTS (SELECT 1 FROM festival_venue b WHERE b.venue = a.venue AND b.festival = sqlc.arg(target));
+
+-- name: MoveFestivalVenues :execrows
+UPDATE festival_venue
+SET festival = sqlc.arg(target)
+WHERE festival = sqlc.arg(source);
+
+-- name: CountDifferingFestivalPromoters :one
+SELECT COUNT(*)::integer AS differing
+FROM festival_promoter a
+JOIN festival_promoter b ON b.promoter = a.promoter AND b.festival = sqlc.arg(target)
+WHERE a.festival = sqlc.arg(source)
+  AND (a.role IS DISTINCT FROM b.role);
+
+-- name: DeleteOverlappingFestivalPromoters :execrows
+DELETE FROM festival_promoter a
+WHERE a.festival = sqlc.arg(source)
+  AND EXISTS (SELECT 1 FROM festival_promoter b WHERE b.promoter = a.promoter AND b.festival = sqlc.arg(target));
+
+-- name: MoveFestivalPromoters :execrows
+UPDATE festival_promoter
+SET festival = sqlc.arg(target)
+WHERE festival = sqlc.arg(source);
+
+-- name: MoveFestivalAliases :execrows
+UPDATE festival_alias
+SET festival = sqlc.arg(target), updated = NOW()
+WHERE festival = sqlc.arg(source);
+
+-- name: CreateMergedFestivalAlias :execrows
+INSERT INTO festival_alias (festival, alias)
+VALUES ($1, $2)
+ON CONFLICT (alias) DO NOTHING;
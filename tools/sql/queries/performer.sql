@@ -1,5 +1,5 @@
 -- name: ListPerformers :many
-SELECT id, uuid, created, updated, name FROM performer
+SELECT id, uuid, created, updated, name, phonetic FROM performer
 ORDER BY name;
 
 -- name: GetPerformerByName :one
@@ -7,20 +7,61 @@ SELECT * FROM performer
 WHERE name = $1 LIMIT 1;
 
 -- name: CreatePerformer :one
-INSERT INTO performer (name)
-VALUES ($1)
-RETURNING id, uuid, created, updated, name;
+INSERT INTO performer (name, phonetic)
+VALUES ($1, $2)
+RETURNING id, uuid, created, updated, name, phonetic;
 
 -- name: GetPerformer :one
-SELECT id, uuid, created, updated, name FROM performer
+SELECT id, uuid, created, updated, name, phonetic FROM performer
 WHERE id = $1;
 
+-- name: GetPerformerIDByUUID :one
+SELECT id FROM performer
+WHERE uuid = $1;
+
+-- name: LockPerformer :one
+SELECT updated FROM performer
+WHERE id = $1
+FOR UPDATE;
+
 -- name: UpdatePerformer :one
 UPDATE performer
-SET name = $1, updated = NOW()
-WHERE id = $2
-RETURNING id, uuid, created, updated, name;
+SET name = $1, phonetic = $2, updated = NOW()
+WHERE id = $3
+RETURNING id, uuid, created, updated, name, phonetic;
 
 -- name: DeletePerformer :exec
 DELETE FROM performer
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
+
+-- name: SearchPerformerCandidates :many
+SELECT id, name, 0::integer AS alias_id, ''::text AS alias,
+    similarity(name, sqlc.arg(query)::text)::real AS score
+FROM performer
+WHERE name % sqlc.arg(query)::text
+UNION ALL
+SELECT e.id, e.name, a.id AS alias_id, a.alias,
+    similarity(a.alias, sqlc.arg(query)::text)::real AS score
+FROM performer_alias a
+JOIN performer e ON e.id = a.performer
+WHERE a.alias % sqlc.arg(query)::text
+ORDER BY score DESC, name
+LIMIT sqlc.arg(max_results);
+
+-- name: ListPerformersPage :many
+SELECT id, uuid, created, updated, name, phonetic FROM performer
+WHERE name ILIKE '%' || sqlc.arg(query)::text || '%'
+ORDER BY
+    CASE WHEN sqlc.arg(sort)::text = 'name' THEN name END,
+    CASE WHEN sqlc.arg(sort)::text = '-name' THEN name END DESC,
+    CASE WHEN sqlc.arg(sort)::text = 'created' THEN created END,
+    CASE WHEN sqlc.arg(sort)::text = '-created' THEN created END DESC,
+    CASE WHEN sqlc.arg(sort)::text = 'updated' THEN updated END,
+    CASE WHEN sqlc.arg(sort)::text = '-updated' THEN updated END DESC,
+    id
+LIMIT sqlc.narg(max_rows) OFFSET sqlc.arg(skip);
+
+-- name: CountPerformers :one
+SELECT COUNT(*)::integer AS total
+FROM performer
+WHERE name ILIKE '%' || sqlc.arg(query)::text || '%';
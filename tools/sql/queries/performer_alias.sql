@@ -1,22 +1,49 @@
 -- name: ListPerformerAliases :many
-SELECT id, uuid, performer, created, updated, alias FROM performer_alias
+SELECT id, uuid, performer, created, updated, alias, phonetic FROM performer_alias
 ORDER BY alias;
 
 -- name: CreatePerformerAlias :one
-INSERT INTO performer_alias (performer, alias)
-VALUES ($1, $2)
-RETURNING id, uuid, performer, created, updated, alias;
+INSERT INTO performer_alias (performer, alias, phonetic)
+VALUES ($1, $2, $3)
+RETURNING id, uuid, performer, created, updated, alias, phonetic;
 
 -- name: GetPerformerAlias :one
-SELECT id, uuid, performer, created, updated, alias FROM performer_alias
+SELECT id, uuid, performer, created, updated, alias, phonetic FROM performer_alias
 WHERE id = $1;
 
+-- name: GetPerformerAliasIDByUUID :one
+SELECT id FROM performer_alias
+WHERE uuid = $1;
+
+-- name: LockPerformerAlias :one
+SELECT updated FROM performer_alias
+WHERE id = $1
+FOR UPDATE;
+
 -- name: UpdatePerformerAlias :one
 UPDATE performer_alias
-SET performer = $1, alias = $2, updated = NOW()
-WHERE id = $3
-RETURNING id, uuid, performer, created, updated, alias;
+SET performer = $1, alias = $2, phonetic = $3, updated = NOW()
+WHERE id = $4
+RETURNING id, uuid, performer, created, updated, alias, phonetic;
 
 -- name: DeletePerformerAlias :exec
 DELETE FROM performer_alias
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
+
+-- name: ListPerformerAliasesPage :many
+SELECT id, uuid, performer, created, updated, alias, phonetic FROM performer_alias
+WHERE alias ILIKE '%' || sqlc.arg(query)::text || '%'
+ORDER BY
+    CASE WHEN sqlc.arg(sort)::text = 'alias' THEN alias END,
+    CASE WHEN sqlc.arg(sort)::text = '-alias' THEN alias END DESC,
+    CASE WHEN sqlc.arg(sort)::text = 'created' THEN created END,
+    CASE WHEN sqlc.arg(sort)::text = '-created' THEN created END DESC,
+    CASE WHEN sqlc.arg(sort)::text = 'updated' THEN updated END,
+    CASE WHEN sqlc.arg(sort)::text = '-updated' THEN updated END DESC,
+    id
+LIMIT sqlc.narg(max_rows) OFFSET sqlc.arg(skip);
+
+-- name: CountPerformerAliases :one
+SELECT COUNT(*)::integer AS total
+FROM performer_alias
+WHERE alias ILIKE '%' || sqlc.arg(query)::text || '%';
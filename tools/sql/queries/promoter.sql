@@ -11,6 +11,15 @@ RETURNING id, uuid, created, updated, name;
 SELECT id, uuid, created, updated, name FROM promoter
 WHERE id = $1;
 
+-- name: GetPromoterIDByUUID :one
+SELECT id FROM promoter
+WHERE uuid = $1;
+
+-- name: LockPromoter :one
+SELECT updated FROM promoter
+WHERE id = $1
+FOR UPDATE;
+
 -- name: UpdatePromoter :one
 UPDATE promoter
 SET name = $1, updated = NOW()
@@ -19,4 +28,36 @@ RETURNING id, uuid, created, updated, name;
 
 -- name: DeletePromoter :exec
 DELETE FROM promoter
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
+
+-- name: SearchPromoterCandidates :many
+SELECT id, name, 0::integer AS alias_id, ''::text AS alias,
+    similarity(name, sqlc.arg(query)::text)::real AS score
+FROM promoter
+WHERE name % sqlc.arg(query)::text
+UNION ALL
+SELECT e.id, e.name, a.id AS alias_id, a.alias,
+    similarity(a.alias, sqlc.arg(query)::text)::real AS score
+FROM promoter_alias a
+JOIN promoter e ON e.id = a.promoter
+WHERE a.alias % sqlc.arg(query)::text
+ORDER BY score DESC, name
+LIMIT sqlc.arg(max_results);
+
+-- name: ListPromotersPage :many
+SELECT id, uuid, created, updated, name FROM promoter
+WHERE name ILIKE '%' || sqlc.arg(query)::text || '%'
+ORDER BY
+    CASE WHEN sqlc.arg(sort)::text = 'name' THEN name END,
+    CASE WHEN sqlc.arg(sort)::text = '-name' THEN name END DESC,
+    CASE WHEN sqlc.arg(sort)::text = 'created' THEN created END,
+    CASE WHEN sqlc.arg(sort)::text = '-created' THEN created END DESC,
+    CASE WHEN sqlc.arg(sort)::text = 'updated' THEN updated END,
+    CASE WHEN sqlc.arg(sort)::text = '-updated' THEN updated END DESC,
+    id
+LIMIT sqlc.narg(max_rows) OFFSET sqlc.arg(skip);
+
+-- name: CountPromoters :one
+SELECT COUNT(*)::integer AS total
+FROM promoter
+WHERE name ILIKE '%' || sqlc.arg(query)::text || '%';
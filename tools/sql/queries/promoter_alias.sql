@@ -15,6 +15,15 @@ RETURNING id, uuid, promoter, created, updated, alias;
 SELECT id, uuid, promoter, created, updated, alias FROM promoter_alias
 WHERE id = $1;
 
+-- name: GetPromoterAliasIDByUUID :one
+SELECT id FROM promoter_alias
+WHERE uuid = $1;
+
+-- name: LockPromoterAlias :one
+SELECT updated FROM promoter_alias
+WHERE id = $1
+FOR UPDATE;
+
 -- name: UpdatePromoterAlias :one
 UPDATE promoter_alias
 SET promoter = $1, alias = $2, updated = NOW()
@@ -23,4 +32,22 @@ RETURNING id, uuid, promoter, created, updated, alias;
 
 -- name: DeletePromoterAlias :exec
 DELETE FROM promoter_alias
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
+
+-- name: ListPromoterAliasesPage :many
+SELECT id, uuid, promoter, created, updated, alias FROM promoter_alias
+WHERE alias ILIKE '%' || sqlc.arg(query)::text || '%'
+ORDER BY
+    CASE WHEN sqlc.arg(sort)::text = 'alias' THEN alias END,
+    CASE WHEN sqlc.arg(sort)::text = '-alias' THEN alias END DESC,
+    CASE WHEN sqlc.arg(sort)::text = 'created' THEN created END,
+    CASE WHEN sqlc.arg(sort)::text = '-created' THEN created END DESC,
+    CASE WHEN sqlc.arg(sort)::text = 'updated' THEN updated END,
+    CASE WHEN sqlc.arg(sort)::text = '-updated' THEN updated END DESC,
+    id
+LIMIT sqlc.narg(max_rows) OFFSET sqlc.arg(skip);
+
+-- name: CountPromoterAliases :one
+SELECT COUNT(*)::integer AS total
+FROM promoter_alias
+WHERE alias ILIKE '%' || sqlc.arg(query)::text || '%';
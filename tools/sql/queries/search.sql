@@ -0,0 +1,80 @@
+-- name: SearchEntities :many
+-- Relevance is the trigram similarity of a name or alias to the search text, boosted when
+-- it starts with or contains the text, so partial names typed into a search still rank well.
+SELECT 'performer'::text AS entity_type, id, uuid, name, ''::text AS detail,
+    0::integer AS alias_id, NULL::uuid AS alias_uuid, ''::text AS alias,
+    (similarity(name, sqlc.arg(query)::text)
+        + CASE WHEN name ILIKE sqlc.arg(pattern)::text || '%' THEN 0.5
+            WHEN name ILIKE '%' || sqlc.arg(pattern)::text || '%' THEN 0.25 ELSE 0 END)::real AS score
+FROM performer
+WHERE name % sqlc.arg(query)::text OR name ILIKE '%' || sqlc.arg(pattern)::text || '%'
+UNION ALL
+SELECT 'performer'::text, e.id, e.uuid, e.name, ''::text,
+    a.id, a.uuid, a.alias,
+    (similarity(a.alias, sqlc.arg(query)::text)
+        + CASE WHEN a.alias ILIKE sqlc.arg(pattern)::text || '%' THEN 0.5
+            WHEN a.alias ILIKE '%' || sqlc.arg(pattern)::text || '%' THEN 0.25 ELSE 0 END)::real
+FROM performer_alias a
+JOIN performer e ON e.id = a.performer
+WHERE a.alias % sqlc.arg(query)::text OR a.alias ILIKE '%' || sqlc.arg(pattern)::text || '%'
+UNION ALL
+SELECT 'venue'::text AS entity_type, id, uuid, name, city::text AS detail,
+    0::integer AS alias_id, NULL::uuid AS alias_uuid, ''::text AS alias,
+    (similarity(name, sqlc.arg(query)::text)
+        + CASE WHEN name ILIKE sqlc.arg(pattern)::text || '%' THEN 0.5
+            WHEN name ILIKE '%' || sqlc.arg(pattern)::text || '%' THEN 0.25 ELSE 0 END)::real AS score
+FROM venue
+WHERE name % sqlc.arg(query)::text OR name ILIKE '%' || sqlc.arg(pattern)::text || '%'
+UNION ALL
+SELECT 'venue'::text, e.id, e.uuid, e.name, e.city::text,
+    a.id, a.uuid, a.alias,
+    (similarity(a.alias, sqlc.arg(query)::text)
+        + CASE WHEN a.alias ILIKE sqlc.arg(pattern)::text || '%' THEN 0.5
+            WHEN a.alias ILIKE '%' || sqlc.arg(pattern)::text || '%' THEN 0.25 ELSE 0 END)::real
+FROM venue_alias a
+JOIN venue e ON e.id = a.venue
+WHERE a.alias % sqlc.arg(query)::text OR a.alias ILIKE '%' || sqlc.arg(pattern)::text || '%'
+UNION ALL
+SELECT 'promoter'::text AS entity_type, id, uuid, name, ''::text AS detail,
+    0::integer AS alias_id, NULL::uuid AS alias_uuid, ''::text AS alias,
+    (similarity(name, sqlc.arg(query)::text)
+        + CASE WHEN name ILIKE sqlc.arg(pattern)::text || '%' THEN 0.5
+            WHEN name ILIKE '%' || sqlc.arg(pattern)::text || '%' THEN 0.25 ELSE 0 END)::real AS score
+FROM promoter
+WHERE name % sqlc.arg(query)::text OR name ILIKE '%' || sqlc.arg(pattern)::text || '%'
+UNION ALL
+SELECT 'promoter'::text, e.id, e.uuid, e.name, ''::text,
+    a.id, a.uuid, a.alias,
+    (similarity(a.alias, sqlc.arg(query)::text)
+        + CASE WHEN a.alias ILIKE sqlc.arg(pattern)::text || '%' THEN 0.5
+            WHEN a.alias ILIKE '%' || sqlc.arg(pattern)::text || '%' THEN 0.25 ELSE 0 END)::real
+FROM promoter_alias a
+JOIN promoter e ON e.id = a.promoter
+WHERE a.alias % sqlc.arg(query)::text OR a.alias ILIKE '%' || sqlc.arg(pattern)::text || '%'
+UNION ALL
+SELECT 'festival'::text AS entity_type, id, uuid, name, ''::text AS detail,
+    0::integer AS alias_id, NULL::uuid AS alias_uuid, ''::text AS alias,
+    (similarity(name, sqlc.arg(query)::text)
+        + CASE WHEN name ILIKE sqlc.arg(pattern)::text || '%' THEN 0.5
+            WHEN name ILIKE '%' || sqlc.arg(pattern)::text || '%' THEN 0.25 ELSE 0 END)::real AS score
+FROM festival
+WHERE name % sqlc.arg(query)::text OR name ILIKE '%' || sqlc.arg(pattern)::text || '%'
+UNION ALL
+SELECT 'festival'::text, e.id, e.uuid, e.name, ''::text,
+    a.id, a.uuid, a.alias,
+    (similarity(a.alias, sqlc.arg(query)::text)
+        + CASE WHEN a.alias ILIKE sqlc.arg(pattern)::text || '%' THEN 0.5
+            WHEN a.alias ILIKE '%' || sqlc.arg(pattern)::text || '%' THEN 0.25 ELSE 0 END)::real
+FROM festival_alias a
+JOIN festival e ON e.id = a.festival
+WHERE a.alias % sqlc.arg(query)::text OR a.alias ILIKE '%' || sqlc.arg(pattern)::text || '%'
+UNION ALL
+SELECT 'event_type'::text AS entity_type, id, uuid, name, ''::text AS detail,
+    0::integer AS alias_id, NULL::uuid AS alias_uuid, ''::text AS alias,
+    (similarity(name, sqlc.arg(query)::text)
+        + CASE WHEN name ILIKE sqlc.arg(pattern)::text || '%' THEN 0.5
+            WHEN name ILIKE '%' || sqlc.arg(pattern)::text || '%' THEN 0.25 ELSE 0 END)::real AS score
+FROM event_type
+WHERE name % sqlc.arg(query)::text OR name ILIKE '%' || sqlc.arg(pattern)::text || '%'
+ORDER BY score DESC, name, alias
+LIMIT sqlc.arg(max_results);
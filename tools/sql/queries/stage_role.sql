@@ -1,22 +1,51 @@
 -- name: ListStageRoles :many
-SELECT id, uuid, created, updated, pattern FROM stage_role
+SELECT id, uuid, created, updated, pattern, kind FROM stage_role
 ORDER BY pattern;
 
 -- name: CreateStageRole :one
-INSERT INTO stage_role (pattern)
-VALUES ($1)
-RETURNING id, uuid, created, updated, pattern;
+INSERT INTO stage_role (pattern, kind)
+VALUES ($1, $2)
+RETURNING id, uuid, created, updated, pattern, kind;
 
 -- name: GetStageRole :one
-SELECT id, uuid, created, updated, pattern FROM stage_role
+SELECT id, uuid, created, updated, pattern, kind FROM stage_role
 WHERE id = $1;
 
+-- name: GetStageRoleIDByUUID :one
+SELECT id FROM stage_role
+WHERE uuid = $1;
+
+-- name: LockStageRole :one
+SELECT updated FROM stage_role
+WHERE id = $1
+FOR UPDATE;
+
 -- name: UpdateStageRole :one
 UPDATE stage_role
-SET pattern = $1, updated = NOW()
-WHERE id = $2
-RETURNING id, uuid, created, updated, pattern;
+SET pattern = $1, kind = $2, updated = NOW()
+WHERE id = $3
+RETURNING id, uuid, created, updated, pattern, kind;
 
 -- name: DeleteStageRole :exec
 DELETE FROM stage_role
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
+
+-- name: ListStageRolesPage :many
+SELECT id, uuid, created, updated, pattern, kind FROM stage_role
+WHERE pattern ILIKE '%' || sqlc.arg(query)::text || '%'
+ORDER BY
+    CASE WHEN sqlc.arg(sort)::text = 'pattern' THEN pattern END,
+    CASE WHEN sqlc.arg(sort)::text = '-pattern' THEN pattern END DESC,
+    CASE WHEN sqlc.arg(sort)::text = 'kind' THEN kind END,
+    CASE WHEN sqlc.arg(sort)::text = '-kind' THEN kind END DESC,
+    CASE WHEN sqlc.arg(sort)::text = 'created' THEN created END,
+    CASE WHEN sqlc.arg(sort)::text = '-created' THEN created END DESC,
+    CASE WHEN sqlc.arg(sort)::text = 'updated' THEN updated END,
+    CASE WHEN sqlc.arg(sort)::text = '-updated' THEN updated END DESC,
+    id
+LIMIT sqlc.narg(max_rows) OFFSET sqlc.arg(skip);
+
+-- name: CountStageRoles :one
+SELECT COUNT(*)::integer AS total
+FROM stage_role
+WHERE pattern ILIKE '%' || sqlc.arg(query)::text || '%';
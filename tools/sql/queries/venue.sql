@@ -1,14 +1,27 @@
 -- name: CreateVenue :one
 INSERT INTO venue (
-    name
+    name,
+    city,
+    country,
+    latitude,
+    longitude
 ) VALUES (
-    $1
+    $1, $2, $3, $4, $5
 ) RETURNING *;
 
 -- name: GetVenue :one
 SELECT * FROM venue
 WHERE id = $1 LIMIT 1;
 
+-- name: GetVenueIDByUUID :one
+SELECT id FROM venue
+WHERE uuid = $1;
+
+-- name: LockVenue :one
+SELECT updated FROM venue
+WHERE id = $1
+FOR UPDATE;
+
 -- name: ListVenues :many
 SELECT * FROM venue
 ORDER BY name;
@@ -17,10 +30,52 @@ ORDER BY name;
 UPDATE venue
 SET
     name = $2,
+    city = $3,
+    country = $4,
+    latitude = $5,
+    longitude = $6,
     updated = now()
 WHERE id = $1
 RETURNING *;
 
 -- name: DeleteVenue :exec
 DELETE FROM venue
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
+
+-- name: SearchVenueCandidates :many
+SELECT id, name, city, 0::integer AS alias_id, ''::text AS alias,
+    similarity(name, sqlc.arg(query)::text)::real AS score
+FROM venue
+WHERE name % sqlc.arg(query)::text
+UNION ALL
+SELECT e.id, e.name, e.city, a.id AS alias_id, a.alias,
+    similarity(a.alias, sqlc.arg(query)::text)::real AS score
+FROM venue_alias a
+JOIN venue e ON e.id = a.venue
+WHERE a.alias % sqlc.arg(query)::text
+ORDER BY score DESC, name
+LIMIT sqlc.arg(max_results);
+
+-- name: ListVenuesPage :many
+SELECT id, uuid, created, updated, name, city, country, latitude, longitude FROM venue
+WHERE (name ILIKE '%' || sqlc.arg(query)::text || '%'
+    OR city ILIKE '%' || sqlc.arg(query)::text || '%')
+ORDER BY
+    CASE WHEN sqlc.arg(sort)::text = 'name' THEN name END,
+    CASE WHEN sqlc.arg(sort)::text = '-name' THEN name END DESC,
+    CASE WHEN sqlc.arg(sort)::text = 'city' THEN city END,
+    CASE WHEN sqlc.arg(sort)::text = '-city' THEN city END DESC,
+    CASE WHEN sqlc.arg(sort)::text = 'country' THEN country END,
+    CASE WHEN sqlc.arg(sort)::text = '-country' THEN country END DESC,
+    CASE WHEN sqlc.arg(sort)::text = 'created' THEN created END,
+    CASE WHEN sqlc.arg(sort)::text = '-created' THEN created END DESC,
+    CASE WHEN sqlc.arg(sort)::text = 'updated' THEN updated END,
+    CASE WHEN sqlc.arg(sort)::text = '-updated' THEN updated END DESC,
+    id
+LIMIT sqlc.narg(max_rows) OFFSET sqlc.arg(skip);
+
+-- name: CountVenues :one
+SELECT COUNT(*)::integer AS total
+FROM venue
+WHERE (name ILIKE '%' || sqlc.arg(query)::text || '%'
+    OR city ILIKE '%' || sqlc.arg(query)::text || '%');
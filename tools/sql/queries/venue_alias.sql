@@ -11,6 +11,15 @@ RETURNING id, uuid, venue, created, updated, alias;
 SELECT id, uuid, venue, created, updated, alias FROM venue_alias
 WHERE id = $1;
 
+-- name: GetVenueAliasIDByUUID :one
+SELECT id FROM venue_alias
+WHERE uuid = $1;
+
+-- name: LockVenueAlias :one
+SELECT updated FROM venue_alias
+WHERE id = $1
+FOR UPDATE;
+
 -- name: UpdateVenueAlias :one
 UPDATE venue_alias
 SET venue = $1, alias = $2, updated = NOW()
@@ -19,4 +28,22 @@ RETURNING id, uuid, venue, created, updated, alias;
 
 -- name: DeleteVenueAlias :exec
 DELETE FROM venue_alias
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
+
+-- name: ListVenueAliasesPage :many
+SELECT id, uuid, venue, created, updated, alias FROM venue_alias
+WHERE alias ILIKE '%' || sqlc.arg(query)::text || '%'
+ORDER BY
+    CASE WHEN sqlc.arg(sort)::text = 'alias' THEN alias END,
+    CASE WHEN sqlc.arg(sort)::text = '-alias' THEN alias END DESC,
+    CASE WHEN sqlc.arg(sort)::text = 'created' THEN created END,
+    CASE WHEN sqlc.arg(sort)::text = '-created' THEN created END DESC,
+    CASE WHEN sqlc.arg(sort)::text = 'updated' THEN updated END,
+    CASE WHEN sqlc.arg(sort)::text = '-updated' THEN updated END DESC,
+    id
+LIMIT sqlc.narg(max_rows) OFFSET sqlc.arg(skip);
+
+-- name: CountVenueAliases :one
+SELECT COUNT(*)::integer AS total
+FROM venue_alias
+WHERE alias ILIKE '%' || sqlc.arg(query)::text || '%';
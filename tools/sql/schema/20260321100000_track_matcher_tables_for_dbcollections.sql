@@ -0,0 +1,70 @@
+-- +goose Up
+-- Track the entity and alias tables used by the in-memory matcher indexes
+INSERT INTO dbcollections_meta (table_name)
+VALUES
+    ('performer'),
+    ('performer_alias'),
+    ('venue'),
+    ('venue_alias'),
+    ('promoter'),
+    ('promoter_alias'),
+    ('festival'),
+    ('festival_alias')
+ON CONFLICT (table_name) DO NOTHING;
+
+-- Attach triggers to tracked tables
+
+CREATE TRIGGER performer_modified
+AFTER INSERT OR UPDATE OR DELETE ON performer
+FOR EACH STATEMENT
+EXECUTE FUNCTION dbcollections_touch();
+
+CREATE TRIGGER performer_aliases_modified
+AFTER INSERT OR UPDATE OR DELETE ON performer_alias
+FOR EACH STATEMENT
+EXECUTE FUNCTION dbcollections_touch();
+
+CREATE TRIGGER venue_modified
+AFTER INSERT OR UPDATE OR DELETE ON venue
+FOR EACH STATEMENT
+EXECUTE FUNCTION dbcollections_touch();
+
+CREATE TRIGGER venue_aliases_modified
+AFTER INSERT OR UPDATE OR DELETE ON venue_alias
+FOR EACH STATEMENT
+EXECUTE FUNCTION dbcollections_touch();
+
+CREATE TRIGGER promoter_modified
+AFTER INSERT OR UPDATE OR DELETE ON promoter
+FOR EACH STATEMENT
+EXECUTE FUNCTION dbcollections_touch();
+
+CREATE TRIGGER promoter_aliases_modified
+AFTER INSERT OR UPDATE OR DELETE ON promoter_alias
+FOR EACH STATEMENT
+EXECUTE FUNCTION dbcollections_touch();
+
+CREATE TRIGGER festival_modified
+AFTER INSERT OR UPDATE OR DELETE ON festival
+FOR EACH STATEMENT
+EXECUTE FUNCTION dbcollections_touch();
+
+CREATE TRIGGER festival_aliases_modified
+AFTER INSERT OR UPDATE OR DELETE ON festival_alias
+FOR EACH STATEMENT
+EXECUTE FUNCTION dbcollections_touch();
+
+-- +goose Down
+-- Drop triggers
+DROP TRIGGER IF EXISTS performer_modified ON performer;
+DROP TRIGGER IF EXISTS performer_aliases_modified ON performer_alias;
+DROP TRIGGER IF EXISTS venue_modified ON venue;
+DROP TRIGGER IF EXISTS venue_aliases_modified ON venue_alias;
+DROP TRIGGER IF EXISTS promoter_modified ON promoter;
+DROP TRIGGER IF EXISTS promoter_aliases_modified ON promoter_alias;
+DROP TRIGGER IF EXISTS festival_modified ON festival;
+DROP TRIGGER IF EXISTS festival_aliases_modified ON festival_alias;
+
+-- Stop tracking the tables
+DELETE FROM dbcollections_meta
+WHERE table_name IN ('performer', 'performer_alias', 'venue', 'venue_alias', 'promoter', 'promoter_alias', 'festival', 'festival_alias');
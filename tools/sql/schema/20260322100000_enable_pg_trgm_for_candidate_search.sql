@@ -0,0 +1,33 @@
+-- +goose Up
+-- This migration enables the 'pg_trgm' extension, which provides trigram
+-- similarity functions and operators, and adds GIN indexes so the matchers
+-- can search for the nearest names and aliases directly in the database.
+CREATE EXTENSION IF NOT EXISTS "pg_trgm";
+
+CREATE INDEX IF NOT EXISTS performer_name_trgm_idx ON performer USING gin (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS performer_alias_alias_trgm_idx ON performer_alias USING gin (alias gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS venue_name_trgm_idx ON venue USING gin (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS venue_alias_alias_trgm_idx ON venue_alias USING gin (alias gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS promoter_name_trgm_idx ON promoter USING gin (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS promoter_alias_alias_trgm_idx ON promoter_alias USING gin (alias gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS festival_name_trgm_idx ON festival USING gin (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS festival_alias_alias_trgm_idx ON festival_alias USING gin (alias gin_trgm_ops);
+
+-- Allow the app user to call the trigram functions used by the candidate search queries.
+GRANT USAGE ON SCHEMA public TO "gc-app";
+GRANT EXECUTE ON FUNCTION similarity(text, text) TO "gc-app";
+GRANT EXECUTE ON FUNCTION show_limit() TO "gc-app";
+GRANT EXECUTE ON FUNCTION set_limit(real) TO "gc-app";
+
+-- +goose Down
+DROP INDEX IF EXISTS performer_name_trgm_idx;
+DROP INDEX IF EXISTS performer_alias_alias_trgm_idx;
+DROP INDEX IF EXISTS venue_name_trgm_idx;
+DROP INDEX IF EXISTS venue_alias_alias_trgm_idx;
+DROP INDEX IF EXISTS promoter_name_trgm_idx;
+DROP INDEX IF EXISTS promoter_alias_alias_trgm_idx;
+DROP INDEX IF EXISTS festival_name_trgm_idx;
+DROP INDEX IF EXISTS festival_alias_alias_trgm_idx;
+
+-- Note: This also removes the grants on the extension's functions.
+DROP EXTENSION IF EXISTS "pg_trgm";
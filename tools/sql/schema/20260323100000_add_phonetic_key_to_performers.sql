@@ -0,0 +1,12 @@
+-- +goose Up
+-- Store the Double Metaphone key of each performer name and alias so the
+-- performer matcher can find names that sound alike but are spelt differently.
+-- The key is calculated by the application whenever a name is written; rows
+-- that predate this migration keep an empty key, which the matcher fills in
+-- when it loads its index.
+ALTER TABLE performer ADD COLUMN IF NOT EXISTS phonetic text NOT NULL DEFAULT '';
+ALTER TABLE performer_alias ADD COLUMN IF NOT EXISTS phonetic text NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE performer_alias DROP COLUMN IF EXISTS phonetic;
+ALTER TABLE performer DROP COLUMN IF EXISTS phonetic;
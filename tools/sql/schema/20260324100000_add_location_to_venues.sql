@@ -0,0 +1,18 @@
+-- +goose Up
+-- Add the city, country and optional coordinates of each venue, so venues with
+-- the same name in different places can be told apart, and a default city for
+-- each image location whose directory pattern does not include one.
+ALTER TABLE venue ADD COLUMN IF NOT EXISTS city text NOT NULL DEFAULT '';
+ALTER TABLE venue ADD COLUMN IF NOT EXISTS country text NOT NULL DEFAULT '';
+ALTER TABLE venue ADD COLUMN IF NOT EXISTS latitude double precision;
+ALTER TABLE venue ADD COLUMN IF NOT EXISTS longitude double precision;
+
+ALTER TABLE image_location ADD COLUMN IF NOT EXISTS default_city text NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE image_location DROP COLUMN IF EXISTS default_city;
+
+ALTER TABLE venue DROP COLUMN IF EXISTS longitude;
+ALTER TABLE venue DROP COLUMN IF EXISTS latitude;
+ALTER TABLE venue DROP COLUMN IF EXISTS country;
+ALTER TABLE venue DROP COLUMN IF EXISTS city;
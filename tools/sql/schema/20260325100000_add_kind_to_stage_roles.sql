@@ -0,0 +1,25 @@
+-- +goose Up
+-- Record what each stage role pattern means, so the way performers shared the
+-- stage is kept rather than only being used to split their names. Patterns that
+-- clearly name their relationship are given the matching kind; everything else
+-- starts as support and can be corrected through the stage role API.
+ALTER TABLE stage_role ADD COLUMN IF NOT EXISTS kind text NOT NULL DEFAULT 'support';
+ALTER TABLE stage_role ADD CONSTRAINT stage_role_kind_check
+    CHECK (kind IN ('support', 'guest', 'b2b', 'in-conversation', 'featuring'));
+
+UPDATE stage_role SET kind = 'in-conversation' WHERE pattern ILIKE '%conversation%';
+UPDATE stage_role SET kind = 'b2b' WHERE pattern ILIKE '%b2b%' OR pattern ILIKE '%back to back%';
+UPDATE stage_role SET kind = 'featuring' WHERE pattern ILIKE '%feat%' OR pattern ILIKE '% ft%';
+UPDATE stage_role SET kind = 'guest' WHERE pattern ILIKE '%guest%' OR pattern ILIKE '% with %';
+
+-- The role a performer took in an event, NULL for headliners and solo performers.
+ALTER TABLE event_performer ADD COLUMN IF NOT EXISTS role text;
+ALTER TABLE event_performer ADD CONSTRAINT event_performer_role_check
+    CHECK (role IN ('support', 'guest', 'b2b', 'in-conversation', 'featuring'));
+
+-- +goose Down
+ALTER TABLE event_performer DROP CONSTRAINT IF EXISTS event_performer_role_check;
+ALTER TABLE event_performer DROP COLUMN IF EXISTS role;
+
+ALTER TABLE stage_role DROP CONSTRAINT IF EXISTS stage_role_kind_check;
+ALTER TABLE stage_role DROP COLUMN IF EXISTS kind;
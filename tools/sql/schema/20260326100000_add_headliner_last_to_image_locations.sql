@@ -0,0 +1,8 @@
+-- +goose Up
+-- Record whether the performers named in each image location's directories are
+-- listed with the headliner last rather than first, so their running order and
+-- headliner can be derived from the order of the list.
+ALTER TABLE image_location ADD COLUMN IF NOT EXISTS headliner_last boolean NOT NULL DEFAULT FALSE;
+
+-- +goose Down
+ALTER TABLE image_location DROP COLUMN IF EXISTS headliner_last;
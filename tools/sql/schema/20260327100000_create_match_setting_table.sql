@@ -0,0 +1,57 @@
+-- +goose Up
+-- Settings that tune how strictly names are matched, one row for each type of entity.
+-- The festival index shares the promoter row, as both are matched from the promoter list.
+-- An exact match on an entity's own name always has a confidence of 100, so the other
+-- confidences must rank below it in the order of the stages that find them.
+CREATE TABLE IF NOT EXISTS match_setting
+(
+    entity_type text NOT NULL,
+    alias_confidence integer NOT NULL DEFAULT 75,
+    fuzzy_name_confidence integer NOT NULL DEFAULT 50,
+    fuzzy_alias_confidence integer NOT NULL DEFAULT 25,
+    phonetic_confidence integer NOT NULL DEFAULT 20,
+    min_similarity double precision NOT NULL DEFAULT 0.85,
+    short_length integer NOT NULL DEFAULT 5,
+    short_max_edits integer NOT NULL DEFAULT 1,
+    max_edits integer NOT NULL DEFAULT 2,
+    created TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (entity_type),
+    CONSTRAINT match_setting_entity_type_check
+        CHECK (entity_type IN ('performer', 'venue', 'promoter')),
+    CONSTRAINT match_setting_confidence_check
+        CHECK (100 > alias_confidence
+           AND alias_confidence > fuzzy_name_confidence
+           AND fuzzy_name_confidence > fuzzy_alias_confidence
+           AND fuzzy_alias_confidence > phonetic_confidence
+           AND phonetic_confidence > 0),
+    CONSTRAINT match_setting_similarity_check
+        CHECK (min_similarity > 0 AND min_similarity <= 1),
+    CONSTRAINT match_setting_edits_check
+        CHECK (short_length >= 0 AND short_max_edits >= 0 AND max_edits >= short_max_edits)
+);
+
+INSERT INTO match_setting (entity_type)
+VALUES
+    ('performer'),
+    ('venue'),
+    ('promoter')
+ON CONFLICT (entity_type) DO NOTHING;
+
+-- Track the table so the matcher indexes pick up changed settings
+INSERT INTO dbcollections_meta (table_name)
+VALUES ('match_setting')
+ON CONFLICT (table_name) DO NOTHING;
+
+CREATE TRIGGER match_setting_modified
+AFTER INSERT OR UPDATE OR DELETE ON match_setting
+FOR EACH STATEMENT
+EXECUTE FUNCTION dbcollections_touch();
+
+-- The rows are fixed, so the application only reads and updates them.
+GRANT SELECT, UPDATE ON match_setting TO "gc-app";
+
+-- +goose Down
+DROP TRIGGER IF EXISTS match_setting_modified ON match_setting;
+DELETE FROM dbcollections_meta WHERE table_name = 'match_setting';
+DROP TABLE IF EXISTS match_setting;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- Give image locations the stable UUID every other table has, so they can be referred
+-- to from outside the database like any other resource.
+ALTER TABLE image_location ADD COLUMN IF NOT EXISTS uuid UUID NOT NULL DEFAULT gen_random_uuid() UNIQUE;
+
+-- +goose Down
+ALTER TABLE image_location DROP COLUMN IF EXISTS uuid;
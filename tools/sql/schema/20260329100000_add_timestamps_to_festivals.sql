@@ -0,0 +1,9 @@
+-- +goose Up
+-- Record when each festival was created and last updated, as every other table does, so
+-- festivals can be sorted by them and edits can be checked against the version last read.
+ALTER TABLE festival ADD COLUMN IF NOT EXISTS created timestamp without time zone NOT NULL DEFAULT now();
+ALTER TABLE festival ADD COLUMN IF NOT EXISTS updated timestamp without time zone NOT NULL DEFAULT now();
+
+-- +goose Down
+ALTER TABLE festival DROP COLUMN IF EXISTS updated;
+ALTER TABLE festival DROP COLUMN IF EXISTS created;
@@ -0,0 +1,21 @@
+-- +goose Up
+-- The kind backfill in 20260325100000_add_kind_to_stage_roles ran each update over every
+-- matching pattern, so a pattern naming several relationships, such as "in conversation
+-- with", kept the last kind it matched rather than the most specific. Give those patterns
+-- the first kind they match. Only kinds a later update of the backfill could have set are
+-- replaced, so kinds corrected through the stage role API since are kept.
+UPDATE stage_role SET kind = 'in-conversation'
+    WHERE pattern ILIKE '%conversation%'
+    AND kind IN ('b2b', 'featuring', 'guest');
+UPDATE stage_role SET kind = 'b2b'
+    WHERE (pattern ILIKE '%b2b%' OR pattern ILIKE '%back to back%')
+    AND pattern NOT ILIKE '%conversation%'
+    AND kind IN ('featuring', 'guest');
+UPDATE stage_role SET kind = 'featuring'
+    WHERE (pattern ILIKE '%feat%' OR pattern ILIKE '% ft%')
+    AND pattern NOT ILIKE '%conversation%'
+    AND pattern NOT ILIKE '%b2b%' AND pattern NOT ILIKE '%back to back%'
+    AND kind = 'guest';
+
+-- +goose Down
+-- The kinds replaced cannot be told apart from those set through the API, so are left.
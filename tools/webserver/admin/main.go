@@ -10,6 +10,9 @@ import (
 	"github.com/66james99/gig-calendar/internal/apiHandler"
 	"github.com/66james99/gig-calendar/internal/database"
 	"github.com/66james99/gig-calendar/internal/dbcollection"
+	"github.com/66james99/gig-calendar/internal/metadata"
+	"github.com/66james99/gig-calendar/internal/metadata/images"
+	"github.com/66james99/gig-calendar/internal/metadata/performers"
 	"github.com/joho/godotenv"
 	"github.com/labstack/echo/v5"
 	"github.com/labstack/echo/v5/middleware"
@@ -18,6 +21,11 @@ import (
 
 func main() {
 	devMode := flag.Bool("dev", false, "Run the server in development mode")
+	requireIfMatch := flag.Bool("require-if-match", false, "Refuse updates and deletes that do not send If-Match")
+	historyVenue := flag.Int("history-venue", metadata.DefaultHistoryBoost.Venue, "Boost for a performer who has played the matched venue before")
+	historyBill := flag.Int("history-bill", metadata.DefaultHistoryBoost.Bill, "Boost for a performer who has shared a bill with another matched performer")
+	historyPromoter := flag.Int("history-promoter", metadata.DefaultHistoryBoost.Promoter, "Boost for a venue that has hosted events by a matched promoter")
+	historyMax := flag.Int("history-max", metadata.DefaultHistoryBoost.Max, "Limit on the total history boost given to a single candidate")
 	flag.Parse()
 
 	// Load environment variables from a .env file if it exists.
@@ -44,8 +52,27 @@ func main() {
 		return
 	}
 
+	stageRoles, err := performers.NewStageRoleMap(context.Background(), queries)
+	if err != nil {
+		fmt.Printf("Error creating StageRoles map: %v\n", err)
+		return
+	}
+
+	indexes, err := images.NewMatchIndexes(context.Background(), queries)
+	if err != nil {
+		fmt.Printf("Error creating match indexes: %v\n", err)
+		return
+	}
+
 	// Create the api handler
-	handler := apiHandler.New(queries, patternsArray)
+	handler := apiHandler.New(db, queries, patternsArray, stageRoles, indexes)
+	if *requireIfMatch {
+		handler.RequireIfMatch()
+	}
+	history := metadata.HistoryBoost{Venue: *historyVenue, Bill: *historyBill, Promoter: *historyPromoter, Max: *historyMax}
+	if err := handler.SetHistoryBoost(history); err != nil {
+		log.Fatalf("Invalid history boost: %v", err)
+	}
 
 	// Create a new Echo instance.
 	e := echo.New()
@@ -66,8 +93,9 @@ func main() {
 	e.Use(middleware.Recover()) // Recover from panics to prevent crashes.
 	// Configure CORS to allow requests from a frontend application.
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
-		AllowOrigins: []string{"*"}, // For development. In production, lock this down to your frontend's domain.
-		AllowMethods: []string{http.MethodGet, http.MethodPut, http.MethodPost, http.MethodDelete, http.MethodOptions},
+		AllowOrigins:  []string{"*"}, // For development. In production, lock this down to your frontend's domain.
+		AllowMethods:  []string{http.MethodGet, http.MethodPut, http.MethodPost, http.MethodDelete, http.MethodOptions},
+		ExposeHeaders: []string{"X-Total-Count", "ETag"}, // Lets the frontend read the row count of paginated lists and the version of a row.
 	}))
 
 	const apiVersion = "v1"
@@ -85,19 +113,43 @@ func main() {
 	}
 
 	// Register Routes
+	apiGroup.GET("/search", handler.Search)
+
+	reg("/events", handler.CreateEvent, handler.ListEvents, handler.GetEvent, handler.UpdateEvent, handler.DeleteEvent)
+	apiGroup.GET("/events/:id/performers", handler.ListEventPerformers)
+	apiGroup.POST("/events/:id/performers", handler.AddEventPerformer)
+	apiGroup.PUT("/events/:id/performers/order", handler.ReorderEventPerformers)
+	apiGroup.PUT("/events/:id/performers/:performer", handler.UpdateEventPerformer)
+	apiGroup.DELETE("/events/:id/performers/:performer", handler.RemoveEventPerformer)
+
 	reg("/image_locations", handler.CreateImageLocation, handler.ListImageLocations, handler.GetImageLocation, handler.UpdateImageLocation, handler.DeleteImageLocation)
 	apiGroup.GET("/image_locations/:id/preview_scan", handler.PreviewImageLocationScan)
 
 	reg("/venues", handler.CreateVenue, handler.ListVenues, handler.GetVenue, handler.UpdateVenue, handler.DeleteVenue)
+	apiGroup.POST("/venues/:id/merge", handler.MergeVenue)
 	reg("/venue_aliases", handler.CreateVenueAlias, handler.ListVenueAliases, handler.GetVenueAlias, handler.UpdateVenueAlias, handler.DeleteVenueAlias)
 	reg("/promoters", handler.CreatePromoter, handler.ListPromoters, handler.GetPromoter, handler.UpdatePromoter, handler.DeletePromoter)
+	apiGroup.POST("/promoters/:id/merge", handler.MergePromoter)
 	reg("/promoter_aliases", handler.CreatePromoterAlias, handler.ListPromoterAliases, handler.GetPromoterAlias, handler.UpdatePromoterAlias, handler.DeletePromoterAlias)
 	reg("/performers", handler.CreatePerformer, handler.ListPerformers, handler.GetPerformer, handler.UpdatePerformer, handler.DeletePerformer)
+	apiGroup.POST("/performers/:id/merge", handler.MergePerformer)
 	reg("/performer_aliases", handler.CreatePerformerAlias, handler.ListPerformerAliases, handler.GetPerformerAlias, handler.UpdatePerformerAlias, handler.DeletePerformerAlias)
 	reg("/festivals", handler.CreateFestival, handler.ListFestivals, handler.GetFestival, handler.UpdateFestival, handler.DeleteFestival)
+	apiGroup.POST("/festivals/:id/merge", handler.MergeFestival)
+	apiGroup.GET("/festivals/:id/venues", handler.ListFestivalVenues)
+	apiGroup.POST("/festivals/:id/venues", handler.AddFestivalVenue)
+	apiGroup.PUT("/festivals/:id/venues/:venue", handler.UpdateFestivalVenue)
+	apiGroup.DELETE("/festivals/:id/venues/:venue", handler.RemoveFestivalVenue)
+	apiGroup.GET("/festivals/:id/promoters", handler.ListFestivalPromoters)
+	apiGroup.POST("/festivals/:id/promoters", handler.AddFestivalPromoter)
+	apiGroup.PUT("/festivals/:id/promoters/:promoter", handler.UpdateFestivalPromoter)
+	apiGroup.DELETE("/festivals/:id/promoters/:promoter", handler.RemoveFestivalPromoter)
 	reg("/festival_aliases", handler.CreateFestivalAlias, handler.ListFestivalAliases, handler.GetFestivalAlias, handler.UpdateFestivalAlias, handler.DeleteFestivalAlias)
 	reg("/event_types", handler.CreateEventType, handler.ListEventTypes, handler.GetEventType, handler.UpdateEventType, handler.DeleteEventType)
 	reg("/stage_roles", handler.CreateStageRole, handler.ListStageRoles, handler.GetStageRole, handler.UpdateStageRole, handler.DeleteStageRole)
+	apiGroup.GET("/match_settings", handler.ListMatchSettings)
+	apiGroup.GET("/match_settings/:entity", handler.GetMatchSetting)
+	apiGroup.PUT("/match_settings/:entity", handler.UpdateMatchSetting)
 
 	// Serve static frontend files.
 	e.Static("/", "../docs/content/admin.gig-calendar.com")